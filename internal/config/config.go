@@ -0,0 +1,401 @@
+// Package config loads, validates and exposes the AppDrop API configuration.
+// Values are resolved in the following order (later sources win):
+//  1. Built-in defaults (see Default)
+//  2. An optional YAML or TOML file named by the CONFIG_FILE environment variable
+//  3. Variables from a .env file in the working directory
+//  4. Process environment variables
+//
+// Everything is validated once at startup so misconfiguration fails fast
+// with a readable list of problems instead of a panic deep in the app.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the root configuration for the AppDrop API server.
+type Config struct {
+	// Server holds HTTP listener settings
+	Server ServerConfig `yaml:"server" toml:"server"`
+	// Database holds PostgreSQL connection and pool settings
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	// CORS holds cross-origin request settings
+	CORS CORSConfig `yaml:"cors" toml:"cors"`
+	// Health holds liveness/readiness probe settings
+	Health HealthConfig `yaml:"health" toml:"health"`
+	// Features holds optional behaviour toggles
+	Features FeatureConfig `yaml:"features" toml:"features"`
+	// Jobs holds background job settings
+	Jobs JobsConfig `yaml:"jobs" toml:"jobs"`
+	// Preview configures shareable preview links for unpublished pages
+	Preview PreviewConfig `yaml:"preview" toml:"preview"`
+	// Storage configures where uploaded media assets are kept
+	Storage StorageConfig `yaml:"storage" toml:"storage"`
+	// DeepLinks identifies the mobile apps that open app links
+	DeepLinks DeepLinksConfig `yaml:"deep_links" toml:"deep_links"`
+}
+
+// ServerConfig configures the HTTP server.
+type ServerConfig struct {
+	// Port is the TCP port the server listens on (env: PORT, default: 8080)
+	Port int `yaml:"port" toml:"port"`
+	// ReadTimeout bounds reading an entire request (env: HTTP_READ_TIMEOUT, default: 15s)
+	ReadTimeout time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	// WriteTimeout bounds writing a response (env: HTTP_WRITE_TIMEOUT, default: 30s)
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	// IdleTimeout bounds keep-alive connections (env: HTTP_IDLE_TIMEOUT, default: 60s)
+	IdleTimeout time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// ShutdownTimeout bounds graceful shutdown (env: SHUTDOWN_TIMEOUT, default: 15s)
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// RequestTimeout is the deadline for handling one request, including its database
+	// queries (env: REQUEST_TIMEOUT, default: 10s)
+	RequestTimeout time.Duration `yaml:"request_timeout" toml:"request_timeout"`
+	// MaxBodyBytes is the largest accepted JSON request body (env: MAX_BODY_BYTES, default: 1048576)
+	MaxBodyBytes int64 `yaml:"max_body_bytes" toml:"max_body_bytes"`
+	// DrainDelay is how long readiness reports failing before the listener closes on shutdown,
+	// giving load balancers time to stop routing traffic (env: SHUTDOWN_DRAIN_DELAY, default: 0s)
+	DrainDelay time.Duration `yaml:"drain_delay" toml:"drain_delay"`
+}
+
+// DatabaseConfig configures the PostgreSQL connection pool.
+type DatabaseConfig struct {
+	// URL is the PostgreSQL connection string (env: DATABASE_URL, required)
+	URL string `yaml:"url" toml:"url"`
+	// MaxConns is the maximum pool size (env: DB_MAX_CONNS, default: 10)
+	MaxConns int32 `yaml:"max_conns" toml:"max_conns"`
+	// MinConns is the number of idle connections kept open (env: DB_MIN_CONNS, default: 0)
+	MinConns int32 `yaml:"min_conns" toml:"min_conns"`
+	// MaxConnLifetime recycles connections after this age (env: DB_MAX_CONN_LIFETIME, default: 1h)
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime"`
+	// MaxConnIdleTime closes idle connections after this duration (env: DB_MAX_CONN_IDLE_TIME, default: 30m)
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time"`
+	// ConnectTimeout bounds the initial connection and ping (env: DB_CONNECT_TIMEOUT, default: 10s)
+	ConnectTimeout time.Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+}
+
+// CORSConfig configures cross-origin resource sharing.
+type CORSConfig struct {
+	// AllowedOrigins lists origins allowed to call the API; "*" allows any
+	// (env: CORS_ALLOWED_ORIGINS as a comma-separated list, default: none)
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins"`
+}
+
+// HealthConfig configures the health probes.
+type HealthConfig struct {
+	// CheckTimeout bounds each readiness dependency check (env: HEALTH_CHECK_TIMEOUT, default: 2s)
+	CheckTimeout time.Duration `yaml:"check_timeout" toml:"check_timeout"`
+}
+
+// FeatureConfig holds optional behaviour toggles.
+type FeatureConfig struct {
+	// RequestLogging enables the request logging middleware (env: FEATURE_REQUEST_LOGGING, default: true)
+	RequestLogging bool `yaml:"request_logging" toml:"request_logging"`
+	// BlockLinkedPageDeletes refuses to delete pages that other pages' widgets or components
+	// link to (env: FEATURE_BLOCK_LINKED_PAGE_DELETES, default: false)
+	BlockLinkedPageDeletes bool `yaml:"block_linked_page_deletes" toml:"block_linked_page_deletes"`
+}
+
+// JobsConfig configures background jobs.
+type JobsConfig struct {
+	// PublishInterval is how often drafts whose publish_at has passed are published
+	// (env: PUBLISH_CHECK_INTERVAL, default: 30s)
+	PublishInterval time.Duration `yaml:"publish_interval" toml:"publish_interval"`
+	// VariantInterval is how often new assets are checked for resized variants to generate;
+	// uploads also wake the job (env: ASSET_VARIANT_INTERVAL, default: 1m)
+	VariantInterval time.Duration `yaml:"variant_interval" toml:"variant_interval"`
+}
+
+// PreviewConfig configures preview links.
+type PreviewConfig struct {
+	// Secret signs preview link tokens; at least 32 characters. When empty a random
+	// secret is generated at startup, so links stop working on restart (env: PREVIEW_SECRET)
+	Secret string `yaml:"secret" toml:"secret"`
+	// DefaultTTL is how long a preview link is valid when none is requested
+	// (env: PREVIEW_LINK_TTL, default: 24h)
+	DefaultTTL time.Duration `yaml:"default_ttl" toml:"default_ttl"`
+	// MaxTTL is the longest validity a preview link can be issued with
+	// (env: PREVIEW_LINK_MAX_TTL, default: 168h)
+	MaxTTL time.Duration `yaml:"max_ttl" toml:"max_ttl"`
+}
+
+// StorageConfig configures media asset storage.
+type StorageConfig struct {
+	// Dir is the directory uploaded assets are stored in; it is created if missing
+	// (env: STORAGE_DIR, default: data/assets)
+	Dir string `yaml:"dir" toml:"dir"`
+	// MaxUploadBytes is the largest accepted asset upload (env: MAX_UPLOAD_BYTES, default: 10485760)
+	MaxUploadBytes int64 `yaml:"max_upload_bytes" toml:"max_upload_bytes"`
+	// VariantWidths are the widths, in pixels, of the resized variants generated for each
+	// uploaded image; widths not smaller than the image are skipped
+	// (env: ASSET_VARIANT_WIDTHS as a comma-separated list, default: 320,640,1080)
+	VariantWidths []int `yaml:"variant_widths" toml:"variant_widths"`
+}
+
+// DeepLinksConfig identifies the mobile apps listed in the generated
+// apple-app-site-association and assetlinks.json files.
+type DeepLinksConfig struct {
+	// AppleTeamID is the Apple developer team ID prefixing the iOS app IDs
+	// (env: APPLE_TEAM_ID, default: none, which disables apple-app-site-association)
+	AppleTeamID string `yaml:"apple_team_id" toml:"apple_team_id"`
+	// AppleBundleIDs are the bundle IDs of the iOS apps that open app links
+	// (env: APPLE_BUNDLE_IDS as a comma-separated list, default: none)
+	AppleBundleIDs []string `yaml:"apple_bundle_ids" toml:"apple_bundle_ids"`
+	// AndroidPackage is the package name of the Android app that opens app links
+	// (env: ANDROID_PACKAGE_NAME, default: none, which disables assetlinks.json)
+	AndroidPackage string `yaml:"android_package" toml:"android_package"`
+	// AndroidFingerprints are the SHA-256 fingerprints of the Android app's signing
+	// certificates, as colon-separated uppercase hex pairs
+	// (env: ANDROID_CERT_FINGERPRINTS as a comma-separated list, default: none)
+	AndroidFingerprints []string `yaml:"android_fingerprints" toml:"android_fingerprints"`
+}
+
+// AppleAppIDs returns the iOS app IDs ("TEAMID.bundle.id") that open app links.
+func (d DeepLinksConfig) AppleAppIDs() []string {
+	ids := make([]string, 0, len(d.AppleBundleIDs))
+	for _, bundle := range d.AppleBundleIDs {
+		ids = append(ids, d.AppleTeamID+"."+bundle)
+	}
+	return ids
+}
+
+// Default returns the configuration used when no file or environment overrides are set.
+// DATABASE_URL has no default and must always be provided.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+			RequestTimeout:  10 * time.Second,
+			MaxBodyBytes:    1 << 20,
+		},
+		Database: DatabaseConfig{
+			MaxConns:        10,
+			MinConns:        0,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: 30 * time.Minute,
+			ConnectTimeout:  10 * time.Second,
+		},
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+		},
+		Features: FeatureConfig{
+			RequestLogging: true,
+		},
+		Jobs: JobsConfig{
+			PublishInterval: 30 * time.Second,
+			VariantInterval: time.Minute,
+		},
+		Preview: PreviewConfig{
+			DefaultTTL: 24 * time.Hour,
+			MaxTTL:     7 * 24 * time.Hour,
+		},
+		Storage: StorageConfig{
+			Dir:            "data/assets",
+			MaxUploadBytes: 10 << 20,
+			VariantWidths:  []int{320, 640, 1080},
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the optional CONFIG_FILE, .env and the environment,
+// then validates it. The returned error lists every problem found, not just the first.
+func Load() (*Config, error) {
+	// A missing .env file is fine - variables may come from the real environment
+	godotenv.Load()
+
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	var problems []string
+	problems = append(problems, applyEnv(cfg)...)
+	problems = append(problems, cfg.validate()...)
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// Addr returns the listen address for the HTTP server, e.g. ":8080".
+func (c *Config) Addr() string {
+	return fmt.Sprintf(":%d", c.Server.Port)
+}
+
+// ValidationError reports every configuration problem found during Load.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// loadFile reads a configuration file over the defaults in cfg: TOML if the path ends in
+// .toml, YAML otherwise. Both use the same key names.
+// Unknown keys are rejected so typos don't silently fall back to defaults.
+func loadFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		md, err := toml.NewDecoder(f).Decode(cfg)
+		if err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("config file %s: unknown key %s", path, undecoded[0])
+		}
+		return nil
+	}
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+var (
+	// appleTeamIDPattern matches an Apple developer team ID
+	appleTeamIDPattern = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+	// bundleIDPattern matches an iOS bundle ID
+	bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+	// androidPackagePattern matches an Android package name
+	androidPackagePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)+$`)
+	// fingerprintPattern matches a SHA-256 certificate fingerprint
+	fingerprintPattern = regexp.MustCompile(`^([0-9A-F]{2}:){31}[0-9A-F]{2}$`)
+)
+
+// validate checks value ranges and required settings, returning one message per problem.
+func (c *Config) validate() []string {
+	var problems []string
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("PORT must be between 1 and 65535 (got %d)", c.Server.Port))
+	}
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		{"REQUEST_TIMEOUT", c.Server.RequestTimeout},
+		{"DB_MAX_CONN_LIFETIME", c.Database.MaxConnLifetime},
+		{"DB_MAX_CONN_IDLE_TIME", c.Database.MaxConnIdleTime},
+		{"DB_CONNECT_TIMEOUT", c.Database.ConnectTimeout},
+		{"HEALTH_CHECK_TIMEOUT", c.Health.CheckTimeout},
+		{"PUBLISH_CHECK_INTERVAL", c.Jobs.PublishInterval},
+		{"ASSET_VARIANT_INTERVAL", c.Jobs.VariantInterval},
+		{"PREVIEW_LINK_TTL", c.Preview.DefaultTTL},
+		{"PREVIEW_LINK_MAX_TTL", c.Preview.MaxTTL},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			problems = append(problems, fmt.Sprintf("%s must be a positive duration (got %s)", d.name, d.value))
+		}
+	}
+	if c.Server.MaxBodyBytes < 1 {
+		problems = append(problems, fmt.Sprintf("MAX_BODY_BYTES must be at least 1 (got %d)", c.Server.MaxBodyBytes))
+	}
+	if c.Server.DrainDelay < 0 {
+		problems = append(problems, fmt.Sprintf("SHUTDOWN_DRAIN_DELAY must not be negative (got %s)", c.Server.DrainDelay))
+	}
+
+	if c.Database.URL == "" {
+		problems = append(problems, "DATABASE_URL is required")
+	} else if !strings.HasPrefix(c.Database.URL, "postgres://") && !strings.HasPrefix(c.Database.URL, "postgresql://") {
+		problems = append(problems, "DATABASE_URL must start with postgres:// or postgresql://")
+	}
+	if c.Database.MaxConns < 1 {
+		problems = append(problems, fmt.Sprintf("DB_MAX_CONNS must be at least 1 (got %d)", c.Database.MaxConns))
+	}
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		problems = append(problems, fmt.Sprintf("DB_MIN_CONNS must be between 0 and DB_MAX_CONNS (got %d)", c.Database.MinConns))
+	}
+
+	if c.Preview.DefaultTTL > c.Preview.MaxTTL {
+		problems = append(problems, fmt.Sprintf("PREVIEW_LINK_TTL must not exceed PREVIEW_LINK_MAX_TTL (got %s > %s)", c.Preview.DefaultTTL, c.Preview.MaxTTL))
+	}
+	if c.Preview.Secret != "" && len(c.Preview.Secret) < 32 {
+		problems = append(problems, "PREVIEW_SECRET must be at least 32 characters")
+	}
+
+	if strings.TrimSpace(c.Storage.Dir) == "" {
+		problems = append(problems, "STORAGE_DIR must not be empty")
+	}
+	if c.Storage.MaxUploadBytes < 1 {
+		problems = append(problems, fmt.Sprintf("MAX_UPLOAD_BYTES must be at least 1 (got %d)", c.Storage.MaxUploadBytes))
+	}
+	seenWidths := map[int]bool{}
+	for _, w := range c.Storage.VariantWidths {
+		if w < 16 || w > 4096 {
+			problems = append(problems, fmt.Sprintf("ASSET_VARIANT_WIDTHS entries must be between 16 and 4096 (got %d)", w))
+		} else if seenWidths[w] {
+			problems = append(problems, fmt.Sprintf("ASSET_VARIANT_WIDTHS lists %d more than once", w))
+		}
+		seenWidths[w] = true
+	}
+
+	links := c.DeepLinks
+	if links.AppleTeamID != "" && !appleTeamIDPattern.MatchString(links.AppleTeamID) {
+		problems = append(problems, fmt.Sprintf("APPLE_TEAM_ID must be 10 uppercase letters or digits (got %q)", links.AppleTeamID))
+	}
+	if links.AppleTeamID != "" && len(links.AppleBundleIDs) == 0 {
+		problems = append(problems, "APPLE_BUNDLE_IDS is required when APPLE_TEAM_ID is set")
+	}
+	if links.AppleTeamID == "" && len(links.AppleBundleIDs) > 0 {
+		problems = append(problems, "APPLE_TEAM_ID is required when APPLE_BUNDLE_IDS is set")
+	}
+	for _, bundle := range links.AppleBundleIDs {
+		if !bundleIDPattern.MatchString(bundle) {
+			problems = append(problems, fmt.Sprintf("APPLE_BUNDLE_IDS entry %q must be a reverse-DNS bundle ID such as com.example.app", bundle))
+		}
+	}
+	if links.AndroidPackage != "" && !androidPackagePattern.MatchString(links.AndroidPackage) {
+		problems = append(problems, fmt.Sprintf("ANDROID_PACKAGE_NAME must be a package name such as com.example.app (got %q)", links.AndroidPackage))
+	}
+	if links.AndroidPackage != "" && len(links.AndroidFingerprints) == 0 {
+		problems = append(problems, "ANDROID_CERT_FINGERPRINTS is required when ANDROID_PACKAGE_NAME is set")
+	}
+	if links.AndroidPackage == "" && len(links.AndroidFingerprints) > 0 {
+		problems = append(problems, "ANDROID_PACKAGE_NAME is required when ANDROID_CERT_FINGERPRINTS is set")
+	}
+	for _, fp := range links.AndroidFingerprints {
+		if !fingerprintPattern.MatchString(fp) {
+			problems = append(problems, fmt.Sprintf("ANDROID_CERT_FINGERPRINTS entry %q must be 32 colon-separated uppercase hex pairs", fp))
+		}
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			problems = append(problems, fmt.Sprintf("CORS_ALLOWED_ORIGINS entry %q must be \"*\" or start with http:// or https://", origin))
+		}
+	}
+
+	return problems
+}
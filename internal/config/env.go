@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv overrides cfg with any configuration set in the environment.
+// Values that cannot be parsed are reported as problems rather than ignored.
+func applyEnv(cfg *Config) []string {
+	var problems []string
+
+	envInt(&problems, "PORT", &cfg.Server.Port)
+	envDuration(&problems, "HTTP_READ_TIMEOUT", &cfg.Server.ReadTimeout)
+	envDuration(&problems, "HTTP_WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+	envDuration(&problems, "HTTP_IDLE_TIMEOUT", &cfg.Server.IdleTimeout)
+	envDuration(&problems, "SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	envDuration(&problems, "SHUTDOWN_DRAIN_DELAY", &cfg.Server.DrainDelay)
+	envDuration(&problems, "REQUEST_TIMEOUT", &cfg.Server.RequestTimeout)
+	envInt64(&problems, "MAX_BODY_BYTES", &cfg.Server.MaxBodyBytes)
+
+	envString("DATABASE_URL", &cfg.Database.URL)
+	envInt32(&problems, "DB_MAX_CONNS", &cfg.Database.MaxConns)
+	envInt32(&problems, "DB_MIN_CONNS", &cfg.Database.MinConns)
+	envDuration(&problems, "DB_MAX_CONN_LIFETIME", &cfg.Database.MaxConnLifetime)
+	envDuration(&problems, "DB_MAX_CONN_IDLE_TIME", &cfg.Database.MaxConnIdleTime)
+	envDuration(&problems, "DB_CONNECT_TIMEOUT", &cfg.Database.ConnectTimeout)
+
+	envDuration(&problems, "HEALTH_CHECK_TIMEOUT", &cfg.Health.CheckTimeout)
+
+	envList("CORS_ALLOWED_ORIGINS", &cfg.CORS.AllowedOrigins)
+
+	envBool(&problems, "FEATURE_REQUEST_LOGGING", &cfg.Features.RequestLogging)
+	envBool(&problems, "FEATURE_BLOCK_LINKED_PAGE_DELETES", &cfg.Features.BlockLinkedPageDeletes)
+
+	envDuration(&problems, "PUBLISH_CHECK_INTERVAL", &cfg.Jobs.PublishInterval)
+	envDuration(&problems, "ASSET_VARIANT_INTERVAL", &cfg.Jobs.VariantInterval)
+
+	envString("PREVIEW_SECRET", &cfg.Preview.Secret)
+	envDuration(&problems, "PREVIEW_LINK_TTL", &cfg.Preview.DefaultTTL)
+	envDuration(&problems, "PREVIEW_LINK_MAX_TTL", &cfg.Preview.MaxTTL)
+
+	envString("STORAGE_DIR", &cfg.Storage.Dir)
+	envInt64(&problems, "MAX_UPLOAD_BYTES", &cfg.Storage.MaxUploadBytes)
+	envIntList(&problems, "ASSET_VARIANT_WIDTHS", &cfg.Storage.VariantWidths)
+
+	envString("APPLE_TEAM_ID", &cfg.DeepLinks.AppleTeamID)
+	envList("APPLE_BUNDLE_IDS", &cfg.DeepLinks.AppleBundleIDs)
+	envString("ANDROID_PACKAGE_NAME", &cfg.DeepLinks.AndroidPackage)
+	envList("ANDROID_CERT_FINGERPRINTS", &cfg.DeepLinks.AndroidFingerprints)
+
+	return problems
+}
+
+// envString sets dst from the named variable if it is set and non-empty.
+func envString(name string, dst *string) {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		*dst = v
+	}
+}
+
+// envList sets dst from a comma-separated variable, dropping empty entries.
+func envList(name string, dst *[]string) {
+	v := os.Getenv(name)
+	if strings.TrimSpace(v) == "" {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+// envIntList sets dst from a comma-separated list of integers, dropping empty entries.
+// Set the variable to "none" for an empty list.
+func envIntList(problems *[]string, name string, dst *[]int) {
+	var items []string
+	envList(name, &items)
+	if items == nil {
+		return
+	}
+	if len(items) == 1 && items[0] == "none" {
+		*dst = []int{}
+		return
+	}
+	list := make([]int, 0, len(items))
+	for _, item := range items {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s must be a comma-separated list of integers (got %q)", name, item))
+			return
+		}
+		list = append(list, n)
+	}
+	*dst = list
+}
+
+func envInt(problems *[]string, name string, dst *int) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("%s must be an integer (got %q)", name, v))
+		return
+	}
+	*dst = n
+}
+
+func envInt32(problems *[]string, name string, dst *int32) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("%s must be an integer (got %q)", name, v))
+		return
+	}
+	*dst = int32(n)
+}
+
+func envInt64(problems *[]string, name string, dst *int64) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("%s must be an integer (got %q)", name, v))
+		return
+	}
+	*dst = n
+}
+
+func envBool(problems *[]string, name string, dst *bool) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("%s must be true or false (got %q)", name, v))
+		return
+	}
+	*dst = b
+}
+
+func envDuration(problems *[]string, name string, dst *time.Duration) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("%s must be a duration such as 30s or 5m (got %q)", name, v))
+		return
+	}
+	*dst = d
+}
@@ -5,7 +5,8 @@ package db
 import (
 	"context"
 	"fmt"
-	"os"
+
+	"appdrop-api/internal/config"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -15,23 +16,42 @@ import (
 // across the entire application for all database operations.
 var Pool *pgxpool.Pool
 
-// ConnectDB initializes the PostgreSQL connection pool from the DATABASE_URL environment variable.
-// It creates a new connection pool, verifies connectivity with a ping,
-// and makes it available globally via the Pool variable.
-// Panics if connection fails or DATABASE_URL is not set.
-func ConnectDB() {
-	dbURL := os.Getenv("DATABASE_URL")
+// SchemaVersion is the migration version this build of the API expects.
+// It must be bumped whenever a new file is added to the migrations directory;
+// the readiness probe fails until the database has been migrated to it.
+const SchemaVersion = 18
+
+// ConnectDB initializes the PostgreSQL connection pool from the database configuration.
+// It applies pool sizing and lifetime settings, verifies connectivity with a ping
+// bounded by the configured connect timeout, and makes the pool available globally
+// via the Pool variable.
+// Returns an error if the URL cannot be parsed or the database is unreachable.
+func ConnectDB(cfg config.DatabaseConfig) error {
+	poolCfg, err := pgxpool.ParseConfig(cfg.URL)
+	if err != nil {
+		return fmt.Errorf("parse DATABASE_URL: %w", err)
+	}
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MinConns = cfg.MinConns
+	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolCfg.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
 
-	pool, err := pgxpool.New(context.Background(), dbURL)
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("create connection pool: %w", err)
 	}
 
-	err = pool.Ping(context.Background())
+	err = pool.Ping(ctx)
 	if err != nil {
-		panic(err)
+		pool.Close()
+		return fmt.Errorf("ping database: %w", err)
 	}
 
 	fmt.Println("Connected to Postgres")
 	Pool = pool
+	return nil
 }
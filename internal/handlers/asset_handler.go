@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// maxMultipartOverhead is how much larger than the file an upload request body may
+// be, leaving room for part headers and boundaries.
+const maxMultipartOverhead = 64 << 10
+
+// GetAssetsHandler handles GET /assets requests.
+// Returns every uploaded asset, newest first.
+// Status: 200 OK on success, 500 on database error
+func GetAssetsHandler(w http.ResponseWriter, r *http.Request) {
+	assets, err := services.GetAssets(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, assets)
+}
+
+// UploadAssetHandler handles POST /assets requests.
+// Accepts a multipart/form-data body with the image in the "file" field and records
+// its MIME type, dimensions, size and checksum. Other fields are ignored.
+// Status: 201 Created on success, 400 for a missing field or an unsupported image,
+// 413 if the file exceeds the upload limit, 415 for non-multipart bodies
+func UploadAssetHandler(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		utils.SendError(w, 415, "UNSUPPORTED_MEDIA_TYPE", "Content-Type must be multipart/form-data")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxUploadBytes+maxMultipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		utils.SendError(w, 400, "VALIDATION_ERROR", "Invalid multipart body")
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			utils.SendError(w, 400, "VALIDATION_ERROR", `multipart field "file" is required`)
+			return
+		}
+		if err != nil {
+			sendUploadError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		asset, err := services.UploadAsset(r.Context(), part.FileName(), part)
+		part.Close()
+		if err != nil {
+			sendUploadError(w, err)
+			return
+		}
+		utils.SendJSON(w, 201, asset)
+		return
+	}
+}
+
+// GetAssetHandler handles GET /assets/:id requests.
+// Returns the asset's metadata; the file itself is served by GetAssetContentHandler.
+// Status: 200 OK on success, 404 if asset not found
+func GetAssetHandler(w http.ResponseWriter, r *http.Request) {
+	asset, err := services.GetAsset(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 404, "NOT_FOUND", "Asset not found")
+		return
+	}
+
+	utils.SendJSON(w, 200, asset)
+}
+
+// GetAssetContentHandler handles GET /assets/:id/content requests.
+// Serves the stored file with its MIME type. An asset's file never changes, so the
+// response is cacheable indefinitely; the checksum is the ETag, and Range and
+// conditional requests are supported.
+// Status: 200 OK (206/304 for range and conditional requests), 404 if asset not found
+func GetAssetContentHandler(w http.ResponseWriter, r *http.Request) {
+	asset, file, err := services.OpenAsset(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "asset not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Asset not found")
+		case "asset file is missing":
+			utils.SendError(w, 404, "NOT_FOUND", "Asset file is missing from storage")
+		default:
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+	defer file.Close()
+
+	serveAssetFile(w, r, file, asset.MimeType, asset.SizeBytes, `"`+asset.Checksum+`"`, asset.CreatedAt)
+}
+
+// GetAssetVariantHandler handles GET /assets/:id/variants/:width requests.
+// Serves a resized variant of the asset's image, cacheable like the original.
+// Status: 200 OK (206/304 for range and conditional requests), 400 for a malformed width,
+// 404 if the asset has no variant of that width
+func GetAssetVariantHandler(w http.ResponseWriter, r *http.Request) {
+	width, err := strconv.Atoi(r.PathValue("width"))
+	if err != nil || width < 1 {
+		utils.SendError(w, 400, "VALIDATION_ERROR", "width must be a positive integer")
+		return
+	}
+
+	asset, variant, file, err := services.OpenAssetVariant(r.Context(), r.PathValue("id"), width)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "asset not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Asset not found")
+		case "asset variant not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Asset has no variant of this width")
+		case "asset file is missing":
+			utils.SendError(w, 404, "NOT_FOUND", "Asset file is missing from storage")
+		default:
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+	defer file.Close()
+
+	// Variant keys change whenever variants are regenerated
+	serveAssetFile(w, r, file, variant.MimeType, variant.SizeBytes, `"`+variant.StorageKey+`"`, asset.CreatedAt)
+}
+
+// RegenerateAssetVariantsHandler handles POST /assets/:id/variants requests.
+// Queues the asset's resized variants to be generated again, e.g. after the configured
+// widths changed; the current variants are served until they are replaced.
+// Status: 202 Accepted with the queued asset, 404 if asset not found
+func RegenerateAssetVariantsHandler(w http.ResponseWriter, r *http.Request) {
+	asset, err := services.RegenerateAssetVariants(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "asset not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Asset not found")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 202, asset)
+}
+
+// DeleteAssetHandler handles DELETE /assets/:id requests.
+// An asset referenced by a widget, component, experiment or product cannot be deleted.
+// Status: 200 OK on success, 404 if asset not found, 409 if still in use
+func DeleteAssetHandler(w http.ResponseWriter, r *http.Request) {
+	err := services.DeleteAsset(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "asset not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Asset not found")
+		case "asset is in use":
+			utils.SendError(w, 409, "CONFLICT", "Asset is used by one or more widgets, components, experiments or products")
+		default:
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Asset deleted"})
+}
+
+// serveAssetFile writes a stored asset file. Stored files never change, so responses are
+// cacheable indefinitely under etag; Range and conditional requests are supported when
+// the file is seekable.
+func serveAssetFile(w http.ResponseWriter, r *http.Request, file io.Reader, mimeType string, size int64, etag string, modTime time.Time) {
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", modTime, seeker)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	io.Copy(w, file)
+}
+
+// sendUploadError writes the response for an error reading or storing an upload.
+func sendUploadError(w http.ResponseWriter, err error) {
+	if utils.SendContextError(w, err) {
+		return
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || err.Error() == "file is too large" {
+		utils.SendError(w, 413, "PAYLOAD_TOO_LARGE", fmt.Sprintf("File must not exceed %d bytes", services.MaxUploadBytes))
+		return
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		utils.SendError(w, 400, "VALIDATION_ERROR", "Invalid multipart body")
+		return
+	}
+	utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+}
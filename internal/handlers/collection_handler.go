@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetCollectionsHandler handles GET /collections requests.
+// Returns every collection ordered by name, with its product IDs in display order.
+// Status: 200 OK on success, 500 on database error
+func GetCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	collections, err := services.GetCollections(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, collections)
+}
+
+// CreateCollectionHandler handles POST /collections requests.
+// Status: 201 Created on success, 409 if the name is taken, 400 for validation errors,
+// 413/415 for oversized or non-JSON bodies
+func CreateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.CollectionRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	collection, err := services.CreateCollection(r.Context(), req.ToCollection())
+	if err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 201, collection)
+}
+
+// GetCollectionHandler handles GET /collections/:id requests.
+// Status: 200 OK on success, 404 if collection not found
+func GetCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	collection, err := services.GetCollection(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 404, "NOT_FOUND", "Collection not found")
+		return
+	}
+
+	utils.SendJSON(w, 200, collection)
+}
+
+// GetCollectionProductsHandler handles GET /collections/:id/products requests.
+// Returns the collection's products in display order.
+// Status: 200 OK on success, 404 if collection not found
+func GetCollectionProductsHandler(w http.ResponseWriter, r *http.Request) {
+	products, err := services.GetCollectionProducts(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "collection not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Collection not found")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, products)
+}
+
+// UpdateCollectionHandler handles PUT /collections/:id requests.
+// Replaces the name, description and product list.
+// Status: 200 OK on success, 404 if collection not found, 409 if the name is taken,
+// 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.CollectionRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	collection, err := services.UpdateCollection(r.Context(), r.PathValue("id"), req.ToCollection())
+	if err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, collection)
+}
+
+// DeleteCollectionHandler handles DELETE /collections/:id requests.
+// The products are kept. A collection used by a product_grid cannot be deleted.
+// Status: 200 OK on success, 404 if collection not found, 409 if still in use
+func DeleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	err := services.DeleteCollection(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "collection not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Collection not found")
+		case "collection is in use":
+			utils.SendError(w, 409, "CONFLICT", "Collection is used by one or more product grids")
+		default:
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Collection deleted"})
+}
+
+// sendCollectionError writes the response for an error from a collection create or update.
+func sendCollectionError(w http.ResponseWriter, err error) {
+	if utils.SendContextError(w, err) {
+		return
+	}
+	switch err.Error() {
+	case "collection not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Collection not found")
+	case "collection name already exists":
+		utils.SendError(w, 409, "CONFLICT", "Collection name already exists")
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+	}
+}
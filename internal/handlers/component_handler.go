@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetComponentsHandler handles GET /components requests.
+// Returns every reusable component in the library, ordered by name.
+// Status: 200 OK on success, 500 on database error
+func GetComponentsHandler(w http.ResponseWriter, r *http.Request) {
+	components, err := services.GetComponents(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, components)
+}
+
+// CreateComponentHandler handles POST /components requests.
+// Creates a component from an explicit root definition, or by copying an existing
+// widget subtree when source_widget_id is given.
+// Status: 201 Created on success, 404 if source widget not found, 409 if name taken,
+// 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func CreateComponentHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ComponentRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	component := models.Component{Name: req.Name, Description: req.Description}
+	createdComponent, err := services.CreateComponent(r.Context(), component, req.Root, req.SourceWidgetID)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "source widget not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Source widget not found")
+		case "component name already exists":
+			utils.SendError(w, 409, "CONFLICT", "Component name already exists")
+		default:
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 201, createdComponent)
+}
+
+// GetComponentHandler handles GET /components/:id requests.
+// Returns the component with its full widget definition.
+// Status: 200 OK on success, 404 if component not found
+func GetComponentHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	component, err := services.GetComponent(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 404, "NOT_FOUND", "Component not found")
+		return
+	}
+
+	utils.SendJSON(w, 200, component)
+}
+
+// UpdateComponentHandler handles PUT /components/:id requests.
+// Replaces the component's name, description and root definition.
+// The change appears on every page that references the component.
+// Status: 200 OK on success, 404 if component not found, 409 if name taken,
+// 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateComponentHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req models.ComponentRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+	if req.Root == nil || req.SourceWidgetID != nil {
+		utils.SendError(w, 400, "VALIDATION_ERROR", "root is required and source_widget_id is only allowed on create")
+		return
+	}
+
+	component := models.Component{Name: req.Name, Description: req.Description, Root: *req.Root}
+	updatedComponent, err := services.UpdateComponent(r.Context(), id, component)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "component not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Component not found")
+		case "component name already exists":
+			utils.SendError(w, 409, "CONFLICT", "Component name already exists")
+		default:
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, updatedComponent)
+}
+
+// DeleteComponentHandler handles DELETE /components/:id requests.
+// A component referenced by any page cannot be deleted.
+// Status: 200 OK on success, 404 if component not found, 409 if still in use
+func DeleteComponentHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	err := services.DeleteComponent(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "component not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Component not found")
+		case "component is in use":
+			utils.SendError(w, 409, "CONFLICT", "Component is used by one or more pages")
+		default:
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Component deleted"})
+}
+
+// GetComponentUsagesHandler handles GET /components/:id/usages requests.
+// Lists every widget referencing the component together with its page.
+// Status: 200 OK on success, 404 if component not found
+func GetComponentUsagesHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	usages, err := services.GetComponentUsages(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "component not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Component not found")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, usages)
+}
+
+// DetachWidgetHandler handles POST /widgets/:id/detach requests.
+// Converts a component reference into a local copy of the component's current
+// definition; later component edits no longer affect it.
+// Returns the detached widget with its children.
+// Status: 200 OK on success, 404 if widget or component not found, 400 if not a component widget
+func DetachWidgetHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	detachedWidget, err := services.DetachWidget(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "widget not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Widget not found")
+		case "component not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Component not found")
+		default:
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, detachedWidget)
+}
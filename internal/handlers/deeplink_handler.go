@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetRouteTableHandler handles GET /routes requests.
+// Returns the page routes and redirects app links can open, with each route in
+// apple-app-site-association and Android intent filter syntax.
+// Status: 200 OK on success, 500 on database error
+func GetRouteTableHandler(w http.ResponseWriter, r *http.Request) {
+	table, err := services.GetRouteTable(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, table)
+}
+
+// GetAppleAppSiteAssociationHandler handles GET /.well-known/apple-app-site-association requests.
+// Serves the file letting the configured iOS apps open universal links to the app's pages,
+// generated from the current routes (see serveLinkFile).
+// Status: 200 OK (304 for conditional requests), 404 if no iOS apps are configured, 500 on database error
+func GetAppleAppSiteAssociationHandler(w http.ResponseWriter, r *http.Request) {
+	doc, err := services.GetAppleAppSiteAssociation(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "apple app links are not configured" {
+			utils.SendError(w, 404, "NOT_FOUND", "Apple app links are not configured")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	serveLinkFile(w, r, doc)
+}
+
+// GetAssetLinksHandler handles GET /.well-known/assetlinks.json requests.
+// Serves the Digital Asset Links statements letting the configured Android app open app links.
+// Status: 200 OK (304 for conditional requests), 404 if no Android app is configured
+func GetAssetLinksHandler(w http.ResponseWriter, r *http.Request) {
+	links, err := services.GetAssetLinks()
+	if err != nil {
+		utils.SendError(w, 404, "NOT_FOUND", "Android app links are not configured")
+		return
+	}
+
+	serveLinkFile(w, r, links)
+}
+
+// serveLinkFile writes a generated deep link file as JSON. The files follow the pages,
+// so they are only cached briefly; the ETag is a hash of the content, letting clients
+// revalidate cheaply.
+func serveLinkFile(w http.ResponseWriter, r *http.Request, doc interface{}) {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+	sum := sha256.Sum256(body)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"time"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetExperimentsHandler handles GET /experiments requests.
+// Returns every experiment, newest first.
+// Status: 200 OK on success, 500 on database error
+func GetExperimentsHandler(w http.ResponseWriter, r *http.Request) {
+	experiments, err := services.GetExperiments(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, experiments)
+}
+
+// CreateExperimentHandler handles POST /experiments requests.
+// Creates an experiment on a page with its variants and traffic weights.
+// Status: 201 Created on success, 409 if the name is taken or the page already has a
+// running experiment, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func CreateExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ExperimentRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	experiment, err := services.CreateExperiment(r.Context(), req.ToExperiment())
+	if err != nil {
+		sendExperimentError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 201, experiment)
+}
+
+// GetExperimentHandler handles GET /experiments/:id requests.
+// Status: 200 OK on success, 404 if experiment not found
+func GetExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	experiment, err := services.GetExperiment(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 404, "NOT_FOUND", "Experiment not found")
+		return
+	}
+
+	utils.SendJSON(w, 200, experiment)
+}
+
+// UpdateExperimentHandler handles PUT /experiments/:id requests.
+// Replaces the experiment; starting and stopping it is done by changing status.
+// A running experiment's page and variants cannot change.
+// Status: 200 OK on success, 404 if experiment not found, 409 for name, running-experiment
+// or status conflicts, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ExperimentRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	experiment, err := services.UpdateExperiment(r.Context(), r.PathValue("id"), req.ToExperiment())
+	if err != nil {
+		sendExperimentError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, experiment)
+}
+
+// DeleteExperimentHandler handles DELETE /experiments/:id requests.
+// Deletes the experiment and its exposure log; running experiments must be stopped first.
+// Status: 200 OK on success, 404 if experiment not found, 409 if running
+func DeleteExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	if err := services.DeleteExperiment(r.Context(), r.PathValue("id")); err != nil {
+		sendExperimentError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Experiment deleted"})
+}
+
+// GetExperimentExposuresHandler handles GET /experiments/:id/exposures requests.
+// Exports the exposure log as CSV with columns experiment_id, user_id, variant and
+// exposed_at (RFC 3339, UTC), one row per user in the order they were first exposed.
+// Status: 200 OK on success, 404 if experiment not found
+func GetExperimentExposuresHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	exposures, err := services.GetExperimentExposures(r.Context(), id)
+	if err != nil {
+		sendExperimentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="experiment-`+id+`-exposures.csv"`)
+	w.WriteHeader(200)
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"experiment_id", "user_id", "variant", "exposed_at"})
+	for _, e := range exposures {
+		out.Write([]string{id, e.UserID, e.Variant, e.ExposedAt.UTC().Format(time.RFC3339)})
+	}
+	out.Flush()
+}
+
+// sendExperimentError writes the response for an error from an experiment service call.
+func sendExperimentError(w http.ResponseWriter, err error) {
+	if utils.SendContextError(w, err) {
+		return
+	}
+	switch err.Error() {
+	case "experiment not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Experiment not found")
+	case "experiment name already exists":
+		utils.SendError(w, 409, "CONFLICT", "Experiment name already exists")
+	case "page already has a running experiment":
+		utils.SendError(w, 409, "CONFLICT", "Page already has a running experiment")
+	case "stop the experiment before changing its page or variants":
+		utils.SendError(w, 409, "CONFLICT", "Stop the experiment before changing its page or variants")
+	case "a started experiment cannot return to draft":
+		utils.SendError(w, 409, "CONFLICT", "A started experiment cannot return to draft")
+	case "cannot delete a running experiment":
+		utils.SendError(w, 409, "CONFLICT", "Cannot delete a running experiment")
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// ReadinessTimeout bounds each dependency check performed by ReadyzHandler.
+// Set from configuration at startup.
+var ReadinessTimeout = 2 * time.Second
+
+// LivezHandler handles GET /livez requests.
+// Reports that the process is running without touching any dependency.
+// Status: 200 OK
+func LivezHandler(w http.ResponseWriter, r *http.Request) {
+	utils.SendJSON(w, 200, services.CheckLiveness())
+}
+
+// ReadyzHandler handles GET /readyz requests.
+// Checks the database, schema version and connection pool and reports
+// per-check status and latency. Fails while the server is shutting down.
+// Status: 200 OK when ready, 503 Service Unavailable otherwise
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	report := services.CheckReadiness(r.Context(), ReadinessTimeout)
+	if report.Status != "pass" {
+		utils.SendJSON(w, 503, report)
+		return
+	}
+	utils.SendJSON(w, 200, report)
+}
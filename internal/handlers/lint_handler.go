@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// LintPageHandler handles GET /pages/:id/lint requests.
+// Runs the lint rules over the page's widgets, including the components it uses, and
+// returns the problems found, errors first. ?category= runs only one category of rules.
+// Status: 200 OK on success, 400 for an unknown category, 404 if page not found
+func LintPageHandler(w http.ResponseWriter, r *http.Request) {
+	report, err := services.LintPage(r.Context(), r.PathValue("id"), r.URL.Query().Get("category"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "page not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+		} else if err.Error() == "category must be layout, content, links or accessibility" {
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, report)
+}
+
+// LintAppHandler handles GET /lint requests.
+// Runs the lint rules over every page, over components no page uses and over the navigation.
+// ?category= runs only one category of rules.
+// Status: 200 OK on success, 400 for an unknown category, 500 on database error
+func LintAppHandler(w http.ResponseWriter, r *http.Request) {
+	report, err := services.LintApp(r.Context(), r.URL.Query().Get("category"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "category must be layout, content, links or accessibility" {
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, report)
+}
+
+// GetLintRulesHandler handles GET /lint/rules requests.
+// Lists the lint rules with their default and current severities.
+// Status: 200 OK on success, 500 on database error
+func GetLintRulesHandler(w http.ResponseWriter, r *http.Request) {
+	rules, err := services.GetLintRules(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, rules)
+}
+
+// GetLintSettingsHandler handles GET /lint/settings requests.
+// Returns the rule severity overrides and whether the publish gate is on.
+// Status: 200 OK on success, 500 on database error
+func GetLintSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	settings, err := services.GetLintSettings(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, settings)
+}
+
+// UpdateLintSettingsHandler handles PUT /lint/settings requests.
+// Replaces the rule severity overrides (error, warning or off) and the publish gate.
+// Returns the stored settings.
+// Status: 200 OK on success, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateLintSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.LintSettingsRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	settings, err := services.UpdateLintSettings(r.Context(), req.ToLintSettings())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, settings)
+}
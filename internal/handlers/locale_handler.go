@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetLocalesHandler handles GET /locales requests.
+// Returns the app's locales and its default locale.
+// Status: 200 OK on success, 500 on database error
+func GetLocalesHandler(w http.ResponseWriter, r *http.Request) {
+	settings, err := services.GetLocaleSettings(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, settings)
+}
+
+// UpdateLocalesHandler handles PUT /locales requests.
+// Replaces the app's locales; removing a locale deletes its translations.
+// Status: 200 OK on success, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateLocalesHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.LocaleSettingsRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	settings, err := services.UpdateLocaleSettings(r.Context(), models.LocaleSettings{Locales: req.Locales, Default: req.Default})
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, settings)
+}
+
+// GetPageTranslationsHandler handles GET /pages/:id/translations requests.
+// Returns every stored translation of the page name and the page's widgets.
+// Status: 200 OK on success, 404 if page not found
+func GetPageTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	translations, err := services.GetPageTranslations(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "page not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, translations)
+}
+
+// SetPageTranslationHandler handles PUT /pages/:id/translations/:locale requests.
+// Creates or replaces the page name in a non-default locale.
+// Status: 200 OK on success, 404 if page or locale not found, 400 for validation errors,
+// 413/415 for oversized or non-JSON bodies
+func SetPageTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	locale := r.PathValue("locale")
+
+	var req models.PageTranslationRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	translation, err := services.SetPageTranslation(r.Context(), id, locale, req.Name)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		sendTranslationError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, translation)
+}
+
+// DeletePageTranslationHandler handles DELETE /pages/:id/translations/:locale requests.
+// Removes the page name translation; reads fall back to the default locale.
+// Status: 200 OK on success, 404 if there is no such translation
+func DeletePageTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	err := services.DeletePageTranslation(r.Context(), r.PathValue("id"), r.PathValue("locale"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		sendTranslationError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Translation deleted"})
+}
+
+// SetWidgetTranslationHandler handles PUT /widgets/:id/translations/:locale requests.
+// Creates or replaces the widget's translatable config fields in a non-default locale.
+// Status: 200 OK on success, 404 if widget or locale not found, 400 for validation errors,
+// 413/415 for oversized or non-JSON bodies
+func SetWidgetTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	locale := r.PathValue("locale")
+
+	var req models.WidgetTranslationRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	translation, err := services.SetWidgetTranslation(r.Context(), id, locale, req.Fields)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		sendTranslationError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, translation)
+}
+
+// DeleteWidgetTranslationHandler handles DELETE /widgets/:id/translations/:locale requests.
+// Removes the widget's translation; reads fall back to the default locale.
+// Status: 200 OK on success, 404 if there is no such translation
+func DeleteWidgetTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	err := services.DeleteWidgetTranslation(r.Context(), r.PathValue("id"), r.PathValue("locale"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		sendTranslationError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Translation deleted"})
+}
+
+// GetMissingTranslationsHandler handles GET /pages/:id/missing-translations requests.
+// Reports per non-default locale whether the page name and which widget fields are untranslated.
+// Status: 200 OK on success, 404 if page not found
+func GetMissingTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	report, err := services.GetMissingTranslations(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "page not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, report)
+}
+
+// sendTranslationError maps translation service errors to responses.
+func sendTranslationError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "page not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+	case "widget not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Widget not found")
+	case "locale not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Locale not found")
+	case "translation not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Translation not found")
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetNavigationHandler handles GET /navigation requests.
+// Returns the tab bar and drawer items in display order; items whose page
+// was deleted are flagged with broken=true.
+// Status: 200 OK on success, 500 on database error
+func GetNavigationHandler(w http.ResponseWriter, r *http.Request) {
+	nav, err := services.GetNavigation(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, nav)
+}
+
+// UpdateNavigationHandler handles PUT /navigation requests.
+// Replaces both menus with the given items, validated against existing pages.
+// Returns the stored navigation.
+// Status: 200 OK on success, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateNavigationHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.NavigationRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	nav, err := services.UpdateNavigation(r.Context(), req.ToNavigation())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, nav)
+}
+
+// GetManifestHandler handles GET /manifest requests.
+// Returns the pages, home page, navigation, theme and locales the mobile app needs at startup.
+// Page names are localized using ?locale= or the Accept-Language header.
+// Status: 200 OK on success, 400 for an unsupported locale, 500 on database error
+func GetManifestHandler(w http.ResponseWriter, r *http.Request) {
+	opts := models.RenderOptions{
+		Locale:         r.URL.Query().Get("locale"),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+	}
+
+	manifest, err := services.GetManifest(r.Context(), opts)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "unsupported locale" {
+			utils.SendError(w, 400, "VALIDATION_ERROR", "Unsupported locale")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, manifest)
+}
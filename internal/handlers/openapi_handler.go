@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/openapi"
+)
+
+// OpenAPIHandler handles GET /openapi.json requests.
+// Serves the OpenAPI 3.1 specification describing every endpoint.
+// Status: 200 OK
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	w.Write(openapi.Spec)
+}
@@ -4,7 +4,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 
 	"appdrop-api/internal/models"
@@ -17,8 +17,11 @@ import (
 // Returns an empty array if no pages exist (never null).
 // Status: 200 OK on success, 500 on database error
 func GetPagesHandler(w http.ResponseWriter, r *http.Request) {
-	pages, err := services.GetPages()
+	pages, err := services.GetPages(r.Context())
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
 		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
 		return
 	}
@@ -35,18 +38,24 @@ func GetPagesHandler(w http.ResponseWriter, r *http.Request) {
 // Creates a new page with provided name, route, and is_home status.
 // Validates request body, route uniqueness, and is_home constraints.
 // Returns the created page with its UUID.
-// Status: 201 Created on success, 400 for validation errors
+// Status: 201 Created on success, 400 for validation errors, 409 if the publish gate
+// refuses a published page, 413/415 for oversized or non-JSON bodies
 func CreatePageHandler(w http.ResponseWriter, r *http.Request) {
-	var page models.Page
-
-	err := json.NewDecoder(r.Body).Decode(&page)
-	if err != nil {
-		utils.SendError(w, 400, "INVALID_JSON", "Invalid request body")
+	var req models.PageRequest
+	if !utils.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	createdPage, err := services.CreatePage(page)
+	createdPage, err := services.CreatePage(r.Context(), req.ToPage())
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		var lintErr *services.PageLintError
+		if errors.As(err, &lintErr) {
+			utils.SendErrorDetails(w, 409, "LINT_FAILED", "Page has lint errors and cannot be published", lintErr.Report)
+			return
+		}
 		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
 		return
 	}
@@ -57,13 +66,28 @@ func CreatePageHandler(w http.ResponseWriter, r *http.Request) {
 // GetPageByIDHandler handles GET /pages/:id requests.
 // Retrieves a page by UUID along with all its associated widgets.
 // Returns complete page structure including widget array.
-// Status: 200 OK on success, 404 if page not found
+// Optional query parameters: resolve=false returns widget configs without substituting
+// theme tokens; color_scheme=dark substitutes dark-mode colors; locale selects the
+// language of translated content (otherwise negotiated from Accept-Language).
+// Status: 200 OK on success, 400 for invalid query parameters, 404 if page not found
 func GetPageByIDHandler(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Path[len("/pages/"):]
+	id := r.PathValue("id")
 
-	data, err := services.GetPageWithWidgets(id)
+	opts, ok := renderOptions(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := services.GetPageWithWidgets(r.Context(), id, opts)
 	if err != nil {
-		utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "unsupported locale" {
+			utils.SendError(w, 400, "VALIDATION_ERROR", "Unsupported locale")
+		} else {
+			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+		}
 		return
 	}
 
@@ -72,13 +96,23 @@ func GetPageByIDHandler(w http.ResponseWriter, r *http.Request) {
 
 // DeletePageHandler handles DELETE /pages/:id requests.
 // Deletes a page and cascades delete to all its widgets.
-// Cannot delete the page marked as is_home=true.
-// Status: 200 OK on success, 404 if page not found, 409 if trying to delete home page
+// Cannot delete the page marked as is_home=true. When FEATURE_BLOCK_LINKED_PAGE_DELETES
+// is on, pages that other pages' widgets or components link to cannot be deleted either;
+// the 409 response lists the links under error.details.
+// Status: 200 OK on success, 404 if page not found, 409 if trying to delete home page or a linked page
 func DeletePageHandler(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Path[len("/pages/"):]
+	id := r.PathValue("id")
 
-	err := services.DeletePage(id)
+	err := services.DeletePage(r.Context(), id)
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		var linked *services.PageLinkedError
+		if errors.As(err, &linked) {
+			utils.SendErrorDetails(w, 409, "CONFLICT", "Page is linked from other widgets or components", linked.Links)
+			return
+		}
 		switch err.Error() {
 		case "cannot delete home page":
 			utils.SendError(w, 409, "CONFLICT", "Cannot delete home page")
@@ -96,25 +130,35 @@ func DeletePageHandler(w http.ResponseWriter, r *http.Request) {
 // UpdatePageHandler handles PUT /pages/:id requests.
 // Updates page name, route, or is_home status.
 // Validates new route uniqueness and is_home constraints.
+// When the lint settings turn the publish gate on, a draft with lint errors cannot be
+// published; the 409 response has the lint report under error.details.
 // Returns the updated page.
-// Status: 200 OK on success, 404 if page not found, 409 if route conflict
+// Status: 200 OK on success, 404 if page not found, 409 if route conflict or lint errors, 413/415 for oversized or non-JSON bodies
 func UpdatePageHandler(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Path[len("/pages/"):]
+	id := r.PathValue("id")
 
-	var page models.Page
-	err := json.NewDecoder(r.Body).Decode(&page)
-	if err != nil {
-		utils.SendError(w, 400, "INVALID_JSON", "Invalid request body")
+	var req models.PageRequest
+	if !utils.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	updatedPage, err := services.UpdatePage(id, page)
+	updatedPage, err := services.UpdatePage(r.Context(), id, req.ToPage())
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		var lintErr *services.PageLintError
+		if errors.As(err, &lintErr) {
+			utils.SendErrorDetails(w, 409, "LINT_FAILED", "Page has lint errors and cannot be published", lintErr.Report)
+			return
+		}
 		switch err.Error() {
 		case "page not found":
 			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
 		case "page route already exists":
 			utils.SendError(w, 409, "CONFLICT", "Page route already exists")
+		case "page route is ambiguous with an existing route":
+			utils.SendError(w, 409, "CONFLICT", "Page route is ambiguous with an existing route")
 		default:
 			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
 		}
@@ -123,3 +167,77 @@ func UpdatePageHandler(w http.ResponseWriter, r *http.Request) {
 
 	utils.SendJSON(w, 200, updatedPage)
 }
+
+// renderOptions parses the request parameters that control how a page is rendered:
+// resolve (true or false, default true), color_scheme (light or dark, default light),
+// locale (checked by the service) and the Accept-Language header.
+// Writes a 400 response and returns false if a parameter is invalid.
+func renderOptions(w http.ResponseWriter, r *http.Request) (models.RenderOptions, bool) {
+	query := r.URL.Query()
+	opts := models.RenderOptions{
+		Resolve:        true,
+		ColorScheme:    utils.ColorSchemeLight,
+		Locale:         query.Get("locale"),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+	}
+
+	switch query.Get("resolve") {
+	case "", "true":
+	case "false":
+		opts.Resolve = false
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", "resolve must be true or false")
+		return opts, false
+	}
+
+	switch scheme := query.Get("color_scheme"); scheme {
+	case "":
+	case utils.ColorSchemeLight, utils.ColorSchemeDark:
+		opts.ColorScheme = scheme
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", "color_scheme must be light or dark")
+		return opts, false
+	}
+
+	return opts, true
+}
+
+// ResolvePathHandler handles GET /resolve?path= requests.
+// Returns the page whose route matches the app path, such as "/products/123" for the
+// route "/products/:productId", and the extracted parameters. Redirects are followed and
+// listed under "redirects", so clients can update stored links to "path". Drafts and
+// hidden pages are included; use GET /public/resolve for what app users can open.
+// Status: 200 OK on success, 400 if the path is missing or malformed, 404 if no page
+// matches, 508 if redirects go on too long
+func ResolvePathHandler(w http.ResponseWriter, r *http.Request) {
+	resolvePath(w, r, models.RenderOptions{})
+}
+
+// resolvePath writes the response for a route resolution request with opts.
+func resolvePath(w http.ResponseWriter, r *http.Request, opts models.RenderOptions) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		utils.SendError(w, 400, "VALIDATION_ERROR", "path is required")
+		return
+	}
+
+	match, err := services.ResolvePath(r.Context(), path, opts)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "no page matches the path":
+			utils.SendError(w, 404, "NOT_FOUND", "No page matches the path")
+		case "too many redirects":
+			utils.SendError(w, 508, "LOOP_DETECTED", "Too many redirects")
+		case "path must start with / and have no empty segments":
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		default:
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, match)
+}
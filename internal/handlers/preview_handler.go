@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// CreatePreviewLinkHandler handles POST /pages/:id/preview-links requests.
+// Issues a signed, expiring link to a non-public view of the page (typically a draft).
+// Body (optional fields): label, expires_in (duration such as "48h").
+// Status: 201 Created on success, 404 if page not found, 400 for validation errors,
+// 413/415 for oversized or non-JSON bodies
+func CreatePreviewLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.PreviewLinkRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	link, err := services.CreatePreviewLink(r.Context(), r.PathValue("id"), req.Label, req.ExpiresIn)
+	if err != nil {
+		sendPreviewLinkError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 201, link)
+}
+
+// GetPreviewLinksHandler handles GET /pages/:id/preview-links requests.
+// Returns the page's active (not revoked or expired) links with their tokens.
+// Status: 200 OK on success, 404 if page not found
+func GetPreviewLinksHandler(w http.ResponseWriter, r *http.Request) {
+	links, err := services.GetPreviewLinks(r.Context(), r.PathValue("id"))
+	if err != nil {
+		sendPreviewLinkError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, links)
+}
+
+// RevokePreviewLinkHandler handles DELETE /pages/:id/preview-links/:linkId requests.
+// The link stops working immediately.
+// Status: 200 OK on success, 404 if page or link not found (or already revoked)
+func RevokePreviewLinkHandler(w http.ResponseWriter, r *http.Request) {
+	err := services.RevokePreviewLink(r.Context(), r.PathValue("id"), r.PathValue("linkId"))
+	if err != nil {
+		sendPreviewLinkError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Preview link revoked"})
+}
+
+// GetPreviewPageHandler handles GET /preview/:token requests.
+// Serves the page a valid preview token was issued for, drafts and pages outside their
+// visibility window included, with the same widget filtering and query parameters as
+// GET /public/pages/:id (experiments are not applied). Responses are not cacheable.
+// Status: 200 OK on success, 400 for invalid query parameters,
+// 404 if the token is invalid, expired or revoked
+func GetPreviewPageHandler(w http.ResponseWriter, r *http.Request) {
+	opts, ok := renderOptions(w, r)
+	if !ok {
+		return
+	}
+	if !publicOptions(w, r, &opts) || !clientOptions(w, r, &opts) {
+		return
+	}
+	// Previews must not skew experiment exposure logs
+	opts.UserID = ""
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	data, err := services.GetPreviewPage(r.Context(), r.PathValue("token"), opts)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "unsupported locale":
+			utils.SendError(w, 400, "VALIDATION_ERROR", "Unsupported locale")
+		case "invalid preview link":
+			utils.SendError(w, 404, "NOT_FOUND", "Preview link is invalid, expired or revoked")
+		default:
+			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, data)
+}
+
+// sendPreviewLinkError writes the response for an error from a preview link service call.
+func sendPreviewLinkError(w http.ResponseWriter, err error) {
+	if utils.SendContextError(w, err) {
+		return
+	}
+	switch err.Error() {
+	case "page not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+	case "preview link not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Preview link not found")
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+	}
+}
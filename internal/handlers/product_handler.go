@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetProductsHandler handles GET /products requests.
+// Returns every product ordered by name.
+// Status: 200 OK on success, 500 on database error
+func GetProductsHandler(w http.ResponseWriter, r *http.Request) {
+	products, err := services.GetProducts(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, products)
+}
+
+// CreateProductHandler handles POST /products requests.
+// Status: 201 Created on success, 409 if the SKU is taken, 400 for validation errors,
+// 413/415 for oversized or non-JSON bodies
+func CreateProductHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ProductRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	product, err := services.CreateProduct(r.Context(), req.ToProduct())
+	if err != nil {
+		sendProductError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 201, product)
+}
+
+// GetProductHandler handles GET /products/:id requests.
+// Status: 200 OK on success, 404 if product not found
+func GetProductHandler(w http.ResponseWriter, r *http.Request) {
+	product, err := services.GetProduct(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 404, "NOT_FOUND", "Product not found")
+		return
+	}
+
+	utils.SendJSON(w, 200, product)
+}
+
+// UpdateProductHandler handles PUT /products/:id requests.
+// Replaces every field of the product; in_stock defaults to true when omitted.
+// Status: 200 OK on success, 404 if product not found, 409 if the SKU is taken,
+// 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateProductHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ProductRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	product, err := services.UpdateProduct(r.Context(), r.PathValue("id"), req.ToProduct())
+	if err != nil {
+		sendProductError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, product)
+}
+
+// DeleteProductHandler handles DELETE /products/:id requests.
+// The product is removed from every collection. A product listed by a product_grid's
+// product_ids, or bound in a widget config, cannot be deleted.
+// Status: 200 OK on success, 404 if product not found, 409 if still in use
+func DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
+	err := services.DeleteProduct(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "product not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Product not found")
+		case "product is in use":
+			utils.SendError(w, 409, "CONFLICT", "Product is used by one or more widgets")
+		default:
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Product deleted"})
+}
+
+// sendProductError writes the response for an error from a product create or update.
+func sendProductError(w http.ResponseWriter, err error) {
+	if utils.SendContextError(w, err) {
+		return
+	}
+	switch err.Error() {
+	case "product not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Product not found")
+	case "product sku already exists":
+		utils.SendError(w, 409, "CONFLICT", "Product SKU already exists")
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+	}
+}
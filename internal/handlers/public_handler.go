@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/targeting"
+	"appdrop-api/internal/utils"
+)
+
+// maxUserIDLength is the longest user ID accepted for experiment bucketing.
+const maxUserIDLength = 256
+
+// GetPublicPageHandler handles GET /public/pages/:id requests.
+// Returns a page the way app users see it: drafts and pages outside their visibility
+// window are not found, and hidden widgets and navigation items are left out.
+// Widgets with a targeting rule are only returned if it matches the client context:
+// platform, app_version, country, segments (comma-separated) and logged_in, sent as
+// query parameters or X-Platform, X-App-Version, X-Country, X-Segments and X-Logged-In headers.
+// Accepts the same query parameters as GET /pages/:id, plus at=<RFC 3339 time> to
+// preview what is visible at another time (default: now).
+// Status: 200 OK on success, 400 for invalid query parameters, 404 if the page is not visible
+func GetPublicPageHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	opts, ok := renderOptions(w, r)
+	if !ok {
+		return
+	}
+	if !publicOptions(w, r, &opts) || !clientOptions(w, r, &opts) {
+		return
+	}
+
+	data, err := services.GetPageWithWidgets(r.Context(), id, opts)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "unsupported locale" {
+			utils.SendError(w, 400, "VALIDATION_ERROR", "Unsupported locale")
+		} else {
+			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, data)
+}
+
+// GetPublicResolveHandler handles GET /public/resolve?path= requests.
+// Like GET /resolve, but only pages visible to app users can match. Accepts ?at= like
+// GET /public/pages/:id.
+// Status: 200 OK on success, 400 for a missing or malformed path or invalid query
+// parameters, 404 if no visible page matches, 508 if redirects go on too long
+func GetPublicResolveHandler(w http.ResponseWriter, r *http.Request) {
+	var opts models.RenderOptions
+	if !publicOptions(w, r, &opts) {
+		return
+	}
+	resolvePath(w, r, opts)
+}
+
+// GetPublicManifestHandler handles GET /public/manifest requests.
+// Returns the app manifest limited to the pages visible to app users; navigation items
+// for hidden pages are left out. Accepts ?locale= and ?at= like GET /public/pages/:id.
+// Status: 200 OK on success, 400 for invalid query parameters, 500 on database error
+func GetPublicManifestHandler(w http.ResponseWriter, r *http.Request) {
+	opts := models.RenderOptions{
+		Locale:         r.URL.Query().Get("locale"),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+	}
+	if !publicOptions(w, r, &opts) {
+		return
+	}
+
+	manifest, err := services.GetManifest(r.Context(), opts)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "unsupported locale" {
+			utils.SendError(w, 400, "VALIDATION_ERROR", "Unsupported locale")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, manifest)
+}
+
+// clientOptions sets opts.Client from the client context query parameters or headers
+// (see utils.ClientContextHeaders). Writes a 400 response and returns false if a value is invalid.
+func clientOptions(w http.ResponseWriter, r *http.Request, opts *models.RenderOptions) bool {
+	client, err := targeting.ParseContext(func(name string) string {
+		if value := r.URL.Query().Get(name); value != "" {
+			return value
+		}
+		return r.Header.Get(utils.ClientContextHeaders[name])
+	})
+	if err != nil {
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		return false
+	}
+	opts.Client = client
+	return true
+}
+
+// publicOptions marks opts as a public read evaluated at ?at= (RFC 3339), or at the
+// server's current time when the parameter is absent, for the user in ?user_id= or
+// the X-User-ID header (used for experiment bucketing).
+// Writes a 400 response and returns false if a parameter is invalid.
+func publicOptions(w http.ResponseWriter, r *http.Request, opts *models.RenderOptions) bool {
+	opts.Public = true
+	opts.At = time.Now()
+
+	if at := r.URL.Query().Get("at"); at != "" {
+		parsed, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			utils.SendError(w, 400, "VALIDATION_ERROR", "at must be an RFC 3339 timestamp")
+			return false
+		}
+		opts.At = parsed
+	}
+
+	opts.UserID = r.URL.Query().Get("user_id")
+	if opts.UserID == "" {
+		opts.UserID = r.Header.Get(utils.UserIDHeader)
+	}
+	if len(opts.UserID) > maxUserIDLength {
+		utils.SendError(w, 400, "VALIDATION_ERROR", fmt.Sprintf("user_id cannot be longer than %d characters", maxUserIDLength))
+		return false
+	}
+	return true
+}
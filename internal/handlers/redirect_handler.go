@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetRedirectsHandler handles GET /redirects requests.
+// Returns every redirect, including those recorded automatically when page routes changed.
+// Status: 200 OK on success, 500 on database error
+func GetRedirectsHandler(w http.ResponseWriter, r *http.Request) {
+	redirects, err := services.GetRedirects(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, redirects)
+}
+
+// CreateRedirectHandler handles POST /redirects requests.
+// Status: 201 Created on success, 409 if from_route is already redirected or overlaps
+// another redirect ambiguously, 400 for validation errors (including loops),
+// 413/415 for oversized or non-JSON bodies
+func CreateRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.RedirectRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	redirect, err := services.CreateRedirect(r.Context(), req.ToRedirect())
+	if err != nil {
+		sendRedirectError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 201, redirect)
+}
+
+// GetRedirectHandler handles GET /redirects/:id requests.
+// Status: 200 OK on success, 404 if redirect not found
+func GetRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	redirect, err := services.GetRedirect(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 404, "NOT_FOUND", "Redirect not found")
+		return
+	}
+
+	utils.SendJSON(w, 200, redirect)
+}
+
+// UpdateRedirectHandler handles PUT /redirects/:id requests.
+// Replaces the redirect's routes and target; an automatic redirect becomes manual.
+// Status: 200 OK on success, 404 if redirect not found, 409 if from_route is already
+// redirected or overlaps another redirect ambiguously, 400 for validation errors,
+// 413/415 for oversized or non-JSON bodies
+func UpdateRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.RedirectRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	redirect, err := services.UpdateRedirect(r.Context(), r.PathValue("id"), req.ToRedirect())
+	if err != nil {
+		sendRedirectError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, 200, redirect)
+}
+
+// DeleteRedirectHandler handles DELETE /redirects/:id requests.
+// Status: 200 OK on success, 404 if redirect not found
+func DeleteRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	err := services.DeleteRedirect(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "redirect not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Redirect not found")
+		} else {
+			utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Redirect deleted"})
+}
+
+// sendRedirectError writes the response for an error from a redirect create or update.
+func sendRedirectError(w http.ResponseWriter, err error) {
+	if utils.SendContextError(w, err) {
+		return
+	}
+	switch err.Error() {
+	case "redirect not found":
+		utils.SendError(w, 404, "NOT_FOUND", "Redirect not found")
+	case "redirect from_route already exists":
+		utils.SendError(w, 409, "CONFLICT", "Redirect from_route already exists")
+	case "redirect from_route is ambiguous with an existing redirect":
+		utils.SendError(w, 409, "CONFLICT", "Redirect from_route is ambiguous with an existing redirect")
+	default:
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+	}
+}
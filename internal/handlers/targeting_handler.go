@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// ValidateTargetingHandler handles POST /targeting/validate requests.
+// Checks a widget targeting rule without saving it. An invalid rule is reported in the
+// response body with the problem and its position, not as an error status.
+// Status: 200 OK with the result, 400 for malformed bodies, 413/415 for oversized or non-JSON bodies
+func ValidateTargetingHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.TargetingRuleRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	utils.SendJSON(w, 200, services.ValidateTargetingRule(req.Rule))
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetTemplatesHandler handles GET /templates requests.
+// Optional query parameters: category (exact match) and q (searches name and description).
+// Returns matching templates, built-in starter templates first.
+// Status: 200 OK on success, 500 on database error
+func GetTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	filter := models.TemplateFilter{
+		Category: r.URL.Query().Get("category"),
+		Query:    r.URL.Query().Get("q"),
+	}
+
+	templates, err := services.GetTemplates(r.Context(), filter)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, templates)
+}
+
+// CreateTemplateHandler handles POST /templates requests.
+// Saves a template from an explicit widgets list, or by copying the current widgets
+// of an existing page when source_page_id is given.
+// Status: 201 Created on success, 404 if source page not found, 409 if name taken,
+// 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func CreateTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.TemplateRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	createdTemplate, err := services.CreateTemplate(r.Context(), req.ToTemplate(), req.SourcePageID)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "source page not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Source page not found")
+		case "template name already exists":
+			utils.SendError(w, 409, "CONFLICT", "Template name already exists")
+		default:
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 201, createdTemplate)
+}
+
+// GetTemplateHandler handles GET /templates/:id requests.
+// Returns the template with its widget definitions.
+// Status: 200 OK on success, 404 if template not found
+func GetTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	template, err := services.GetTemplate(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 404, "NOT_FOUND", "Template not found")
+		return
+	}
+
+	utils.SendJSON(w, 200, template)
+}
+
+// DeleteTemplateHandler handles DELETE /templates/:id requests.
+// Built-in starter templates cannot be deleted.
+// Status: 200 OK on success, 404 if template not found, 409 if built in
+func DeleteTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	err := services.DeleteTemplate(r.Context(), id)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		switch err.Error() {
+		case "template not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Template not found")
+		case "cannot delete built-in template":
+			utils.SendError(w, 409, "CONFLICT", "Cannot delete built-in template")
+		default:
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, map[string]string{"message": "Template deleted"})
+}
+
+// CreatePageFromTemplateHandler handles POST /pages/from-template/:templateId requests.
+// Creates a page with the given name, route and is_home status, pre-filled with a copy
+// of the template's widgets.
+// Returns the new page with its widget tree, rendered like GET /pages/:id (same query parameters).
+// Status: 201 Created on success, 404 if template not found, 409 if route conflict or
+// the publish gate refuses a published page, 400 for validation errors, 413/415 for
+// oversized or non-JSON bodies
+func CreatePageFromTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	templateID := r.PathValue("templateId")
+
+	opts, ok := renderOptions(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.PageRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	data, err := services.CreatePageFromTemplate(r.Context(), templateID, req.ToPage(), opts)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		var lintErr *services.PageLintError
+		if errors.As(err, &lintErr) {
+			utils.SendErrorDetails(w, 409, "LINT_FAILED", "Page has lint errors and cannot be published", lintErr.Report)
+			return
+		}
+		switch err.Error() {
+		case "template not found":
+			utils.SendError(w, 404, "NOT_FOUND", "Template not found")
+		case "page route already exists":
+			utils.SendError(w, 409, "CONFLICT", "Page route already exists")
+		case "page route is ambiguous with an existing route":
+			utils.SendError(w, 409, "CONFLICT", "Page route is ambiguous with an existing route")
+		default:
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 201, data)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetThemeHandler handles GET /theme requests.
+// Returns the app's design tokens.
+// Status: 200 OK on success, 500 on database error
+func GetThemeHandler(w http.ResponseWriter, r *http.Request) {
+	theme, err := services.GetTheme(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, theme)
+}
+
+// UpdateThemeHandler handles PUT /theme requests.
+// Replaces every token group; tokens still referenced by widgets or components cannot be removed.
+// Returns the stored theme.
+// Status: 200 OK on success, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateThemeHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ThemeRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	theme, err := services.UpdateTheme(r.Context(), req.ToTheme())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, theme)
+}
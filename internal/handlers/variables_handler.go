@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/utils"
+)
+
+// GetStoreVariablesHandler handles GET /variables requests.
+// Returns the store variables widget configs bind as {{store.<name>}}.
+// Status: 200 OK on success, 500 on database error
+func GetStoreVariablesHandler(w http.ResponseWriter, r *http.Request) {
+	variables, err := services.GetStoreVariables(r.Context())
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 500, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, variables)
+}
+
+// UpdateStoreVariablesHandler handles PUT /variables requests.
+// Replaces every variable; variables still referenced by widgets or components cannot be removed.
+// Returns the stored variables.
+// Status: 200 OK on success, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func UpdateStoreVariablesHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.StoreVariablesRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	variables, err := services.UpdateStoreVariables(r.Context(), req.Variables)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	utils.SendJSON(w, 200, variables)
+}
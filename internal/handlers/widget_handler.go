@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"appdrop-api/internal/models"
@@ -13,22 +12,23 @@ import (
 // Creates a new widget on the specified page.
 // Parses pageID from URL path and validates widget type and configuration.
 // Returns the created widget with its UUID and assigned position.
-// Status: 201 Created on success, 404 if page not found, 400 for validation errors
+// Status: 201 Created on success, 404 if page not found, 400 for validation errors, 413/415 for oversized or non-JSON bodies
 func CreateWidgetHandler(w http.ResponseWriter, r *http.Request) {
-	pageID := r.URL.Path[len("/pages/"):]
-	pageID = pageID[:len(pageID)-len("/widgets")]
+	pageID := r.PathValue("id")
 
-	var widget models.Widget
-	err := json.NewDecoder(r.Body).Decode(&widget)
-	if err != nil {
-		utils.SendError(w, 400, "INVALID_JSON", "Invalid request body")
+	var req models.WidgetRequest
+	if !utils.DecodeJSON(w, r, &req) {
 		return
 	}
 
+	widget := req.ToWidget()
 	widget.PageID = pageID
 
-	createdWidget, err := services.CreateWidget(widget)
+	createdWidget, err := services.CreateWidget(r.Context(), widget)
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
 		if err.Error() == "page not found" {
 			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
 		} else {
@@ -41,23 +41,27 @@ func CreateWidgetHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateWidgetHandler handles PUT /widgets/:id requests.
-// Updates widget configuration, type, or position within its page.
-// Validates widget existence and type constraints.
+// Updates widget configuration, type, or position among its siblings.
+// Validates widget existence and type constraints. The body cannot carry parent_id;
+// POST /widgets/:id/move re-parents a widget.
 // Returns the updated widget.
-// Status: 200 OK on success, 404 if widget not found, 400 for validation errors
+// Status: 200 OK on success, 404 if widget not found, 400 for validation errors, 413/415 for oversized or non-JSON bodies
 func UpdateWidgetHandler(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Path[len("/widgets/"):]
+	id := r.PathValue("id")
 
-	var widget models.Widget
-	err := json.NewDecoder(r.Body).Decode(&widget)
-	if err != nil {
-		utils.SendError(w, 400, "INVALID_JSON", "Invalid request body")
+	var req models.UpdateWidgetRequest
+	if !utils.DecodeJSON(w, r, &req) {
 		return
 	}
+
+	widget := req.ToWidget()
 	widget.ID = id
 
-	updatedWidget, err := services.UpdateWidget(widget)
+	updatedWidget, err := services.UpdateWidget(r.Context(), widget)
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
 		if err.Error() == "widget not found" {
 			utils.SendError(w, 404, "NOT_FOUND", "Widget not found")
 		} else {
@@ -73,10 +77,13 @@ func UpdateWidgetHandler(w http.ResponseWriter, r *http.Request) {
 // Removes a widget from its page by UUID.
 // Status: 200 OK on success, 404 if widget not found
 func DeleteWidgetHandler(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Path[len("/widgets/"):]
+	id := r.PathValue("id")
 
-	err := services.DeleteWidget(id)
+	err := services.DeleteWidget(r.Context(), id)
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
 		if err.Error() == "widget not found" {
 			utils.SendError(w, 404, "NOT_FOUND", "Widget not found")
 		} else {
@@ -89,26 +96,24 @@ func DeleteWidgetHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // ReorderWidgetsHandler handles POST /pages/:id/widgets/reorder requests.
-// Updates the position of all widgets on a page based on provided widget_ids array.
+// Updates the position of sibling widgets based on provided widget_ids array.
 // The order of widget_ids in the request determines their new positions.
-// Validates that all widgets belong to the specified page.
-// Status: 200 OK on success, 404 if page or widget not found, 400 for validation errors
+// Validates that all widgets belong to the specified page and to parent_id
+// (omit parent_id to reorder top-level widgets).
+// Status: 200 OK on success, 404 if page or widget not found, 400 for validation errors, 413/415 for oversized or non-JSON bodies
 func ReorderWidgetsHandler(w http.ResponseWriter, r *http.Request) {
-	pageID := r.URL.Path[len("/pages/"):]
-	pageID = pageID[:len(pageID)-len("/widgets/reorder")]
+	pageID := r.PathValue("id")
 
-	var body struct {
-		WidgetIDs []string `json:"widget_ids"`
-	}
-
-	err := json.NewDecoder(r.Body).Decode(&body)
-	if err != nil {
-		utils.SendError(w, 400, "INVALID_JSON", "Invalid request body")
+	var req models.ReorderWidgetsRequest
+	if !utils.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	err = services.ReorderWidgets(pageID, body.WidgetIDs)
+	err := services.ReorderWidgets(r.Context(), pageID, req.ParentID, req.WidgetIDs)
 	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
 		switch err.Error() {
 		case "page not found":
 			utils.SendError(w, 404, "NOT_FOUND", "Page not found")
@@ -122,3 +127,33 @@ func ReorderWidgetsHandler(w http.ResponseWriter, r *http.Request) {
 
 	utils.SendJSON(w, 200, map[string]string{"message": "Widgets reordered"})
 }
+
+// MoveWidgetHandler handles POST /widgets/:id/move requests.
+// Moves a widget, together with its children, into another container (or to the
+// top level when parent_id is null) at the given position.
+// Rejects moves that would create a cycle or exceed the nesting depth limit.
+// Returns the moved widget.
+// Status: 200 OK on success, 404 if widget not found, 400 for validation errors, 413/415 for oversized or non-JSON bodies
+func MoveWidgetHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req models.MoveWidgetRequest
+	if !utils.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	movedWidget, err := services.MoveWidget(r.Context(), id, req.ParentID, req.Position)
+	if err != nil {
+		if utils.SendContextError(w, err) {
+			return
+		}
+		if err.Error() == "widget not found" {
+			utils.SendError(w, 404, "NOT_FOUND", "Widget not found")
+		} else {
+			utils.SendError(w, 400, "VALIDATION_ERROR", err.Error())
+		}
+		return
+	}
+
+	utils.SendJSON(w, 200, movedWidget)
+}
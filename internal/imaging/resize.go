@@ -0,0 +1,123 @@
+// Package imaging scales decoded images for resized asset variants, in pure Go.
+package imaging
+
+import (
+	"image"
+	"image/draw"
+	"slices"
+)
+
+// ToRGBA returns img as an *image.RGBA with its bounds moved to the origin,
+// converting it if needed.
+func ToRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, b.Min, draw.Src)
+	return rgba
+}
+
+// ScaledHeight returns the height that keeps src's aspect ratio at the given width,
+// rounded to the nearest pixel and at least 1.
+func ScaledHeight(src image.Rectangle, width int) int {
+	h := (src.Dy()*width*2 + src.Dx()) / (src.Dx() * 2)
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// VariantSizes returns the sizes of the resized variants of an image with bounds src:
+// one per distinct width narrower than the image, narrowest first, each keeping the
+// image's aspect ratio (see ScaledHeight). Images are never scaled up, so an image no
+// wider than the narrowest width has no variants.
+func VariantSizes(src image.Rectangle, widths []int) []image.Point {
+	widths = slices.Clone(widths)
+	slices.Sort(widths)
+	var sizes []image.Point
+	for _, width := range slices.Compact(widths) {
+		if width >= src.Dx() {
+			break
+		}
+		if width > 0 {
+			sizes = append(sizes, image.Pt(width, ScaledHeight(src, width)))
+		}
+	}
+	return sizes
+}
+
+// Resize scales src down to width x height by area averaging: every destination pixel
+// is the coverage-weighted mean of the source pixels under it, which avoids the
+// aliasing of point sampling. Averaging happens on premultiplied colors so transparent
+// pixels don't darken their neighbours. Upscaling is not supported; dimensions larger
+// than src are clamped to it.
+//
+// Source rows are scaled horizontally one at a time, so memory use beyond the
+// result is a few rows.
+func Resize(src *image.RGBA, width, height int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	width, height = min(max(width, 1), sw), min(max(height, 1), sh)
+
+	cols := boxWeights(sw, width)
+	rows := boxWeights(sh, height)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	rowBuf := make([]float64, width*4)
+	acc := make([]float64, width*4)
+
+	for y, sources := range rows {
+		clear(acc)
+		for _, s := range sources {
+			scaleRow(src, s.index, cols, rowBuf)
+			for i, v := range rowBuf {
+				acc[i] += v * s.weight
+			}
+		}
+		out := dst.Pix[y*dst.Stride : y*dst.Stride+width*4]
+		for i, v := range acc {
+			out[i] = uint8(min(max(v+0.5, 0), 255))
+		}
+	}
+	return dst
+}
+
+// contribution is a source pixel's share of a destination pixel.
+type contribution struct {
+	index  int
+	weight float64
+}
+
+// boxWeights maps each of the n destination pixels along one axis to the source
+// pixels it covers out of size, with weights adding up to 1.
+func boxWeights(size, n int) [][]contribution {
+	scale := float64(size) / float64(n)
+	weights := make([][]contribution, n)
+	for i := range weights {
+		start, end := float64(i)*scale, float64(i+1)*scale
+		for s := int(start); s < size && float64(s) < end; s++ {
+			overlap := min(end, float64(s+1)) - max(start, float64(s))
+			if overlap > 0 {
+				weights[i] = append(weights[i], contribution{s, overlap / scale})
+			}
+		}
+	}
+	return weights
+}
+
+// scaleRow scales source row y horizontally into out (4 premultiplied channels per pixel).
+func scaleRow(src *image.RGBA, y int, cols [][]contribution, out []float64) {
+	row := src.Pix[y*src.Stride:]
+	for x, sources := range cols {
+		var r, g, b, a float64
+		for _, s := range sources {
+			p := row[s.index*4 : s.index*4+4]
+			r += float64(p[0]) * s.weight
+			g += float64(p[1]) * s.weight
+			b += float64(p[2]) * s.weight
+			a += float64(p[3]) * s.weight
+		}
+		out[x*4], out[x*4+1], out[x*4+2], out[x*4+3] = r, g, b, a
+	}
+}
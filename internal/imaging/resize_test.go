@@ -0,0 +1,142 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// uniform returns a w x h image filled with c.
+func uniform(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestScaledHeight(t *testing.T) {
+	tests := []struct {
+		w, h  int
+		width int
+		want  int
+	}{
+		{1000, 500, 320, 160},
+		{1080, 1920, 320, 569}, // 568.9 rounds up
+		{1080, 1920, 640, 1138},
+		{1000, 1000, 333, 333},
+		{3, 2, 2, 1},       // 1.33 rounds down
+		{4, 2, 3, 2},       // 1.5 rounds half up
+		{10000, 1, 320, 1}, // never below 1 pixel
+	}
+	for _, tt := range tests {
+		got := ScaledHeight(image.Rect(0, 0, tt.w, tt.h), tt.width)
+		if got != tt.want {
+			t.Errorf("ScaledHeight(%dx%d, %d) = %d, want %d", tt.w, tt.h, tt.width, got, tt.want)
+		}
+		// The aspect ratio is kept to within rounding
+		src, dst := float64(tt.w)/float64(tt.h), float64(tt.width)/float64(got)
+		if got > 1 && math.Abs(float64(tt.width)/src-float64(got)) > 0.5 {
+			t.Errorf("ScaledHeight(%dx%d, %d) = %d changes the aspect ratio from %.3f to %.3f", tt.w, tt.h, tt.width, got, src, dst)
+		}
+	}
+}
+
+func TestVariantSizes(t *testing.T) {
+	widths := []int{1080, 320, 640, 320}
+	tests := []struct {
+		w, h int
+		want []image.Point
+	}{
+		{2000, 1000, []image.Point{{320, 160}, {640, 320}, {1080, 540}}},
+		{1080, 1080, []image.Point{{320, 320}, {640, 640}}}, // no variant as wide as the image
+		{700, 1400, []image.Point{{320, 640}, {640, 1280}}},
+		{320, 200, nil}, // never scaled up
+		{100, 100, nil},
+	}
+	for _, tt := range tests {
+		got := VariantSizes(image.Rect(0, 0, tt.w, tt.h), widths)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("VariantSizes(%dx%d) = %v, want %v", tt.w, tt.h, got, tt.want)
+		}
+	}
+	if widths[0] != 1080 {
+		t.Errorf("VariantSizes sorted its widths argument in place: %v", widths)
+	}
+}
+
+func TestResizeDimensions(t *testing.T) {
+	src := uniform(400, 300, color.RGBA{10, 20, 30, 255})
+	tests := []struct {
+		width, height int
+		want          image.Rectangle
+	}{
+		{200, 150, image.Rect(0, 0, 200, 150)},
+		{1, 1, image.Rect(0, 0, 1, 1)},
+		{400, 300, image.Rect(0, 0, 400, 300)},
+		// No upscaling: larger dimensions are clamped to the source
+		{800, 600, image.Rect(0, 0, 400, 300)},
+		{800, 150, image.Rect(0, 0, 400, 150)},
+		{0, -5, image.Rect(0, 0, 1, 1)},
+	}
+	for _, tt := range tests {
+		if got := Resize(src, tt.width, tt.height).Rect; got != tt.want {
+			t.Errorf("Resize(400x300, %d, %d) bounds = %v, want %v", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestResizeAveragesPixels(t *testing.T) {
+	// A uniform image stays the same color at any size
+	c := color.RGBA{200, 100, 50, 255}
+	for _, size := range [][2]int{{7, 5}, {3, 3}, {1, 1}} {
+		dst := Resize(uniform(20, 15, c), size[0], size[1])
+		for y := 0; y < size[1]; y++ {
+			for x := 0; x < size[0]; x++ {
+				if got := dst.RGBAAt(x, y); got != c {
+					t.Fatalf("Resize to %dx%d: pixel (%d,%d) = %v, want %v", size[0], size[1], x, y, got, c)
+				}
+			}
+		}
+	}
+
+	// Black and white columns average to grey
+	stripes := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			v := uint8(255 * (x % 2))
+			stripes.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	if got, want := Resize(stripes, 2, 1).RGBAAt(0, 0), (color.RGBA{128, 128, 128, 255}); got != want {
+		t.Errorf("Resize of stripes = %v, want %v", got, want)
+	}
+}
+
+func TestBoxWeightsSumToOne(t *testing.T) {
+	for _, tt := range [][2]int{{10, 3}, {1080, 320}, {7, 7}, {5, 1}} {
+		for i, sources := range boxWeights(tt[0], tt[1]) {
+			sum := 0.0
+			for _, s := range sources {
+				sum += s.weight
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("boxWeights(%d, %d)[%d] sums to %v, want 1", tt[0], tt[1], i, sum)
+			}
+		}
+	}
+}
+
+func TestToRGBAMovesBoundsToOrigin(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(5, 5, 15, 10))
+	src.Set(5, 5, color.NRGBA{255, 0, 0, 255})
+	got := ToRGBA(src)
+	if got.Rect != image.Rect(0, 0, 10, 5) {
+		t.Errorf("ToRGBA bounds = %v, want (0,0)-(10,5)", got.Rect)
+	}
+	if c := got.RGBAAt(0, 0); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("ToRGBA pixel (0,0) = %v, want red", c)
+	}
+}
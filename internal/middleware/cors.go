@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"appdrop-api/internal/utils"
+)
+
+// CORS is an HTTP middleware that adds cross-origin headers for allowed origins.
+// An origin of "*" allows any caller. Preflight OPTIONS requests from allowed
+// origins are answered directly with 204 No Content.
+// With no allowed origins configured, requests pass through untouched.
+// Preflights allow the client context and user ID headers of the public reads along
+// with Content-Type and Authorization.
+func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
+	headers := []string{"Content-Type", "Authorization"}
+	for _, header := range utils.ClientContextHeaders {
+		headers = append(headers, header)
+	}
+	slices.Sort(headers[2:])
+	headers = append(headers, utils.UserIDHeader)
+	allowHeaders := strings.Join(headers, ", ")
+
+	allowAll := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" || !(allowAll || allowed[origin]) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Asset is an uploaded media file, kept in blob storage and referenced from widget
+// configs by asset_id (e.g. a banner's background image).
+type Asset struct {
+	// ID is a UUID that uniquely identifies the asset
+	ID string `json:"id"`
+	// Filename is the name of the uploaded file, without directories
+	Filename string `json:"filename"`
+	// MimeType is the detected content type, e.g. "image/png"
+	MimeType string `json:"mime_type"`
+	// SizeBytes is the size of the stored file
+	SizeBytes int64 `json:"size_bytes"`
+	// Width is the image width in pixels
+	Width int `json:"width"`
+	// Height is the image height in pixels
+	Height int `json:"height"`
+	// Checksum is the hex-encoded SHA-256 of the file contents
+	Checksum string `json:"checksum"`
+	// URL is the path the file is served from
+	URL string `json:"url"`
+	// VariantsStatus is "pending" or "processing" until resized variants have been
+	// generated, then "ready" or "failed"
+	VariantsStatus string `json:"variants_status"`
+	// Variants are the resized copies of the image, narrowest first
+	Variants []AssetVariant `json:"variants"`
+	// SrcSet lists the variants and the original as an HTML srcset value,
+	// e.g. "/assets/{id}/variants/320 320w, /assets/{id}/content 1200w"
+	SrcSet string `json:"srcset"`
+	// StorageKey is where the file is kept in blob storage
+	StorageKey string `json:"-"`
+	// CreatedAt is the timestamp when the asset was uploaded
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AssetVariant is a resized copy of an asset's image, generated in the background.
+type AssetVariant struct {
+	// Width is the variant width in pixels; the aspect ratio is the original's
+	Width int `json:"width"`
+	// Height is the variant height in pixels
+	Height int `json:"height"`
+	// MimeType is the variant's content type, which can differ from the original's
+	MimeType string `json:"mime_type"`
+	// SizeBytes is the size of the stored file
+	SizeBytes int64 `json:"size_bytes"`
+	// URL is the path the file is served from
+	URL string `json:"url"`
+	// StorageKey is where the file is kept in blob storage
+	StorageKey string `json:"-"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// WidgetNode is a widget definition that is not attached to any page:
+// a type, its configuration and (for containers) nested child definitions.
+// Components store their content as a WidgetNode tree.
+type WidgetNode struct {
+	// Type specifies the widget category (same values as Widget.Type)
+	Type string `json:"type"`
+	// ComponentID is the referenced component when Type is "component"
+	ComponentID *string `json:"component_id,omitempty"`
+	// Config holds widget-specific configuration as JSON
+	Config map[string]interface{} `json:"config"`
+	// Children holds nested definitions for container types
+	Children []WidgetNode `json:"children,omitempty"`
+}
+
+// Component is a named, reusable widget (or widget subtree) shared across pages.
+// Pages reference a component through a widget of type "component"; editing the
+// component changes every referencing page.
+type Component struct {
+	// ID is a UUID that uniquely identifies the component
+	ID string `json:"id"`
+	// Name is the unique human-readable name shown in the components library
+	Name string `json:"name"`
+	// Description explains what the component is for
+	Description string `json:"description"`
+	// Root is the component's widget definition, including any nested children
+	Root WidgetNode `json:"root"`
+	// CreatedAt is the timestamp when the component was created
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the timestamp when the component was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ComponentUsage identifies a widget that references a component and the page it is on.
+type ComponentUsage struct {
+	// WidgetID is the referencing widget
+	WidgetID string `json:"widget_id"`
+	// PageID is the page holding the referencing widget
+	PageID string `json:"page_id"`
+	// PageName is the name of that page
+	PageName string `json:"page_name"`
+	// PageRoute is the route of that page
+	PageRoute string `json:"page_route"`
+}
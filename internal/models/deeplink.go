@@ -0,0 +1,84 @@
+package models
+
+// RouteTable lists the app paths that open a page, for configuring deep links in the
+// mobile apps.
+type RouteTable struct {
+	// Routes lists the page routes, sorted by route
+	Routes []RouteTableEntry `json:"routes"`
+	// Redirects lists the redirects, sorted by from_route
+	Redirects []RouteTableRedirect `json:"redirects"`
+}
+
+// RouteTableEntry is a page route in the route table.
+type RouteTableEntry struct {
+	// PageID is the page the route opens
+	PageID string `json:"page_id"`
+	// PageName is the page's name in the default locale
+	PageName string `json:"page_name"`
+	// Route is the page's route pattern, e.g. "/products/:productId"
+	Route string `json:"route"`
+	// IsHome indicates the home page
+	IsHome bool `json:"is_home"`
+	// Params lists the route's parameter names in order
+	Params []string `json:"params"`
+	// IOSPattern is the route as an apple-app-site-association path, e.g. "/products/*"
+	IOSPattern string `json:"ios_pattern"`
+	// AndroidPattern is the route as an Android intent filter pathPattern, e.g. "/products/.*"
+	AndroidPattern string `json:"android_pattern"`
+}
+
+// RouteTableRedirect is a redirect in the route table.
+type RouteTableRedirect struct {
+	// RedirectID is the redirect
+	RedirectID string `json:"redirect_id"`
+	// FromRoute is the route pattern redirected
+	FromRoute string `json:"from_route"`
+	// ToRoute is the route redirected to: the page's current route or the redirect's to_route
+	ToRoute string `json:"to_route"`
+	// PageID is the page redirected to (nil for a redirect to a route)
+	PageID *string `json:"page_id"`
+	// IOSPattern is the from_route as an apple-app-site-association path
+	IOSPattern string `json:"ios_pattern"`
+	// AndroidPattern is the from_route as an Android intent filter pathPattern
+	AndroidPattern string `json:"android_pattern"`
+}
+
+// AppleAppSiteAssociation is the apple-app-site-association file that lets iOS apps
+// open universal links.
+type AppleAppSiteAssociation struct {
+	AppLinks AppleAppLinks `json:"applinks"`
+}
+
+// AppleAppLinks lists the apps that open universal links and the paths they open.
+type AppleAppLinks struct {
+	Details []AppleAppLinkDetail `json:"details"`
+}
+
+// AppleAppLinkDetail lists the paths a set of apps open.
+type AppleAppLinkDetail struct {
+	// AppIDs are the apps' IDs, "TEAMID.bundle.id"
+	AppIDs []string `json:"appIDs"`
+	// Components are the path patterns the apps open
+	Components []AppleAppLinkComponent `json:"components"`
+}
+
+// AppleAppLinkComponent is a path pattern in apple-app-site-association, where "*"
+// matches any characters.
+type AppleAppLinkComponent struct {
+	Path    string `json:"/"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// AssetLink is a statement in an assetlinks.json file that lets an Android app open
+// app links.
+type AssetLink struct {
+	Relation []string        `json:"relation"`
+	Target   AssetLinkTarget `json:"target"`
+}
+
+// AssetLinkTarget identifies the Android app in an AssetLink.
+type AssetLinkTarget struct {
+	Namespace    string   `json:"namespace"`
+	PackageName  string   `json:"package_name"`
+	Fingerprints []string `json:"sha256_cert_fingerprints"`
+}
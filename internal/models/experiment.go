@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Experiment is an A/B test of a page's layout. Public readers that send a user ID
+// are bucketed deterministically into one of the variants by traffic weight.
+type Experiment struct {
+	// ID is a UUID that uniquely identifies the experiment
+	ID string `json:"id"`
+	// Name is the unique human-readable name of the experiment
+	Name string `json:"name"`
+	// PageID is the page whose layout is tested
+	PageID string `json:"page_id"`
+	// Status is "draft", "running" or "stopped"; only running experiments assign variants
+	Status string `json:"status"`
+	// Variants are the layouts tested against each other
+	Variants []ExperimentVariant `json:"variants"`
+	// CreatedAt is the timestamp when the experiment was created
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the timestamp when the experiment was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExperimentVariant is one layout in an experiment: the page as it is, the page with
+// widget overrides applied, or the widgets of an alternate page version.
+type ExperimentVariant struct {
+	// Key identifies the variant within the experiment (e.g. "control", "b")
+	Key string `json:"key"`
+	// Weight is the variant's share of traffic relative to the other variants' weights
+	Weight int `json:"weight"`
+	// PageID is an alternate page whose widgets replace the experiment page's widgets
+	PageID *string `json:"page_id"`
+	// Overrides change or hide widgets of the experiment page
+	Overrides []WidgetOverride `json:"overrides"`
+}
+
+// WidgetOverride changes one widget of the experiment page for a variant.
+type WidgetOverride struct {
+	// WidgetID is the widget on the experiment page to change
+	WidgetID string `json:"widget_id"`
+	// Config holds config fields that replace the widget's own values
+	Config map[string]interface{} `json:"config,omitempty"`
+	// Hidden removes the widget (and its children) from the variant
+	Hidden bool `json:"hidden,omitempty"`
+}
+
+// ExperimentAssignment is the variant a user was bucketed into.
+type ExperimentAssignment struct {
+	// ExperimentID is the experiment the assignment belongs to
+	ExperimentID string `json:"experiment_id"`
+	// PageID is the page the experiment tests
+	PageID string `json:"page_id"`
+	// Variant is the assigned variant's key
+	Variant string `json:"variant"`
+}
+
+// ExperimentExposure records the first time a user was shown a variant.
+type ExperimentExposure struct {
+	// UserID is the client-supplied user ID
+	UserID string `json:"user_id"`
+	// Variant is the key of the variant shown
+	Variant string `json:"variant"`
+	// ExposedAt is when the user was first shown the variant
+	ExposedAt time.Time `json:"exposed_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// HealthCheck is the result of a single dependency check performed by a probe.
+type HealthCheck struct {
+	// Name identifies the check (e.g., "database", "migrations", "pool")
+	Name string `json:"name"`
+	// Status is "pass" or "fail"
+	Status string `json:"status"`
+	// LatencyMS is how long the check took in milliseconds
+	LatencyMS float64 `json:"latency_ms"`
+	// Message gives detail on the result, e.g. the failure reason
+	Message string `json:"message,omitempty"`
+}
+
+// HealthReport is the response body of the liveness and readiness probes.
+type HealthReport struct {
+	// Status is "pass" when every check passed, otherwise "fail"
+	Status string `json:"status"`
+	// Checks lists the individual dependency checks (empty for liveness)
+	Checks []HealthCheck `json:"checks"`
+}
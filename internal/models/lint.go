@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// LintIssue is a problem found in a page, component or navigation item.
+type LintIssue struct {
+	// Rule names the check that found the problem, e.g. "broken_link"
+	Rule string `json:"rule"`
+	// Severity is "error" or "warning"
+	Severity string `json:"severity"`
+	// PageID is the page the problem is on (nil for components and navigation)
+	PageID *string `json:"page_id"`
+	// WidgetID is the widget the problem is in, if any
+	WidgetID *string `json:"widget_id,omitempty"`
+	// ComponentID is the component the problem is in, if any
+	ComponentID *string `json:"component_id,omitempty"`
+	// NavigationItemID is the navigation item the problem is in, if any
+	NavigationItemID *string `json:"navigation_item_id,omitempty"`
+	// Field is the config field at fault, e.g. "config.link" (empty if not about a field)
+	Field string `json:"field,omitempty"`
+	// Message describes the problem
+	Message string `json:"message"`
+	// Hint suggests how to fix the problem
+	Hint string `json:"hint,omitempty"`
+}
+
+// LintReport lists the problems found by a lint run.
+type LintReport struct {
+	// Issues lists the problems, errors first
+	Issues []LintIssue `json:"issues"`
+	// Errors counts the issues with severity "error"
+	Errors int `json:"errors"`
+	// Warnings counts the issues with severity "warning"
+	Warnings int `json:"warnings"`
+}
+
+// PageLink is a widget or component whose config links to a page.
+type PageLink struct {
+	// WidgetID is the linking widget (nil for a component definition)
+	WidgetID *string `json:"widget_id"`
+	// PageID is the page holding the linking widget (nil for a component definition)
+	PageID *string `json:"page_id"`
+	// ComponentID is the component whose definition links to the page, if any
+	ComponentID *string `json:"component_id,omitempty"`
+	// Field is the config field holding the link, e.g. "config.link"
+	Field string `json:"field"`
+	// Link is the app path linked to
+	Link string `json:"link"`
+}
+
+// LintRule describes a check the page linter runs.
+type LintRule struct {
+	// Name identifies the rule, e.g. "empty_text"
+	Name string `json:"name"`
+	// Description explains what the rule looks for
+	Description string `json:"description"`
+	// Category groups related rules: layout, content, links or accessibility
+	Category string `json:"category"`
+	// DefaultSeverity is the rule's severity unless the lint settings override it
+	DefaultSeverity string `json:"default_severity"`
+	// Severity is the rule's current severity: "error", "warning" or "off"
+	Severity string `json:"severity"`
+}
+
+// LintSettings configures the page linter for the app.
+type LintSettings struct {
+	// Severities overrides rule severities by rule name: "error", "warning" or "off"
+	Severities map[string]string `json:"severities"`
+	// PublishGate keeps drafts with lint errors from being published
+	PublishGate bool `json:"publish_gate"`
+	// UpdatedAt is the timestamp when the settings were last changed
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// LocaleSettings lists the locales the app is published in.
+// Page names and widget configs are stored in the default locale;
+// the other locales are stored as translations.
+type LocaleSettings struct {
+	// Locales are BCP 47 codes (e.g., "en", "fr", "pt-BR"), default first
+	Locales []string `json:"locales"`
+	// Default is the locale of the base content and the fallback for missing translations
+	Default string `json:"default"`
+}
+
+// PageTranslation is a page's name in one non-default locale.
+type PageTranslation struct {
+	// PageID is the translated page
+	PageID string `json:"page_id"`
+	// Locale is the translation's locale code
+	Locale string `json:"locale"`
+	// Name is the translated page name
+	Name string `json:"name"`
+	// UpdatedAt is the timestamp when the translation was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WidgetTranslation holds a widget's translatable config fields in one non-default locale.
+// Fields not present fall back to the widget's own config.
+type WidgetTranslation struct {
+	// WidgetID is the translated widget
+	WidgetID string `json:"widget_id"`
+	// Locale is the translation's locale code
+	Locale string `json:"locale"`
+	// Fields maps config field names (see utils.TranslatableWidgetFields) to translated text
+	Fields map[string]string `json:"fields"`
+	// UpdatedAt is the timestamp when the translation was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PageTranslations is every stored translation for a page and its widgets.
+type PageTranslations struct {
+	// Page holds the page name translations
+	Page []PageTranslation `json:"page"`
+	// Widgets holds the widget field translations
+	Widgets []WidgetTranslation `json:"widgets"`
+}
+
+// MissingTranslations reports, for one page, which translatable content is missing per locale.
+type MissingTranslations struct {
+	// PageID is the checked page
+	PageID string `json:"page_id"`
+	// DefaultLocale is the locale of the base content, which is never missing
+	DefaultLocale string `json:"default_locale"`
+	// Locales has one entry per non-default locale
+	Locales []LocaleMissingTranslations `json:"locales"`
+}
+
+// LocaleMissingTranslations lists the content missing in one locale.
+type LocaleMissingTranslations struct {
+	// Locale is the locale code
+	Locale string `json:"locale"`
+	// PageName is set when the page name has no translation
+	PageName bool `json:"page_name"`
+	// Widgets lists widgets with untranslated fields
+	Widgets []MissingWidgetFields `json:"widgets"`
+	// Complete is set when nothing is missing in this locale
+	Complete bool `json:"complete"`
+}
+
+// MissingWidgetFields lists a widget's untranslated fields.
+type MissingWidgetFields struct {
+	// WidgetID is the widget
+	WidgetID string `json:"widget_id"`
+	// Type is the widget type
+	Type string `json:"type"`
+	// Fields are the config fields with base text but no translation
+	Fields []string `json:"fields"`
+}
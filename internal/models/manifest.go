@@ -0,0 +1,21 @@
+package models
+
+// Manifest is everything the mobile app needs at startup to build its shell:
+// the list of pages it can route to, how users navigate between them and the theme.
+// Page content is fetched separately with GET /pages/:id.
+type Manifest struct {
+	// HomePageID is the page shown on launch, or nil if no page is marked as home
+	HomePageID *string `json:"home_page_id"`
+	// Pages lists every page (without widgets)
+	Pages []Page `json:"pages"`
+	// Navigation is the tab bar and drawer configuration
+	Navigation *Navigation `json:"navigation"`
+	// Theme holds the current design token values, including dark-mode overrides
+	Theme *Theme `json:"theme"`
+	// Locale is the locale page names are returned in
+	Locale string `json:"locale"`
+	// Locales lists every locale the app is available in
+	Locales *LocaleSettings `json:"locales"`
+	// Experiments are the user's variants in running experiments (public manifest with a user ID only)
+	Experiments []ExperimentAssignment `json:"experiments,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// NavigationItem is one entry of the tab bar or drawer menu.
+// It targets either a page (PageID) or an external URL, never both.
+type NavigationItem struct {
+	// ID is a UUID that uniquely identifies the item
+	ID string `json:"id"`
+	// Label is the text shown for the item
+	Label string `json:"label"`
+	// Icon names the icon shown for the item (required in the tab bar)
+	Icon string `json:"icon"`
+	// PageID is the target page, or nil for URL items and broken items
+	PageID *string `json:"page_id"`
+	// Route is the target page's current route (read-only, page items only)
+	Route *string `json:"route,omitempty"`
+	// URL is the external link target, or nil for page items
+	URL *string `json:"url"`
+	// Broken is set when the target page was deleted and the item needs a new target
+	Broken bool `json:"broken"`
+}
+
+// Navigation describes how users move between pages in the app.
+// Items are listed in display order.
+type Navigation struct {
+	// TabBar holds the bottom tab bar items
+	TabBar []NavigationItem `json:"tab_bar"`
+	// Drawer holds the side drawer menu items
+	Drawer []NavigationItem `json:"drawer"`
+}
@@ -6,6 +6,7 @@ import "time"
 // Page represents a screen or view in a mobile application.
 // Each page has a unique route and can contain multiple widgets.
 // Only one page can be designated as the home page (is_home=true).
+// Public reads only show published pages inside their visibility window.
 type Page struct {
 	// ID is a UUID that uniquely identifies the page
 	ID string `json:"id"`
@@ -15,8 +16,30 @@ type Page struct {
 	Route string `json:"route"`
 	// IsHome indicates if this is the application's home/default page
 	IsHome bool `json:"is_home"`
+	// Status is "published" or "draft"; drafts are never shown on public reads
+	Status string `json:"status"`
+	// PublishAt is when the publish job turns a draft into a published page
+	PublishAt *time.Time `json:"publish_at"`
+	// VisibleFrom is when the page starts appearing on public reads (nil for always)
+	VisibleFrom *time.Time `json:"visible_from"`
+	// VisibleUntil is when the page stops appearing on public reads (nil for never)
+	VisibleUntil *time.Time `json:"visible_until"`
 	// CreatedAt is the timestamp when the page was created
 	CreatedAt time.Time `json:"created_at"`
 	// UpdatedAt is the timestamp when the page was last modified
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// RouteMatch is the page an app path resolves to, with the values of its route
+// parameters (e.g. {"productId": "123"} for "/products/123" and "/products/:productId")
+// and the redirects followed to reach it.
+type RouteMatch struct {
+	// Page is the matching page
+	Page Page `json:"page"`
+	// Params maps parameter names to their unescaped path segments
+	Params map[string]string `json:"params"`
+	// Path is the path that matched the page: the requested path, or where redirects led
+	Path string `json:"path"`
+	// Redirects lists the redirects followed, in order (empty if the path matched directly)
+	Redirects []RedirectHop `json:"redirects"`
+}
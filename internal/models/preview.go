@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// PreviewLink shares a non-public view of a page, usually a draft, until it expires
+// or is revoked. Anyone holding the token can open the preview.
+type PreviewLink struct {
+	// ID is a UUID that uniquely identifies the link
+	ID string `json:"id"`
+	// PageID is the page the link previews
+	PageID string `json:"page_id"`
+	// Label describes who or what the link was shared for
+	Label string `json:"label"`
+	// Token is the signed token that opens the preview
+	Token string `json:"token"`
+	// Path is the public preview URL path for the token
+	Path string `json:"path"`
+	// ExpiresAt is when the link stops working
+	ExpiresAt time.Time `json:"expires_at"`
+	// RevokedAt is when the link was revoked, or nil while it is usable
+	RevokedAt *time.Time `json:"revoked_at"`
+	// CreatedAt is the timestamp when the link was issued
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Product is an item in the app's catalog, listed by product_grid widgets either
+// directly or through a collection.
+type Product struct {
+	// ID is a UUID that uniquely identifies the product
+	ID string `json:"id"`
+	// SKU is the unique stock keeping unit code, e.g. "TSHIRT-BLK-M"
+	SKU string `json:"sku"`
+	// Name is the product name shown to users
+	Name string `json:"name"`
+	// Description is optional longer text about the product
+	Description string `json:"description"`
+	// PriceCents is the price in minor units of Currency (1999 is 19.99)
+	PriceCents int64 `json:"price_cents"`
+	// Currency is the ISO 4217 currency code, e.g. "USD"
+	Currency string `json:"currency"`
+	// Images are the product's pictures, the first being the main one
+	Images []ProductImage `json:"images"`
+	// InStock reports whether the product can currently be bought
+	InStock bool `json:"in_stock"`
+	// CreatedAt is the timestamp when the product was created
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the timestamp when the product was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductImage is one picture of a product: an uploaded asset or an external URL.
+type ProductImage struct {
+	// AssetID is the uploaded asset showing the product, if the image is an asset
+	AssetID *string `json:"asset_id,omitempty"`
+	// URL is the image location; for assets it is filled in with the asset's file path
+	URL string `json:"url"`
+	// AltText describes the image for screen readers
+	AltText string `json:"alt_text"`
+}
+
+// Collection is a named, ordered list of products, such as "Summer sale".
+type Collection struct {
+	// ID is a UUID that uniquely identifies the collection
+	ID string `json:"id"`
+	// Name is the unique collection name
+	Name string `json:"name"`
+	// Description is optional text about the collection
+	Description string `json:"description"`
+	// ProductIDs lists the collection's products in display order
+	ProductIDs []string `json:"product_ids"`
+	// CreatedAt is the timestamp when the collection was created
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the timestamp when the collection was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Redirect sends app paths matching an old or alternative route to a page, or to another
+// route. Route parameters are carried over by name, or by position when the target
+// uses other names.
+type Redirect struct {
+	// ID is a UUID that uniquely identifies the redirect
+	ID string `json:"id"`
+	// FromRoute is the route pattern redirected, e.g. "/product/:id"
+	FromRoute string `json:"from_route"`
+	// PageID is the page redirected to, following its current route (nil if ToRoute is set)
+	PageID *string `json:"page_id"`
+	// ToRoute is the route pattern redirected to (nil if PageID is set)
+	ToRoute *string `json:"to_route"`
+	// Automatic is true for redirects recorded when a page's route changed
+	Automatic bool `json:"automatic"`
+	// CreatedAt is the timestamp when the redirect was created
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the timestamp when the redirect was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RedirectHop is one redirect followed while resolving an app path.
+type RedirectHop struct {
+	// RedirectID is the redirect that was followed
+	RedirectID string `json:"redirect_id"`
+	// From is the path that was redirected
+	From string `json:"from"`
+	// To is the path it was redirected to
+	To string `json:"to"`
+}
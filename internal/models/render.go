@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"appdrop-api/internal/targeting"
+)
+
+// RenderOptions control how a page is rendered for a read request.
+type RenderOptions struct {
+	// Resolve substitutes {{...}} references in widget configs; false returns configs as stored
+	Resolve bool
+	// ColorScheme is "light" or "dark" and selects which color token values are substituted
+	ColorScheme string
+	// Locale is the explicitly requested locale (?locale=), or "" to negotiate
+	Locale string
+	// AcceptLanguage is the request's Accept-Language header, used when Locale is ""
+	AcceptLanguage string
+	// Public limits the read to what app users can see: published pages, and pages
+	// and widgets inside their visibility window at At
+	Public bool
+	// At is the time visibility windows are evaluated against on public reads
+	At time.Time
+	// Client is the client context widget targeting rules are matched against on public reads
+	Client targeting.Context
+	// Preview serves the page regardless of its status and visibility window (public
+	// reads through a preview link); widgets are still filtered
+	Preview bool
+	// UserID is the client-supplied user ID bucketed into experiment variants on public reads
+	UserID string
+}
@@ -0,0 +1,322 @@
+package models
+
+import "time"
+
+// PageRequest is the request body for creating or updating a page.
+// Only client-editable fields are declared; read-only fields such as id,
+// created_at and updated_at are rejected by the strict JSON decoder.
+type PageRequest struct {
+	// Name is the human-readable title of the page
+	Name string `json:"name"`
+	// Route is the unique URL path of the page
+	Route string `json:"route"`
+	// IsHome makes this page the application's home page
+	IsHome bool `json:"is_home"`
+	// Status is "published" or "draft" (defaults to published)
+	Status string `json:"status"`
+	// PublishAt schedules a draft to be published at this time
+	PublishAt *time.Time `json:"publish_at"`
+	// VisibleFrom is when the page starts appearing on public reads
+	VisibleFrom *time.Time `json:"visible_from"`
+	// VisibleUntil is when the page stops appearing on public reads
+	VisibleUntil *time.Time `json:"visible_until"`
+}
+
+// ToPage converts the request into a Page for the service layer.
+func (r PageRequest) ToPage() Page {
+	return Page{
+		Name: r.Name, Route: r.Route, IsHome: r.IsHome,
+		Status: r.Status, PublishAt: r.PublishAt,
+		VisibleFrom: r.VisibleFrom, VisibleUntil: r.VisibleUntil,
+	}
+}
+
+// WidgetRequest is the request body for creating a widget.
+// The page a widget belongs to comes from the URL, never from the body.
+type WidgetRequest struct {
+	// ParentID is the container widget to create this widget in (nil for top level)
+	ParentID *string `json:"parent_id"`
+	// Type specifies the widget category
+	Type string `json:"type"`
+	// ComponentID is the component to reference when Type is "component"
+	ComponentID *string `json:"component_id"`
+	// Position is the order index of the widget on its page (0-based)
+	Position int `json:"position"`
+	// Config holds widget-specific configuration
+	Config map[string]interface{} `json:"config"`
+	// VisibleFrom is when the widget starts appearing on public reads
+	VisibleFrom *time.Time `json:"visible_from"`
+	// VisibleUntil is when the widget stops appearing on public reads
+	VisibleUntil *time.Time `json:"visible_until"`
+	// Targeting is an audience rule limiting who sees the widget (nil for everyone)
+	Targeting *string `json:"targeting"`
+}
+
+// ToWidget converts the request into a Widget for the service layer.
+func (r WidgetRequest) ToWidget() Widget {
+	return Widget{
+		ParentID: r.ParentID, Type: r.Type, ComponentID: r.ComponentID, Position: r.Position, Config: r.Config,
+		VisibleFrom: r.VisibleFrom, VisibleUntil: r.VisibleUntil, Targeting: r.Targeting,
+	}
+}
+
+// UpdateWidgetRequest is the request body for updating a widget. It has no parent_id,
+// which the strict JSON decoder therefore rejects; MoveWidgetRequest re-parents a widget.
+type UpdateWidgetRequest struct {
+	// Type specifies the widget category
+	Type string `json:"type"`
+	// ComponentID is the component to reference when Type is "component"
+	ComponentID *string `json:"component_id"`
+	// Position is the order index of the widget among its siblings (0-based)
+	Position int `json:"position"`
+	// Config holds widget-specific configuration
+	Config map[string]interface{} `json:"config"`
+	// VisibleFrom is when the widget starts appearing on public reads
+	VisibleFrom *time.Time `json:"visible_from"`
+	// VisibleUntil is when the widget stops appearing on public reads
+	VisibleUntil *time.Time `json:"visible_until"`
+	// Targeting is an audience rule limiting who sees the widget (nil for everyone)
+	Targeting *string `json:"targeting"`
+}
+
+// ToWidget converts the request into a Widget for the service layer.
+func (r UpdateWidgetRequest) ToWidget() Widget {
+	return Widget{
+		Type: r.Type, ComponentID: r.ComponentID, Position: r.Position, Config: r.Config,
+		VisibleFrom: r.VisibleFrom, VisibleUntil: r.VisibleUntil, Targeting: r.Targeting,
+	}
+}
+
+// ReorderWidgetsRequest is the request body for reordering a page's widgets.
+// Widgets are reordered among siblings: the top level, or the children of ParentID.
+type ReorderWidgetsRequest struct {
+	// ParentID is the container whose children are reordered (nil for top level)
+	ParentID *string `json:"parent_id"`
+	// WidgetIDs lists the page's widget IDs in their new order
+	WidgetIDs []string `json:"widget_ids"`
+}
+
+// MoveWidgetRequest is the request body for moving a widget within its page's tree.
+type MoveWidgetRequest struct {
+	// ParentID is the new container for the widget (nil to move it to the top level)
+	ParentID *string `json:"parent_id"`
+	// Position is the index among the new siblings (clamped to the end of the list)
+	Position int `json:"position"`
+}
+
+// ComponentRequest is the request body for creating or updating a component.
+// The definition comes either from Root or, on create, from copying an existing
+// widget subtree identified by SourceWidgetID.
+type ComponentRequest struct {
+	// Name is the unique component name
+	Name string `json:"name"`
+	// Description explains what the component is for
+	Description string `json:"description"`
+	// Root is the component's widget definition
+	Root *WidgetNode `json:"root"`
+	// SourceWidgetID copies an existing widget and its children as the definition (create only)
+	SourceWidgetID *string `json:"source_widget_id"`
+}
+
+// TemplateRequest is the request body for saving a page template.
+// Exactly one of Widgets or SourcePageID must be given.
+type TemplateRequest struct {
+	// Name is the unique human-readable name of the template
+	Name string `json:"name"`
+	// Category groups templates in the library
+	Category string `json:"category"`
+	// Description explains what the template is for
+	Description string `json:"description"`
+	// PreviewImageURL is a screenshot or illustration of the template
+	PreviewImageURL string `json:"preview_image_url"`
+	// Widgets is an explicit list of top-level widget definitions
+	Widgets []WidgetNode `json:"widgets"`
+	// SourcePageID is a page whose current widgets are copied into the template
+	SourcePageID *string `json:"source_page_id"`
+}
+
+// ToTemplate converts the request into a Template for the service layer.
+func (r TemplateRequest) ToTemplate() Template {
+	return Template{
+		Name:            r.Name,
+		Category:        r.Category,
+		Description:     r.Description,
+		PreviewImageURL: r.PreviewImageURL,
+		Widgets:         r.Widgets,
+	}
+}
+
+// NavigationItemRequest is one menu item in a NavigationRequest.
+type NavigationItemRequest struct {
+	// Label is the text shown for the item
+	Label string `json:"label"`
+	// Icon names the icon shown for the item
+	Icon string `json:"icon"`
+	// PageID is the target page (mutually exclusive with URL)
+	PageID *string `json:"page_id"`
+	// URL is an external http(s) link (mutually exclusive with PageID)
+	URL *string `json:"url"`
+}
+
+// NavigationRequest is the request body for replacing the app navigation.
+// Items are stored in the order given.
+type NavigationRequest struct {
+	// TabBar lists the bottom tab bar items
+	TabBar []NavigationItemRequest `json:"tab_bar"`
+	// Drawer lists the side drawer menu items
+	Drawer []NavigationItemRequest `json:"drawer"`
+}
+
+// ToNavigation converts the request into a Navigation for the service layer.
+func (r NavigationRequest) ToNavigation() Navigation {
+	convert := func(items []NavigationItemRequest) []NavigationItem {
+		result := make([]NavigationItem, 0, len(items))
+		for _, item := range items {
+			result = append(result, NavigationItem{Label: item.Label, Icon: item.Icon, PageID: item.PageID, URL: item.URL})
+		}
+		return result
+	}
+	return Navigation{TabBar: convert(r.TabBar), Drawer: convert(r.Drawer)}
+}
+
+// ThemeRequest is the request body for replacing the app theme.
+type ThemeRequest struct {
+	// Colors maps color token names to hex values
+	Colors map[string]string `json:"colors"`
+	// Typography maps text style names to font settings
+	Typography map[string]TypographyToken `json:"typography"`
+	// Spacing maps spacing token names to sizes in points
+	Spacing map[string]float64 `json:"spacing"`
+	// Radius maps corner radius token names to sizes in points
+	Radius map[string]float64 `json:"radius"`
+	// DarkColors overrides color tokens in dark mode
+	DarkColors map[string]string `json:"dark_colors"`
+}
+
+// ToTheme converts the request into a Theme for the service layer.
+func (r ThemeRequest) ToTheme() Theme {
+	return Theme{Colors: r.Colors, Typography: r.Typography, Spacing: r.Spacing, Radius: r.Radius, DarkColors: r.DarkColors}
+}
+
+// LocaleSettingsRequest is the request body for replacing the app's locales.
+// Removing a locale deletes its translations.
+type LocaleSettingsRequest struct {
+	// Locales are BCP 47 codes; must include Default
+	Locales []string `json:"locales"`
+	// Default is the locale of the base content
+	Default string `json:"default"`
+}
+
+// PageTranslationRequest is the request body for translating a page name.
+type PageTranslationRequest struct {
+	// Name is the translated page name
+	Name string `json:"name"`
+}
+
+// WidgetTranslationRequest is the request body for translating a widget's config fields.
+type WidgetTranslationRequest struct {
+	// Fields maps translatable config field names to translated text
+	Fields map[string]string `json:"fields"`
+}
+
+// ExperimentRequest is the request body for creating or replacing an experiment.
+type ExperimentRequest struct {
+	// Name is the unique human-readable name of the experiment
+	Name string `json:"name"`
+	// PageID is the page whose layout is tested
+	PageID string `json:"page_id"`
+	// Status is "draft" (default on create), "running" or "stopped"
+	Status string `json:"status"`
+	// Variants are the layouts tested against each other
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// ToExperiment converts the request into an Experiment for the service layer.
+func (r ExperimentRequest) ToExperiment() Experiment {
+	return Experiment{Name: r.Name, PageID: r.PageID, Status: r.Status, Variants: r.Variants}
+}
+
+// PreviewLinkRequest is the request body for issuing a preview link.
+type PreviewLinkRequest struct {
+	// Label describes who or what the link is shared for
+	Label string `json:"label"`
+	// ExpiresIn is how long the link is valid, as a duration such as "48h" (default from config)
+	ExpiresIn string `json:"expires_in"`
+}
+
+// ProductRequest is the request body for creating or updating a product.
+type ProductRequest struct {
+	// SKU is the unique stock keeping unit code
+	SKU string `json:"sku"`
+	// Name is the product name shown to users
+	Name string `json:"name"`
+	// Description is optional longer text about the product
+	Description string `json:"description"`
+	// PriceCents is the price in minor units of Currency
+	PriceCents int64 `json:"price_cents"`
+	// Currency is the ISO 4217 currency code
+	Currency string `json:"currency"`
+	// Images are the product's pictures, each an asset_id or a url
+	Images []ProductImage `json:"images"`
+	// InStock reports whether the product can be bought (defaults to true)
+	InStock *bool `json:"in_stock"`
+}
+
+// ToProduct converts the request into a Product for the service layer.
+func (r ProductRequest) ToProduct() Product {
+	inStock := r.InStock == nil || *r.InStock
+	return Product{
+		SKU: r.SKU, Name: r.Name, Description: r.Description,
+		PriceCents: r.PriceCents, Currency: r.Currency, Images: r.Images, InStock: inStock,
+	}
+}
+
+// CollectionRequest is the request body for creating or updating a collection.
+type CollectionRequest struct {
+	// Name is the unique collection name
+	Name string `json:"name"`
+	// Description is optional text about the collection
+	Description string `json:"description"`
+	// ProductIDs lists the collection's products in display order
+	ProductIDs []string `json:"product_ids"`
+}
+
+// ToCollection converts the request into a Collection for the service layer.
+func (r CollectionRequest) ToCollection() Collection {
+	return Collection{Name: r.Name, Description: r.Description, ProductIDs: r.ProductIDs}
+}
+
+// StoreVariablesRequest is the request body for replacing the store variables.
+type StoreVariablesRequest struct {
+	// Variables maps variable names to string, number or boolean values
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// RedirectRequest is the request body for creating or updating a redirect.
+// Exactly one of PageID or ToRoute must be given.
+type RedirectRequest struct {
+	// FromRoute is the route pattern redirected
+	FromRoute string `json:"from_route"`
+	// PageID is the page to redirect to
+	PageID *string `json:"page_id"`
+	// ToRoute is the route pattern to redirect to
+	ToRoute *string `json:"to_route"`
+}
+
+// ToRedirect converts the request into a Redirect for the service layer.
+func (r RedirectRequest) ToRedirect() Redirect {
+	return Redirect{FromRoute: r.FromRoute, PageID: r.PageID, ToRoute: r.ToRoute}
+}
+
+// LintSettingsRequest is the request body for replacing the lint settings.
+type LintSettingsRequest struct {
+	// Severities overrides rule severities by rule name: "error", "warning" or "off"
+	Severities map[string]string `json:"severities"`
+	// PublishGate keeps drafts with lint errors from being published
+	PublishGate bool `json:"publish_gate"`
+}
+
+// ToLintSettings converts the request to lint settings.
+func (r LintSettingsRequest) ToLintSettings() LintSettings {
+	return LintSettings{Severities: r.Severities, PublishGate: r.PublishGate}
+}
@@ -0,0 +1,23 @@
+package models
+
+// TargetingRuleRequest is the request body for checking a targeting rule.
+type TargetingRuleRequest struct {
+	// Rule is the targeting rule to check
+	Rule string `json:"rule"`
+}
+
+// TargetingValidation is the result of checking a targeting rule.
+type TargetingValidation struct {
+	// Valid reports whether the rule can be saved on a widget
+	Valid bool `json:"valid"`
+	// Error describes the first problem found (nil when valid)
+	Error *TargetingError `json:"error"`
+}
+
+// TargetingError describes a problem in a targeting rule.
+type TargetingError struct {
+	// Message explains the problem
+	Message string `json:"message"`
+	// Position is the 1-based character offset in the rule where the problem was found
+	Position int `json:"position"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Template is a saved page layout that new pages can be created from.
+// It holds the page's top-level widgets as WidgetNode definitions with nested children;
+// creating a page from a template copies them, so later template changes do not affect the page.
+type Template struct {
+	// ID is a UUID that uniquely identifies the template
+	ID string `json:"id"`
+	// Name is the unique human-readable name shown in the templates library
+	Name string `json:"name"`
+	// Category groups templates in the library (e.g., "home", "marketing")
+	Category string `json:"category"`
+	// Description explains what the template is for
+	Description string `json:"description"`
+	// PreviewImageURL is a screenshot or illustration of the template
+	PreviewImageURL string `json:"preview_image_url"`
+	// Widgets are the template's top-level widget definitions in page order
+	Widgets []WidgetNode `json:"widgets"`
+	// IsBuiltin marks starter templates shipped with the API; they cannot be deleted
+	IsBuiltin bool `json:"is_builtin"`
+	// CreatedAt is the timestamp when the template was created
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the timestamp when the template was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TemplateFilter narrows a templates listing. Empty fields match everything.
+type TemplateFilter struct {
+	// Category matches templates in this category (case-insensitive)
+	Category string
+	// Query matches templates whose name or description contains it (case-insensitive)
+	Query string
+}
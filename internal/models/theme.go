@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Theme holds the app's design tokens. Widget configs reference tokens by path,
+// e.g. "{{color.primary}}", "{{spacing.md}}" or "{{typography.heading.font_size}}",
+// and page reads substitute the current values, so rebranding means editing only the theme.
+type Theme struct {
+	// Colors maps color token names to hex values (e.g., "primary": "#1A73E8")
+	Colors map[string]string `json:"colors"`
+	// Typography maps text style names to font settings
+	Typography map[string]TypographyToken `json:"typography"`
+	// Spacing maps spacing token names to sizes in points
+	Spacing map[string]float64 `json:"spacing"`
+	// Radius maps corner radius token names to sizes in points
+	Radius map[string]float64 `json:"radius"`
+	// DarkColors overrides color tokens in dark mode; every key must exist in Colors
+	DarkColors map[string]string `json:"dark_colors"`
+	// UpdatedAt is the timestamp when the theme was last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TypographyToken is a named text style. Its fields are referenced individually,
+// e.g. "{{typography.body.font_size}}".
+type TypographyToken struct {
+	// FontFamily is the font name
+	FontFamily string `json:"font_family"`
+	// FontSize is the size in points
+	FontSize float64 `json:"font_size"`
+	// FontWeight is the CSS-style weight (100 to 900)
+	FontWeight int `json:"font_weight"`
+	// LineHeight is the line height as a multiple of the font size
+	LineHeight float64 `json:"line_height"`
+}
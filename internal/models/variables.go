@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// StoreVariables are app-level values, such as a free shipping threshold, that widget
+// configs reference as "{{store.<name>}}". Page reads substitute the current values,
+// so a change appears on every page without editing widgets.
+type StoreVariables struct {
+	// Variables maps variable names to string, number or boolean values
+	Variables map[string]interface{} `json:"variables"`
+	// UpdatedAt is the timestamp when the variables were last modified
+	UpdatedAt time.Time `json:"updated_at"`
+}
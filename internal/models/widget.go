@@ -5,20 +5,40 @@ import "time"
 // Widget represents a UI component or content block placed on a page.
 // Each widget has a specific type and flexible JSON configuration
 // that defines its appearance and behavior.
+// Container widgets (row, column, tabs, carousel) hold other widgets as children,
+// forming a tree per page.
 type Widget struct {
 	// ID is a UUID that uniquely identifies the widget
 	ID string `json:"id"`
 	// PageID is the UUID of the page this widget belongs to
 	PageID string `json:"page_id"`
-	// Type specifies the widget category (banner, product_grid, text, image, spacer)
+	// ParentID is the UUID of the container widget holding this widget, or nil for top-level widgets
+	ParentID *string `json:"parent_id"`
+	// Type specifies the widget category (banner, product_grid, text, image, spacer,
+	// a container type: row, column, tabs, carousel, or "component" for a component reference)
 	Type string `json:"type"`
-	// Position is the order index of this widget on its page (0-based)
+	// ComponentID is the referenced component when Type is "component"
+	ComponentID *string `json:"component_id,omitempty"`
+	// Position is the order index of this widget among its siblings (0-based)
 	Position int `json:"position"`
 	// Config holds widget-specific configuration as JSON
-	// Structure varies by widget type, e.g., banner has image_url, text has content
+	// Structure varies by widget type, e.g., banner has image_url (or asset_id for an
+	// uploaded asset), text has content
 	Config map[string]interface{} `json:"config"`
+	// VisibleFrom is when the widget starts appearing on public reads (nil for always)
+	VisibleFrom *time.Time `json:"visible_from"`
+	// VisibleUntil is when the widget stops appearing on public reads (nil for never)
+	VisibleUntil *time.Time `json:"visible_until"`
+	// Targeting is an audience rule limiting who sees the widget on public reads
+	// (nil for everyone), e.g. `platform == "ios" and app_version >= "2.3"`
+	Targeting *string `json:"targeting"`
 	// CreatedAt is the timestamp when the widget was created
 	CreatedAt time.Time `json:"created_at"`
 	// UpdatedAt is the timestamp when the widget was last modified
 	UpdatedAt time.Time `json:"updated_at"`
+	// Children holds nested widgets of a container, ordered by position.
+	// Only populated when a page's widget tree is returned.
+	Children []Widget `json:"children,omitempty"`
+	// Component is the referenced component's current definition, expanded in page responses
+	Component *Component `json:"component,omitempty"`
 }
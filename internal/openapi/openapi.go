@@ -0,0 +1,11 @@
+// Package openapi embeds the OpenAPI 3.1 specification describing the AppDrop API.
+// The document is maintained by hand in openapi.json; every route registered in
+// internal/router must have a matching operation (enforced by the router tests).
+package openapi
+
+import _ "embed"
+
+// Spec is the raw OpenAPI document served at /openapi.json.
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,260 @@
+// Package pageroute parses page route patterns and matches app paths against them.
+//
+// A route is "/" or a sequence of "/"-separated segments, each either a literal
+// ("products") or a named parameter (":productId"):
+//
+//	/products/:productId/reviews
+//
+// Literals are letters, digits, ".", "_", "~" and "-" (but not "." or ".."); parameter
+// names are letters, digits and underscores, starting with a letter, and are unique
+// within a route. Routes have no empty segments, so no trailing slash.
+//
+// A parameter matches any one non-empty path segment. When several routes match a
+// path, the more specific one wins: "/products/featured" over "/products/:productId".
+// Two routes overlap ambiguously when some path matches both and neither is more
+// specific, like "/:category/new" and "/shop/:productId"; Conflicts reports these.
+package pageroute
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MaxRouteLength is the longest route, in bytes, that Parse accepts.
+const MaxRouteLength = 200
+
+var (
+	// literalPattern matches a literal route segment
+	literalPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+	// paramPattern matches a parameter name (without the leading ":")
+	paramPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// segment is one part of a route: a literal, or a parameter if param is set.
+type segment struct {
+	literal string
+	param   string
+}
+
+// Pattern is a parsed page route.
+type Pattern struct {
+	route    string
+	segments []segment
+}
+
+// Parse checks a route's syntax and returns its pattern.
+func Parse(route string) (*Pattern, error) {
+	if len(route) > MaxRouteLength {
+		return nil, fmt.Errorf("route cannot be longer than %d characters", MaxRouteLength)
+	}
+	if !strings.HasPrefix(route, "/") {
+		return nil, errors.New("route must start with /")
+	}
+	p := &Pattern{route: route}
+	if route == "/" {
+		return p, nil
+	}
+
+	names := map[string]bool{}
+	for i, part := range strings.Split(route[1:], "/") {
+		switch {
+		case part == "":
+			return nil, errors.New("route cannot have empty segments or a trailing /")
+		case strings.HasPrefix(part, ":"):
+			name := part[1:]
+			if !paramPattern.MatchString(name) {
+				return nil, fmt.Errorf("route segment %d: parameter names are letters, digits and _, starting with a letter", i+1)
+			}
+			if names[name] {
+				return nil, fmt.Errorf("route segment %d: parameter %s is used twice", i+1, name)
+			}
+			names[name] = true
+			p.segments = append(p.segments, segment{param: name})
+		case part == "." || part == ".." || !literalPattern.MatchString(part):
+			return nil, fmt.Errorf("route segment %d: %q must be letters, digits, ., _, ~ or - (or :name for a parameter)", i+1, part)
+		default:
+			p.segments = append(p.segments, segment{literal: part})
+		}
+	}
+	return p, nil
+}
+
+// String returns the route the pattern was parsed from.
+func (p *Pattern) String() string {
+	return p.route
+}
+
+// Shape returns the route with parameter names left out ("/products/:"). Routes with
+// the same shape match the same paths.
+func (p *Pattern) Shape() string {
+	if len(p.segments) == 0 {
+		return "/"
+	}
+	var b strings.Builder
+	for _, s := range p.segments {
+		b.WriteString("/")
+		if s.param != "" {
+			b.WriteString(":")
+		} else {
+			b.WriteString(s.literal)
+		}
+	}
+	return b.String()
+}
+
+// Specificity is the number of literal segments; of two routes matching a path, the
+// one with more literals is the more specific.
+func (p *Pattern) Specificity() int {
+	n := 0
+	for _, s := range p.segments {
+		if s.param == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Params returns the route's parameter names in order.
+func (p *Pattern) Params() []string {
+	var names []string
+	for _, s := range p.segments {
+		if s.param != "" {
+			names = append(names, s.param)
+		}
+	}
+	return names
+}
+
+// Wildcard returns the route with each parameter replaced by wild, such as
+// "/products/*" for "/products/:productId" and wild "*".
+func (p *Pattern) Wildcard(wild string) string {
+	if len(p.segments) == 0 {
+		return "/"
+	}
+	var b strings.Builder
+	for _, s := range p.segments {
+		b.WriteString("/")
+		if s.param != "" {
+			b.WriteString(wild)
+		} else {
+			b.WriteString(s.literal)
+		}
+	}
+	return b.String()
+}
+
+// Build returns the path for the route with its parameters filled in from values, by
+// name or, for a name values lacks, from the value at the same position in ordered.
+// Values are escaped. Returns false if a parameter has no value.
+func (p *Pattern) Build(values map[string]string, ordered []string) (string, bool) {
+	if len(p.segments) == 0 {
+		return "/", true
+	}
+	var b strings.Builder
+	index := 0
+	for _, s := range p.segments {
+		b.WriteString("/")
+		if s.param == "" {
+			b.WriteString(s.literal)
+			continue
+		}
+		value, ok := values[s.param]
+		if !ok {
+			if index >= len(ordered) {
+				return "", false
+			}
+			value = ordered[index]
+		}
+		index++
+		b.WriteString(url.PathEscape(value))
+	}
+	return b.String(), true
+}
+
+// Match reports whether path matches the route and returns its parameter values.
+// path is the escaped path of an app URL, such as "/products/123"; a query string or
+// fragment is ignored, as is a trailing slash. Segments are unescaped before matching.
+func (p *Pattern) Match(path string) (map[string]string, bool) {
+	parts, ok := SplitPath(path)
+	if !ok || len(parts) != len(p.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, s := range p.segments {
+		if s.param != "" {
+			params[s.param] = parts[i]
+		} else if parts[i] != s.literal {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// SplitPath splits an app path into unescaped segments ("/" has none). Returns false if
+// the path does not start with "/", has an empty segment or cannot be unescaped.
+func SplitPath(path string) ([]string, bool) {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	path = strings.TrimSuffix(path[1:], "/")
+	if path == "" {
+		return []string{}, true
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil || unescaped == "" {
+			return nil, false
+		}
+		parts[i] = unescaped
+	}
+	return parts, true
+}
+
+// Overlaps reports whether some path matches both routes.
+func Overlaps(a, b *Pattern) bool {
+	if len(a.segments) != len(b.segments) {
+		return false
+	}
+	for i := range a.segments {
+		x, y := a.segments[i], b.segments[i]
+		if x.param == "" && y.param == "" && x.literal != y.literal {
+			return false
+		}
+	}
+	return true
+}
+
+// Conflicts reports whether two routes overlap without one being more specific:
+// either they match exactly the same paths ("/products/:id" and "/products/:slug"),
+// or some path matches both and each has a literal where the other has a parameter.
+func Conflicts(a, b *Pattern) bool {
+	if !Overlaps(a, b) {
+		return false
+	}
+	// They conflict unless exactly one is within the other
+	return a.within(b) == b.within(a)
+}
+
+// Within reports whether every path matching a also matches b, e.g. "/p/sale" or
+// "/p/:id" within "/p/:slug".
+func Within(a, b *Pattern) bool {
+	return Overlaps(a, b) && a.within(b)
+}
+
+// within reports whether every path matching p also matches other. Both patterns
+// have the same number of segments and overlap.
+func (p *Pattern) within(other *Pattern) bool {
+	for i, s := range other.segments {
+		if s.param == "" && p.segments[i].param != "" {
+			return false
+		}
+	}
+	return true
+}
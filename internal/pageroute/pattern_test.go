@@ -0,0 +1,168 @@
+package pageroute
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// mustParse parses a route the test expects to be valid.
+func mustParse(t *testing.T, route string) *Pattern {
+	t.Helper()
+	p, err := Parse(route)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", route, err)
+	}
+	return p
+}
+
+func TestParseRejectsInvalidRoutes(t *testing.T) {
+	routes := []string{
+		"",
+		"products",
+		"/products/",
+		"//products",
+		"/products/:",
+		"/products/:1id",
+		"/products/:id/reviews/:id",
+		"/products/..",
+		"/products/a b",
+	}
+	for _, route := range routes {
+		if _, err := Parse(route); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", route)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		route  string
+		path   string
+		params map[string]string
+		ok     bool
+	}{
+		{"/", "/", map[string]string{}, true},
+		{"/", "/home", nil, false},
+		{"/products", "/products", map[string]string{}, true},
+		{"/products", "/products/", map[string]string{}, true},
+		{"/products", "/products?sort=price#top", map[string]string{}, true},
+		{"/products", "/product", nil, false},
+		{"/products/:productId", "/products/123", map[string]string{"productId": "123"}, true},
+		{"/products/:productId", "/products/red%20shirt", map[string]string{"productId": "red shirt"}, true},
+		{"/products/:productId", "/products", nil, false},
+		{"/products/:productId", "/products/123/reviews", nil, false},
+		{"/:category/:productId", "/shoes/42", map[string]string{"category": "shoes", "productId": "42"}, true},
+		{"/products/:productId", "products/123", nil, false},
+		{"/products/:productId", "/products//123", nil, false},
+		{"/products/:productId", "/products/%zz", nil, false},
+	}
+	for _, tt := range tests {
+		params, ok := mustParse(t, tt.route).Match(tt.path)
+		if ok != tt.ok || !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("%s Match(%q) = %v, %v; want %v, %v", tt.route, tt.path, params, ok, tt.params, tt.ok)
+		}
+	}
+}
+
+func TestOverlapsAndConflicts(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		overlaps  bool
+		conflicts bool
+	}{
+		// Same paths
+		{"/products", "/products", true, true},
+		{"/products/:id", "/products/:slug", true, true},
+		{"/:a/:b", "/:x/:y", true, true},
+		// One more specific than the other
+		{"/products/featured", "/products/:productId", true, false},
+		{"/shop/:id/reviews", "/:section/:id/:tab", true, false},
+		// Ambiguous: each has a literal where the other has a parameter
+		{"/:category/new", "/shop/:productId", true, true},
+		{"/a/:x/c", "/:y/b/c", true, true},
+		// Disjoint
+		{"/products", "/collections", false, false},
+		{"/products/:id", "/products", false, false},
+		{"/products/:id", "/collections/:id", false, false},
+		{"/:category/new", "/shop/:productId/reviews", false, false},
+		{"/", "/:page", false, false},
+	}
+	for _, tt := range tests {
+		a, b := mustParse(t, tt.a), mustParse(t, tt.b)
+		for _, pair := range [][2]*Pattern{{a, b}, {b, a}} {
+			if got := Overlaps(pair[0], pair[1]); got != tt.overlaps {
+				t.Errorf("Overlaps(%s, %s) = %v, want %v", pair[0], pair[1], got, tt.overlaps)
+			}
+			if got := Conflicts(pair[0], pair[1]); got != tt.conflicts {
+				t.Errorf("Conflicts(%s, %s) = %v, want %v", pair[0], pair[1], got, tt.conflicts)
+			}
+		}
+	}
+}
+
+func TestWithin(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		within bool
+	}{
+		{"/p/sale", "/p/:slug", true},
+		{"/p/:id", "/p/:slug", true},
+		{"/p/sale", "/p/sale", true},
+		{"/p/:slug", "/p/sale", false},
+		{"/:category/new", "/shop/:productId", false},
+		{"/p/sale", "/q/:slug", false},
+		{"/p/sale", "/p/:slug/reviews", false},
+	}
+	for _, tt := range tests {
+		if got := Within(mustParse(t, tt.a), mustParse(t, tt.b)); got != tt.within {
+			t.Errorf("Within(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.within)
+		}
+	}
+}
+
+func TestSpecificityOrdering(t *testing.T) {
+	// Routes matching "/shop/featured/reviews", most specific first
+	routes := []string{
+		"/shop/featured/reviews",
+		"/shop/:productId/reviews",
+		"/:section/:productId/reviews",
+		"/:section/:productId/:tab",
+	}
+	patterns := make([]*Pattern, len(routes))
+	for i, route := range routes {
+		patterns[i] = mustParse(t, route)
+		if _, ok := patterns[i].Match("/shop/featured/reviews"); !ok {
+			t.Fatalf("%s does not match /shop/featured/reviews", route)
+		}
+	}
+	sorted := append([]*Pattern(nil), patterns...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Specificity() > sorted[j].Specificity() })
+	for i := range sorted {
+		if sorted[i] != patterns[i] {
+			t.Errorf("position %d: %s (specificity %d), want %s (specificity %d)",
+				i, sorted[i], sorted[i].Specificity(), patterns[i], patterns[i].Specificity())
+		}
+	}
+	if got := mustParse(t, "/").Specificity(); got != 0 {
+		t.Errorf("Specificity of / = %d, want 0", got)
+	}
+}
+
+func TestShapeAndBuild(t *testing.T) {
+	p := mustParse(t, "/products/:productId/reviews/:reviewId")
+	if got, want := p.Shape(), "/products/:/reviews/:"; got != want {
+		t.Errorf("Shape() = %q, want %q", got, want)
+	}
+	if got, want := p.Params(), []string{"productId", "reviewId"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Params() = %v, want %v", got, want)
+	}
+
+	path, ok := p.Build(map[string]string{"reviewId": "7"}, []string{"red shirt", "ignored"})
+	if want := "/products/red%20shirt/reviews/7"; !ok || path != want {
+		t.Errorf("Build() = %q, %v; want %q, true", path, ok, want)
+	}
+	if _, ok := p.Build(map[string]string{}, []string{"1"}); ok {
+		t.Error("Build() with a missing parameter succeeded")
+	}
+}
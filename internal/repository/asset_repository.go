@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// assetColumns is the column list selected for every asset query, in scanAsset order.
+const assetColumns = `id,filename,mime_type,size_bytes,width,height,checksum,storage_key,variants_status,created_at`
+
+// scanAsset reads one asset row selected with assetColumns.
+func scanAsset(row pgx.Row) (*models.Asset, error) {
+	var a models.Asset
+	err := row.Scan(&a.ID, &a.Filename, &a.MimeType, &a.SizeBytes, &a.Width, &a.Height,
+		&a.Checksum, &a.StorageKey, &a.VariantsStatus, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CreateAsset inserts an asset record and returns it with its generated ID.
+func CreateAsset(ctx context.Context, asset models.Asset) (*models.Asset, error) {
+	return scanAsset(db.Pool.QueryRow(ctx,
+		`INSERT INTO assets (filename, mime_type, size_bytes, width, height, checksum, storage_key)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING `+assetColumns,
+		asset.Filename, asset.MimeType, asset.SizeBytes, asset.Width, asset.Height,
+		asset.Checksum, asset.StorageKey))
+}
+
+func queryAssets(ctx context.Context, sql string, args ...any) ([]models.Asset, error) {
+	rows, err := db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assets []models.Asset
+	for rows.Next() {
+		a, err := scanAsset(rows)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, *a)
+	}
+	return assets, rows.Err()
+}
+
+// GetAssets retrieves every asset, newest first.
+func GetAssets(ctx context.Context) ([]models.Asset, error) {
+	return queryAssets(ctx,
+		`SELECT `+assetColumns+` FROM assets ORDER BY created_at DESC`)
+}
+
+// GetAssetsByIDs retrieves the assets with the given IDs; IDs that match no asset
+// (including malformed ones) are skipped.
+func GetAssetsByIDs(ctx context.Context, ids []string) ([]models.Asset, error) {
+	return queryAssets(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE id::text = ANY($1)`, ids)
+}
+
+// GetAssetByID retrieves a single asset by its UUID.
+// Returns an error if the asset is not found.
+func GetAssetByID(ctx context.Context, id string) (*models.Asset, error) {
+	return scanAsset(db.Pool.QueryRow(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE id=$1`, id))
+}
+
+// AssetInUse reports whether a widget, component definition, experiment override or
+// product image references the asset by asset_id.
+func AssetInUse(ctx context.Context, id string) (bool, error) {
+	var inUse bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM widgets WHERE config->>'asset_id' = $1)
+		     OR EXISTS (SELECT 1 FROM components
+		                WHERE jsonb_path_exists(root, '$.** ? (@.asset_id == $id)', jsonb_build_object('id', $1::text)))
+		     OR EXISTS (SELECT 1 FROM experiments
+		                WHERE jsonb_path_exists(variants, '$[*].overrides[*].config ? (@.asset_id == $id)', jsonb_build_object('id', $1::text)))
+		     OR EXISTS (SELECT 1 FROM products
+		                WHERE jsonb_path_exists(images, '$[*] ? (@.asset_id == $id)', jsonb_build_object('id', $1::text)))`,
+		id).Scan(&inUse)
+	return inUse, err
+}
+
+// DeleteAsset removes an asset record. The stored file is removed by the service layer.
+func DeleteAsset(ctx context.Context, id string) error {
+	_, err := db.Pool.Exec(ctx, `DELETE FROM assets WHERE id=$1`, id)
+	return err
+}
+
+// assetVariantColumns is the column list selected for every variant query, in scanAssetVariant order.
+const assetVariantColumns = `asset_id,width,height,mime_type,size_bytes,storage_key`
+
+// scanAssetVariant reads one variant row selected with assetVariantColumns,
+// returning the asset ID alongside it.
+func scanAssetVariant(row pgx.Row) (string, *models.AssetVariant, error) {
+	var assetID string
+	var v models.AssetVariant
+	err := row.Scan(&assetID, &v.Width, &v.Height, &v.MimeType, &v.SizeBytes, &v.StorageKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return assetID, &v, nil
+}
+
+// GetAssetVariants retrieves the variants of the given assets, keyed by asset ID,
+// narrowest first.
+func GetAssetVariants(ctx context.Context, assetIDs []string) (map[string][]models.AssetVariant, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+assetVariantColumns+` FROM asset_variants
+		 WHERE asset_id::text = ANY($1) ORDER BY asset_id, width`, assetIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := map[string][]models.AssetVariant{}
+	for rows.Next() {
+		assetID, v, err := scanAssetVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants[assetID] = append(variants[assetID], *v)
+	}
+	return variants, rows.Err()
+}
+
+// GetAssetVariant retrieves an asset's variant of the given width.
+// Returns an error if there is no such variant.
+func GetAssetVariant(ctx context.Context, assetID string, width int) (*models.AssetVariant, error) {
+	_, v, err := scanAssetVariant(db.Pool.QueryRow(ctx,
+		`SELECT `+assetVariantColumns+` FROM asset_variants WHERE asset_id=$1 AND width=$2`, assetID, width))
+	return v, err
+}
+
+// ClaimPendingAsset marks the oldest asset waiting for variants as processing and returns it,
+// or nil if none is waiting. Assets left processing for longer than staleAfter (by a
+// worker that stopped mid-way) are claimed again. Concurrent callers never claim the
+// same asset.
+func ClaimPendingAsset(ctx context.Context, staleAfter time.Duration) (*models.Asset, error) {
+	a, err := scanAsset(db.Pool.QueryRow(ctx,
+		`UPDATE assets SET variants_status='processing', variants_updated_at=NOW()
+		 WHERE id = (
+		     SELECT id FROM assets
+		     WHERE variants_status='pending'
+		        OR (variants_status='processing' AND variants_updated_at < NOW() - make_interval(secs => $1))
+		     ORDER BY created_at
+		     LIMIT 1
+		     FOR UPDATE SKIP LOCKED)
+		 RETURNING `+assetColumns, staleAfter.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return a, err
+}
+
+// SaveAssetVariants replaces an asset's variants and marks it ready, in one transaction.
+// An asset queued again while it was processing stays pending.
+// Returns the storage keys of the replaced variants so their files can be removed.
+func SaveAssetVariants(ctx context.Context, assetID string, variants []models.AssetVariant) ([]string, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `DELETE FROM asset_variants WHERE asset_id=$1 RETURNING storage_key`, assetID)
+	if err != nil {
+		return nil, err
+	}
+	replaced, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range variants {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO asset_variants (asset_id, width, height, mime_type, size_bytes, storage_key)
+			 VALUES ($1,$2,$3,$4,$5,$6)`,
+			assetID, v.Width, v.Height, v.MimeType, v.SizeBytes, v.StorageKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE assets SET variants_status='ready', variants_updated_at=NOW()
+		 WHERE id=$1 AND variants_status='processing'`, assetID); err != nil {
+		return nil, err
+	}
+	return replaced, tx.Commit(ctx)
+}
+
+// SetAssetVariantsStatus sets an asset's variants status.
+// Returns false if the asset does not exist.
+func SetAssetVariantsStatus(ctx context.Context, id, status string) (bool, error) {
+	tag, err := db.Pool.Exec(ctx,
+		`UPDATE assets SET variants_status=$2, variants_updated_at=NOW() WHERE id=$1`, id, status)
+	return tag.RowsAffected() > 0, err
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// collectionColumns is the column list selected for every collection query, in scanCollection order.
+// The product IDs are aggregated from collection_products in position order.
+const collectionColumns = `c.id,c.name,c.description,
+	COALESCE((SELECT array_agg(cp.product_id::text ORDER BY cp.position)
+	          FROM collection_products cp WHERE cp.collection_id = c.id), '{}'),
+	c.created_at,c.updated_at`
+
+// scanCollection reads one collection row selected with collectionColumns.
+func scanCollection(row pgx.Row) (*models.Collection, error) {
+	var c models.Collection
+	err := row.Scan(&c.ID, &c.Name, &c.Description, &c.ProductIDs, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func queryCollections(ctx context.Context, sql string, args ...any) ([]models.Collection, error) {
+	rows, err := db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []models.Collection
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, *c)
+	}
+	return collections, rows.Err()
+}
+
+// GetCollections retrieves every collection ordered by name.
+func GetCollections(ctx context.Context) ([]models.Collection, error) {
+	return queryCollections(ctx,
+		`SELECT `+collectionColumns+` FROM collections c ORDER BY c.name`)
+}
+
+// GetCollectionByID retrieves a single collection by its UUID.
+// Returns an error if the collection is not found.
+func GetCollectionByID(ctx context.Context, id string) (*models.Collection, error) {
+	return scanCollection(db.Pool.QueryRow(ctx,
+		`SELECT `+collectionColumns+` FROM collections c WHERE c.id=$1`, id))
+}
+
+// GetCollectionsByIDs retrieves the collections with the given IDs, keyed by ID; IDs that
+// match no collection (including malformed ones) are skipped.
+func GetCollectionsByIDs(ctx context.Context, ids []string) (map[string]*models.Collection, error) {
+	collections, err := queryCollections(ctx,
+		`SELECT `+collectionColumns+` FROM collections c WHERE c.id::text = ANY($1)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Collection, len(collections))
+	for i := range collections {
+		byID[collections[i].ID] = &collections[i]
+	}
+	return byID, nil
+}
+
+// CreateCollection inserts a collection with its products in one transaction
+// and returns it with its generated ID.
+func CreateCollection(ctx context.Context, collection models.Collection) (*models.Collection, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO collections (name, description) VALUES ($1,$2) RETURNING id`,
+		collection.Name, collection.Description).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if err := setCollectionProducts(ctx, tx, id, collection.ProductIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return GetCollectionByID(ctx, id)
+}
+
+// UpdateCollection replaces a collection's fields and products in one transaction
+// and bumps its updated_at timestamp.
+func UpdateCollection(ctx context.Context, collection models.Collection) (*models.Collection, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`UPDATE collections SET name=$2, description=$3, updated_at=NOW() WHERE id=$1`,
+		collection.ID, collection.Name, collection.Description)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM collection_products WHERE collection_id=$1`, collection.ID); err != nil {
+		return nil, err
+	}
+	if err := setCollectionProducts(ctx, tx, collection.ID, collection.ProductIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return GetCollectionByID(ctx, collection.ID)
+}
+
+// setCollectionProducts inserts a collection's products in order.
+func setCollectionProducts(ctx context.Context, tx pgx.Tx, collectionID string, productIDs []string) error {
+	for i, productID := range productIDs {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO collection_products (collection_id, product_id, position) VALUES ($1,$2,$3)`,
+			collectionID, productID, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteCollection removes a collection; its products are kept.
+func DeleteCollection(ctx context.Context, id string) error {
+	_, err := db.Pool.Exec(ctx, `DELETE FROM collections WHERE id=$1`, id)
+	return err
+}
+
+// CollectionNameExists checks if a collection other than excludeID already uses name.
+// Pass an empty excludeID when creating.
+func CollectionNameExists(ctx context.Context, name, excludeID string) (bool, error) {
+	var exists bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM collections WHERE name=$1 AND id::text != $2)`,
+		name, excludeID).Scan(&exists)
+	return exists, err
+}
+
+// CollectionInUse reports whether a widget, component definition or experiment override
+// lists products from the collection through a product_grid's collection_id.
+func CollectionInUse(ctx context.Context, id string) (bool, error) {
+	var inUse bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM widgets WHERE config->>'collection_id' = $1)
+		     OR EXISTS (SELECT 1 FROM components
+		                WHERE jsonb_path_exists(root, '$.** ? (@.collection_id == $id)', jsonb_build_object('id', $1::text)))
+		     OR EXISTS (SELECT 1 FROM experiments
+		                WHERE jsonb_path_exists(variants, '$[*].overrides[*].config ? (@.collection_id == $id)', jsonb_build_object('id', $1::text)))`,
+		id).Scan(&inUse)
+	return inUse, err
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// componentColumns is the column list selected for every component query, in scanComponent order.
+const componentColumns = `id,name,description,root,created_at,updated_at`
+
+// scanComponent reads one component row selected with componentColumns.
+// Unmarshals the JSONB root definition into a WidgetNode tree.
+func scanComponent(row pgx.Row) (*models.Component, error) {
+	var c models.Component
+	var rootJSON []byte
+
+	err := row.Scan(&c.ID, &c.Name, &c.Description, &rootJSON, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rootJSON, &c.Root); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetAllComponents retrieves every component ordered by name.
+func GetAllComponents(ctx context.Context) ([]models.Component, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+componentColumns+` FROM components ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var components []models.Component
+	for rows.Next() {
+		c, err := scanComponent(rows)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, *c)
+	}
+	return components, rows.Err()
+}
+
+// GetComponentByID retrieves a single component by its UUID.
+// Returns an error if the component is not found.
+func GetComponentByID(ctx context.Context, id string) (*models.Component, error) {
+	return scanComponent(db.Pool.QueryRow(ctx,
+		`SELECT `+componentColumns+` FROM components WHERE id=$1`, id))
+}
+
+// GetComponentsByIDs retrieves the components with the given IDs, keyed by ID.
+// Used to expand component references when serving a page in one query.
+func GetComponentsByIDs(ctx context.Context, ids []string) (map[string]*models.Component, error) {
+	components := make(map[string]*models.Component, len(ids))
+	if len(ids) == 0 {
+		return components, nil
+	}
+
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+componentColumns+` FROM components WHERE id = ANY($1::uuid[])`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanComponent(rows)
+		if err != nil {
+			return nil, err
+		}
+		components[c.ID] = c
+	}
+	return components, rows.Err()
+}
+
+// CreateComponent inserts a new component and returns it with its generated ID and timestamps.
+func CreateComponent(ctx context.Context, component models.Component) (*models.Component, error) {
+	rootData, err := json.Marshal(component.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanComponent(db.Pool.QueryRow(ctx,
+		`INSERT INTO components (name, description, root) VALUES ($1,$2,$3)
+		 RETURNING `+componentColumns,
+		component.Name, component.Description, string(rootData)))
+}
+
+// UpdateComponent replaces a component's name, description and definition.
+func UpdateComponent(ctx context.Context, component models.Component) (*models.Component, error) {
+	rootData, err := json.Marshal(component.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanComponent(db.Pool.QueryRow(ctx,
+		`UPDATE components SET name=$1, description=$2, root=$3, updated_at=NOW()
+		 WHERE id=$4 RETURNING `+componentColumns,
+		component.Name, component.Description, string(rootData), component.ID))
+}
+
+// DeleteComponent removes a component.
+// Fails with a foreign key violation if any widget still references it.
+func DeleteComponent(ctx context.Context, id string) error {
+	_, err := db.Pool.Exec(ctx, `DELETE FROM components WHERE id=$1`, id)
+	return err
+}
+
+// ComponentNameExists checks if a component other than excludeID already uses name.
+// Pass an empty excludeID when creating.
+func ComponentNameExists(ctx context.Context, name, excludeID string) (bool, error) {
+	var exists bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM components WHERE name=$1 AND id::text != $2)`,
+		name, excludeID).Scan(&exists)
+	return exists, err
+}
+
+// GetComponentUsages lists every widget referencing a component, with its page.
+// Ordered by page name so the list is stable for display.
+func GetComponentUsages(ctx context.Context, componentID string) ([]models.ComponentUsage, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT w.id, p.id, p.name, p.route
+		 FROM widgets w JOIN pages p ON p.id = w.page_id
+		 WHERE w.component_id=$1
+		 ORDER BY p.name, w.position`, componentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usages []models.ComponentUsage
+	for rows.Next() {
+		var u models.ComponentUsage
+		if err := rows.Scan(&u.WidgetID, &u.PageID, &u.PageName, &u.PageRoute); err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+	return usages, rows.Err()
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// experimentColumns is the column list selected for every experiment query, in scanExperiment order.
+const experimentColumns = `id,name,page_id,status,variants,created_at,updated_at`
+
+// scanExperiment reads one experiment row selected with experimentColumns.
+// Unmarshals the JSONB variants.
+func scanExperiment(row pgx.Row) (*models.Experiment, error) {
+	var e models.Experiment
+	var variantsJSON []byte
+
+	err := row.Scan(&e.ID, &e.Name, &e.PageID, &e.Status, &variantsJSON, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(variantsJSON, &e.Variants); err != nil {
+		return nil, err
+	}
+	if e.Variants == nil {
+		e.Variants = []models.ExperimentVariant{}
+	}
+	return &e, nil
+}
+
+func queryExperiments(ctx context.Context, sql string, args ...any) ([]models.Experiment, error) {
+	rows, err := db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var experiments []models.Experiment
+	for rows.Next() {
+		e, err := scanExperiment(rows)
+		if err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, *e)
+	}
+	return experiments, rows.Err()
+}
+
+// GetExperiments retrieves every experiment, newest first.
+func GetExperiments(ctx context.Context) ([]models.Experiment, error) {
+	return queryExperiments(ctx,
+		`SELECT `+experimentColumns+` FROM experiments ORDER BY created_at DESC`)
+}
+
+// GetRunningExperiments retrieves every running experiment.
+func GetRunningExperiments(ctx context.Context) ([]models.Experiment, error) {
+	return queryExperiments(ctx,
+		`SELECT `+experimentColumns+` FROM experiments WHERE status='running' ORDER BY created_at`)
+}
+
+// GetExperimentByID retrieves a single experiment by its UUID.
+// Returns an error if the experiment is not found.
+func GetExperimentByID(ctx context.Context, id string) (*models.Experiment, error) {
+	return scanExperiment(db.Pool.QueryRow(ctx,
+		`SELECT `+experimentColumns+` FROM experiments WHERE id=$1`, id))
+}
+
+// GetRunningExperimentForPage retrieves the running experiment on a page, or nil if there is none.
+func GetRunningExperimentForPage(ctx context.Context, pageID string) (*models.Experiment, error) {
+	e, err := scanExperiment(db.Pool.QueryRow(ctx,
+		`SELECT `+experimentColumns+` FROM experiments WHERE page_id=$1 AND status='running'`, pageID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+// CreateExperiment inserts a new experiment and returns it with its generated ID and timestamps.
+func CreateExperiment(ctx context.Context, experiment models.Experiment) (*models.Experiment, error) {
+	variantsData, err := json.Marshal(experiment.Variants)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanExperiment(db.Pool.QueryRow(ctx,
+		`INSERT INTO experiments (name, page_id, status, variants)
+		 VALUES ($1,$2,$3,$4) RETURNING `+experimentColumns,
+		experiment.Name, experiment.PageID, experiment.Status, string(variantsData)))
+}
+
+// UpdateExperiment replaces an experiment's name, page, status and variants.
+func UpdateExperiment(ctx context.Context, experiment models.Experiment) (*models.Experiment, error) {
+	variantsData, err := json.Marshal(experiment.Variants)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanExperiment(db.Pool.QueryRow(ctx,
+		`UPDATE experiments
+		 SET name=$1, page_id=$2, status=$3, variants=$4, updated_at=NOW()
+		 WHERE id=$5 RETURNING `+experimentColumns,
+		experiment.Name, experiment.PageID, experiment.Status, string(variantsData), experiment.ID))
+}
+
+// DeleteExperiment removes an experiment and its exposure log (due to ON DELETE CASCADE).
+func DeleteExperiment(ctx context.Context, id string) error {
+	_, err := db.Pool.Exec(ctx, `DELETE FROM experiments WHERE id=$1`, id)
+	return err
+}
+
+// ExperimentNameExists checks if another experiment (not excludeID) already uses name.
+// Pass an empty excludeID when creating.
+func ExperimentNameExists(ctx context.Context, name, excludeID string) (bool, error) {
+	var exists bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM experiments WHERE name=$1 AND id::text != $2)`,
+		name, excludeID).Scan(&exists)
+	return exists, err
+}
+
+// RecordExposure logs that a user was shown a variant. Only the first exposure of
+// each user to an experiment is kept.
+func RecordExposure(ctx context.Context, experimentID, userID, variant string) error {
+	_, err := db.Pool.Exec(ctx,
+		`INSERT INTO experiment_exposures (experiment_id, user_id, variant)
+		 VALUES ($1,$2,$3) ON CONFLICT (experiment_id, user_id) DO NOTHING`,
+		experimentID, userID, variant)
+	return err
+}
+
+// GetExposures retrieves an experiment's exposure log in the order users were exposed.
+func GetExposures(ctx context.Context, experimentID string) ([]models.ExperimentExposure, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT user_id, variant, exposed_at FROM experiment_exposures
+		 WHERE experiment_id=$1 ORDER BY exposed_at, user_id`, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exposures []models.ExperimentExposure
+	for rows.Next() {
+		var e models.ExperimentExposure
+		if err := rows.Scan(&e.UserID, &e.Variant, &e.ExposedAt); err != nil {
+			return nil, err
+		}
+		exposures = append(exposures, e)
+	}
+	return exposures, rows.Err()
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"context"
+)
+
+// PingDB verifies the database is reachable by acquiring a connection and pinging it.
+func PingDB(ctx context.Context) error {
+	return db.Pool.Ping(ctx)
+}
+
+// GetSchemaVersion returns the highest migration version recorded in schema_migrations.
+// Returns 0 if no migrations have been recorded.
+func GetSchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := db.Pool.QueryRow(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
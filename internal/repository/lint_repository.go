@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+)
+
+// GetLintSettings retrieves the lint settings (a single row seeded by the migration).
+func GetLintSettings(ctx context.Context) (*models.LintSettings, error) {
+	var s models.LintSettings
+	var severitiesJSON []byte
+
+	err := db.Pool.QueryRow(ctx,
+		`SELECT severities, publish_gate, updated_at FROM lint_settings`).Scan(&severitiesJSON, &s.PublishGate, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(severitiesJSON, &s.Severities); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpdateLintSettings replaces the lint settings and returns the stored settings.
+func UpdateLintSettings(ctx context.Context, settings models.LintSettings) (*models.LintSettings, error) {
+	severitiesData, err := json.Marshal(settings.Severities)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Pool.Exec(ctx,
+		`UPDATE lint_settings SET severities=$1, publish_gate=$2, updated_at=NOW()`,
+		string(severitiesData), settings.PublishGate)
+	if err != nil {
+		return nil, err
+	}
+	return GetLintSettings(ctx)
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"encoding/json"
+)
+
+// GetLocaleSettings retrieves the app's locales, default first, then alphabetically.
+func GetLocaleSettings(ctx context.Context) (*models.LocaleSettings, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT code, is_default FROM locales ORDER BY is_default DESC, code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := &models.LocaleSettings{Locales: []string{}}
+	for rows.Next() {
+		var code string
+		var isDefault bool
+		if err := rows.Scan(&code, &isDefault); err != nil {
+			return nil, err
+		}
+		if isDefault {
+			settings.Default = code
+		}
+		settings.Locales = append(settings.Locales, code)
+	}
+	return settings, rows.Err()
+}
+
+// ReplaceLocaleSettings replaces the app's locales. Locales no longer listed are deleted
+// together with their translations (ON DELETE CASCADE).
+// Runs in a transaction so there is always exactly one default locale.
+func ReplaceLocaleSettings(ctx context.Context, settings models.LocaleSettings) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`DELETE FROM locales WHERE code != ALL($1::text[])`, settings.Locales)
+	if err != nil {
+		return err
+	}
+
+	// Clear the old default first so the single-default index is never violated
+	if _, err := tx.Exec(ctx, `UPDATE locales SET is_default = false WHERE is_default`); err != nil {
+		return err
+	}
+
+	for _, code := range settings.Locales {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO locales (code, is_default) VALUES ($1,$2)
+			 ON CONFLICT (code) DO UPDATE SET is_default = EXCLUDED.is_default`,
+			code, code == settings.Default)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetPageTranslations retrieves every page name and widget translation of a page,
+// ordered by locale.
+func GetPageTranslations(ctx context.Context, pageID string) (*models.PageTranslations, error) {
+	translations := &models.PageTranslations{
+		Page:    []models.PageTranslation{},
+		Widgets: []models.WidgetTranslation{},
+	}
+
+	rows, err := db.Pool.Query(ctx,
+		`SELECT page_id, locale, name, updated_at FROM page_translations
+		 WHERE page_id=$1 ORDER BY locale`, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t models.PageTranslation
+		if err := rows.Scan(&t.PageID, &t.Locale, &t.Name, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations.Page = append(translations.Page, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	translations.Widgets, err = GetWidgetTranslations(ctx, pageID, "")
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// GetWidgetTranslations retrieves the widget translations of a page, ordered by locale.
+// Pass a locale to get only that locale's translations, or "" for all.
+func GetWidgetTranslations(ctx context.Context, pageID, locale string) ([]models.WidgetTranslation, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT t.widget_id, t.locale, t.fields, t.updated_at
+		 FROM widget_translations t JOIN widgets w ON w.id = t.widget_id
+		 WHERE w.page_id=$1 AND ($2 = '' OR t.locale=$2)
+		 ORDER BY t.locale, w.position`, pageID, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []models.WidgetTranslation{}
+	for rows.Next() {
+		var t models.WidgetTranslation
+		var fieldsJSON []byte
+		if err := rows.Scan(&t.WidgetID, &t.Locale, &fieldsJSON, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(fieldsJSON, &t.Fields); err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	return translations, rows.Err()
+}
+
+// GetPageNameTranslations maps page IDs to their names in one locale.
+// Pages without a translation are absent.
+func GetPageNameTranslations(ctx context.Context, locale string) (map[string]string, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT page_id, name FROM page_translations WHERE locale=$1`, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var pageID, name string
+		if err := rows.Scan(&pageID, &name); err != nil {
+			return nil, err
+		}
+		names[pageID] = name
+	}
+	return names, rows.Err()
+}
+
+// UpsertPageTranslation creates or replaces a page name translation.
+func UpsertPageTranslation(ctx context.Context, t models.PageTranslation) (*models.PageTranslation, error) {
+	var saved models.PageTranslation
+	err := db.Pool.QueryRow(ctx,
+		`INSERT INTO page_translations (page_id, locale, name) VALUES ($1,$2,$3)
+		 ON CONFLICT (page_id, locale) DO UPDATE SET name = EXCLUDED.name, updated_at = NOW()
+		 RETURNING page_id, locale, name, updated_at`,
+		t.PageID, t.Locale, t.Name,
+	).Scan(&saved.PageID, &saved.Locale, &saved.Name, &saved.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeletePageTranslation removes a page name translation.
+// Returns false if there was none.
+func DeletePageTranslation(ctx context.Context, pageID, locale string) (bool, error) {
+	tag, err := db.Pool.Exec(ctx,
+		`DELETE FROM page_translations WHERE page_id=$1 AND locale=$2`, pageID, locale)
+	return tag.RowsAffected() > 0, err
+}
+
+// UpsertWidgetTranslation creates or replaces a widget's translated fields for one locale.
+func UpsertWidgetTranslation(ctx context.Context, t models.WidgetTranslation) (*models.WidgetTranslation, error) {
+	fieldsData, err := json.Marshal(t.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved models.WidgetTranslation
+	var fieldsJSON []byte
+	err = db.Pool.QueryRow(ctx,
+		`INSERT INTO widget_translations (widget_id, locale, fields) VALUES ($1,$2,$3)
+		 ON CONFLICT (widget_id, locale) DO UPDATE SET fields = EXCLUDED.fields, updated_at = NOW()
+		 RETURNING widget_id, locale, fields, updated_at`,
+		t.WidgetID, t.Locale, string(fieldsData),
+	).Scan(&saved.WidgetID, &saved.Locale, &fieldsJSON, &saved.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fieldsJSON, &saved.Fields); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteWidgetTranslation removes a widget's translation for one locale.
+// Returns false if there was none.
+func DeleteWidgetTranslation(ctx context.Context, widgetID, locale string) (bool, error) {
+	tag, err := db.Pool.Exec(ctx,
+		`DELETE FROM widget_translations WHERE widget_id=$1 AND locale=$2`, widgetID, locale)
+	return tag.RowsAffected() > 0, err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetNavigation retrieves the tab bar and drawer items in display order.
+// Page items carry their target page's current route; items whose page was
+// deleted (neither page nor URL set) are marked Broken.
+func GetNavigation(ctx context.Context) (*models.Navigation, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT n.menu, n.id, n.label, n.icon, n.page_id, p.route, n.url
+		 FROM navigation_items n LEFT JOIN pages p ON p.id = n.page_id
+		 ORDER BY n.menu, n.position`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nav := &models.Navigation{TabBar: []models.NavigationItem{}, Drawer: []models.NavigationItem{}}
+	for rows.Next() {
+		var menu string
+		var item models.NavigationItem
+		if err := rows.Scan(&menu, &item.ID, &item.Label, &item.Icon, &item.PageID, &item.Route, &item.URL); err != nil {
+			return nil, err
+		}
+		item.Broken = item.PageID == nil && item.URL == nil
+
+		if menu == utils.NavigationTabBar {
+			nav.TabBar = append(nav.TabBar, item)
+		} else {
+			nav.Drawer = append(nav.Drawer, item)
+		}
+	}
+	return nav, rows.Err()
+}
+
+// ReplaceNavigation replaces every navigation item with the given tab bar and drawer,
+// numbering positions in slice order.
+// Runs in a transaction so clients never see a half-written menu.
+func ReplaceNavigation(ctx context.Context, nav models.Navigation) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM navigation_items`); err != nil {
+		return err
+	}
+
+	menus := map[string][]models.NavigationItem{
+		utils.NavigationTabBar: nav.TabBar,
+		utils.NavigationDrawer: nav.Drawer,
+	}
+	for menu, items := range menus {
+		for index, item := range items {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO navigation_items (menu, position, label, icon, page_id, url)
+				 VALUES ($1,$2,$3,$4,$5,$6)`,
+				menu, index, item.Label, item.Icon, item.PageID, item.URL)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// repairTabBar removes tab bar items whose target page no longer exists and
+// renumbers the remaining items. Drawer items are left in place so they are
+// reported as broken until an editor retargets them.
+func repairTabBar(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx,
+		`DELETE FROM navigation_items
+		 WHERE menu=$1 AND page_id IS NULL AND url IS NULL`, utils.NavigationTabBar)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE navigation_items n SET position = ordered.position
+		 FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY position) - 1 AS position
+		       FROM navigation_items WHERE menu=$1) ordered
+		 WHERE n.id = ordered.id`, utils.NavigationTabBar)
+	return err
+}
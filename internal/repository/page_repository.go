@@ -1,19 +1,41 @@
 // Package repository provides data access operations for the AppDrop API.
 // This layer handles all direct database interactions using parameterized queries.
 // Repository functions use raw SQL with pgx for efficient database operations.
+// Every function takes the caller's context so cancelled or timed-out requests
+// abort their queries instead of running to completion.
 package repository
 
 import (
 	"appdrop-api/internal/db"
 	"appdrop-api/internal/models"
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// pageColumns is the column list selected for every page query, in scanPage order.
+const pageColumns = `id,name,route,is_home,status,publish_at,visible_from,visible_until,created_at,updated_at`
+
+// scanPage reads one page row selected with pageColumns.
+func scanPage(row pgx.Row) (*models.Page, error) {
+	var p models.Page
+	err := row.Scan(
+		&p.ID, &p.Name, &p.Route, &p.IsHome,
+		&p.Status, &p.PublishAt, &p.VisibleFrom, &p.VisibleUntil,
+		&p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
 // GetAllPages retrieves all pages from the database.
 // Returns a slice of all pages ordered by creation date, or error on database failure.
-func GetAllPages() ([]models.Page, error) {
-	rows, err := db.Pool.Query(context.Background(),
-		`SELECT id, name, route, is_home, created_at, updated_at FROM pages ORDER BY created_at`)
+func GetAllPages(ctx context.Context) ([]models.Page, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+pageColumns+` FROM pages ORDER BY created_at`)
 	if err != nil {
 		return nil, err
 	}
@@ -22,100 +44,210 @@ func GetAllPages() ([]models.Page, error) {
 	var pages []models.Page
 
 	for rows.Next() {
-		var p models.Page
-		err := rows.Scan(&p.ID, &p.Name, &p.Route, &p.IsHome, &p.CreatedAt, &p.UpdatedAt)
+		p, err := scanPage(rows)
 		if err != nil {
 			return nil, err
 		}
-		pages = append(pages, p)
+		pages = append(pages, *p)
 	}
 
-	return pages, nil
+	return pages, rows.Err()
 }
 
-func CreatePage(page models.Page) (*models.Page, error) {
+func CreatePage(ctx context.Context, page models.Page) (*models.Page, error) {
 	// CreatePage inserts a new page into the database and returns the created page.
 	// Uses RETURNING clause to get auto-generated ID and timestamps in one query.
-	var createdPage models.Page
-	err := db.Pool.QueryRow(context.Background(),
-		`INSERT INTO pages (name, route, is_home) VALUES ($1,$2,$3) RETURNING id, name, route, is_home, created_at, updated_at`,
+	return scanPage(db.Pool.QueryRow(ctx,
+		`INSERT INTO pages (name, route, is_home, status, publish_at, visible_from, visible_until)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING `+pageColumns,
 		page.Name, page.Route, page.IsHome,
-	).Scan(&createdPage.ID, &createdPage.Name, &createdPage.Route, &createdPage.IsHome, &createdPage.CreatedAt, &createdPage.UpdatedAt)
-
-	if err != nil {
-		return nil, err
-	}
-	return &createdPage, nil
-}
-
-func RouteExists(route string) (bool, error) {
-	// RouteExists checks if a page with the given route already exists.
-	// Used to enforce route uniqueness constraint.
-	var exists bool
-	err := db.Pool.QueryRow(context.Background(),
-		`SELECT EXISTS(SELECT 1 FROM pages WHERE route=$1)`,
-		route,
-	).Scan(&exists)
-
-	return exists, err
+		page.Status, page.PublishAt, page.VisibleFrom, page.VisibleUntil,
+	))
 }
 
-func ResetHomePage() error {
+func ResetHomePage(ctx context.Context) error {
 	// ResetHomePage sets is_home=false for all pages.
 	// Called before making a different page the home page to maintain the single home page constraint.
-	_, err := db.Pool.Exec(context.Background(),
+	_, err := db.Pool.Exec(ctx,
 		`UPDATE pages SET is_home = false WHERE is_home = true`)
 	return err
 }
 
-func GetPageByID(id string) (*models.Page, error) {
+func GetPageByID(ctx context.Context, id string) (*models.Page, error) {
 	// GetPageByID retrieves a page from the database by its UUID.
 	// Returns nil if the page is not found.
-	var p models.Page
-
-	err := db.Pool.QueryRow(context.Background(),
-		`SELECT id,name,route,is_home,created_at,updated_at 
-		 FROM pages WHERE id=$1`, id).
-		Scan(&p.ID, &p.Name, &p.Route, &p.IsHome, &p.CreatedAt, &p.UpdatedAt)
+	return scanPage(db.Pool.QueryRow(ctx,
+		`SELECT `+pageColumns+` FROM pages WHERE id=$1`, id))
+}
 
+func DeletePage(ctx context.Context, id string) error {
+	// DeletePage removes a page and all associated widgets (due to ON DELETE CASCADE).
+	// Navigation items targeting the page lose their target (ON DELETE SET NULL);
+	// tab bar items are removed in the same transaction, drawer items are left flagged as broken.
+	tx, err := db.Pool.Begin(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return &p, nil
-}
+	defer tx.Rollback(ctx)
 
-func DeletePage(id string) error {
-	// DeletePage removes a page and all associated widgets (due to ON DELETE CASCADE).
-	_, err := db.Pool.Exec(context.Background(),
+	_, err = tx.Exec(ctx,
 		`DELETE FROM pages WHERE id=$1`, id)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := repairTabBar(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func UpdatePage(page models.Page) (*models.Page, error) {
+func UpdatePage(ctx context.Context, page models.Page) (*models.Page, error) {
 	// UpdatePage modifies page details and returns the updated page.
 	// Uses RETURNING clause to get updated timestamps and values in one query.
-	var updatedPage models.Page
-	err := db.Pool.QueryRow(context.Background(),
-		`UPDATE pages 
-		 SET name=$1, route=$2, is_home=$3, updated_at=NOW()
-		 WHERE id=$4 RETURNING id, name, route, is_home, created_at, updated_at`,
-		page.Name, page.Route, page.IsHome, page.ID,
-	).Scan(&updatedPage.ID, &updatedPage.Name, &updatedPage.Route, &updatedPage.IsHome, &updatedPage.CreatedAt, &updatedPage.UpdatedAt)
+	return scanPage(db.Pool.QueryRow(ctx,
+		`UPDATE pages
+		 SET name=$1, route=$2, is_home=$3, status=$4, publish_at=$5,
+		     visible_from=$6, visible_until=$7, updated_at=NOW()
+		 WHERE id=$8 RETURNING `+pageColumns,
+		page.Name, page.Route, page.IsHome,
+		page.Status, page.PublishAt, page.VisibleFrom, page.VisibleUntil, page.ID,
+	))
+}
+
+func UpdatePageWithRedirects(ctx context.Context, page models.Page, deleteRedirectIDs []string, automatic *models.Redirect) (*models.Page, error) {
+	// UpdatePageWithRedirects is UpdatePage for a route change: in the same transaction it
+	// deletes the redirects with deleteRedirectIDs and, unless automatic is nil, records
+	// the automatic redirect from the page's old route (replacing any redirect from the
+	// same route). The services decide which redirects change.
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	updatedPage, err := scanPage(tx.QueryRow(ctx,
+		`UPDATE pages
+		 SET name=$1, route=$2, is_home=$3, status=$4, publish_at=$5,
+		     visible_from=$6, visible_until=$7, updated_at=NOW()
+		 WHERE id=$8 RETURNING `+pageColumns,
+		page.Name, page.Route, page.IsHome,
+		page.Status, page.PublishAt, page.VisibleFrom, page.VisibleUntil, page.ID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deleteRedirectIDs) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM redirects WHERE id = ANY($1::uuid[])`, deleteRedirectIDs); err != nil {
+			return nil, err
+		}
+	}
+	if automatic != nil {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO redirects (from_route, page_id, automatic) VALUES ($1,$2,true)
+			 ON CONFLICT (from_route) DO UPDATE
+			 SET page_id=EXCLUDED.page_id, to_route=NULL, automatic=true, updated_at=NOW()`,
+			automatic.FromRoute, automatic.PageID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return updatedPage, nil
+}
+
+func CreatePageWithWidgets(ctx context.Context, page models.Page, nodes []models.WidgetNode) (*models.Page, error) {
+	// CreatePageWithWidgets inserts a new page together with a widget tree built from nodes.
+	// Used to instantiate templates; runs in a transaction so a page is never left half-populated.
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	createdPage, err := scanPage(tx.QueryRow(ctx,
+		`INSERT INTO pages (name, route, is_home, status, publish_at, visible_from, visible_until)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING `+pageColumns,
+		page.Name, page.Route, page.IsHome,
+		page.Status, page.PublishAt, page.VisibleFrom, page.VisibleUntil,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertWidgetNodes(ctx, tx, createdPage.ID, nil, nodes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return createdPage, nil
+}
 
+func GetDuePages(ctx context.Context, now time.Time) ([]models.Page, error) {
+	// GetDuePages retrieves the drafts whose publish_at is at or before now.
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+pageColumns+` FROM pages
+		 WHERE status='draft' AND publish_at <= $1 ORDER BY publish_at`, now)
 	if err != nil {
 		return nil, err
 	}
-	return &updatedPage, nil
+	defer rows.Close()
+
+	var pages []models.Page
+	for rows.Next() {
+		p, err := scanPage(rows)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, *p)
+	}
+	return pages, rows.Err()
 }
 
-func RouteExistsForOtherPage(route, id string) (bool, error) {
-	// RouteExistsForOtherPage checks if a route exists on a different page.
-	// Used during update to allow the same page to keep its own route.
-	var exists bool
-	err := db.Pool.QueryRow(context.Background(),
-		`SELECT EXISTS(SELECT 1 FROM pages WHERE route=$1 AND id != $2)`,
-		route, id,
-	).Scan(&exists)
+func PublishScheduledPage(ctx context.Context, id string) (bool, error) {
+	// PublishScheduledPage publishes a draft and clears its publish_at, unless it was
+	// published or unscheduled meanwhile. Returns whether the page was published.
+	tag, err := db.Pool.Exec(ctx,
+		`UPDATE pages SET status='published', publish_at=NULL, updated_at=NOW()
+		 WHERE id=$1 AND status='draft' AND publish_at IS NOT NULL`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
 
-	return exists, err
+func UnschedulePage(ctx context.Context, id string) error {
+	// UnschedulePage clears a draft's publish_at, leaving it a draft.
+	_, err := db.Pool.Exec(ctx,
+		`UPDATE pages SET publish_at=NULL, updated_at=NOW() WHERE id=$1 AND status='draft'`, id)
+	return err
+}
+
+func PublishDuePages(ctx context.Context, now time.Time) ([]string, error) {
+	// PublishDuePages publishes every draft whose publish_at is at or before now
+	// and clears its publish_at. Returns the IDs of the pages it published.
+	rows, err := db.Pool.Query(ctx,
+		`UPDATE pages SET status='published', publish_at=NULL, updated_at=NOW()
+		 WHERE status='draft' AND publish_at <= $1 RETURNING id`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
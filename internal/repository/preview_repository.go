@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// previewLinkColumns is the column list selected for every preview link query, in scanPreviewLink order.
+const previewLinkColumns = `id,page_id,label,expires_at,revoked_at,created_at`
+
+// scanPreviewLink reads one preview link row selected with previewLinkColumns.
+// The token is not stored; the service layer derives it from the ID and expiry.
+func scanPreviewLink(row pgx.Row) (*models.PreviewLink, error) {
+	var l models.PreviewLink
+	err := row.Scan(&l.ID, &l.PageID, &l.Label, &l.ExpiresAt, &l.RevokedAt, &l.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// CreatePreviewLink inserts a preview link for a page and returns it with its generated ID.
+func CreatePreviewLink(ctx context.Context, pageID, label string, expiresAt time.Time) (*models.PreviewLink, error) {
+	return scanPreviewLink(db.Pool.QueryRow(ctx,
+		`INSERT INTO preview_links (page_id, label, expires_at)
+		 VALUES ($1,$2,$3) RETURNING `+previewLinkColumns,
+		pageID, label, expiresAt))
+}
+
+// GetPreviewLinkByID retrieves a preview link by its UUID, revoked or expired ones included.
+func GetPreviewLinkByID(ctx context.Context, id string) (*models.PreviewLink, error) {
+	return scanPreviewLink(db.Pool.QueryRow(ctx,
+		`SELECT `+previewLinkColumns+` FROM preview_links WHERE id=$1`, id))
+}
+
+// GetActivePreviewLinks retrieves a page's links that are neither revoked nor expired
+// at now, soonest to expire first.
+func GetActivePreviewLinks(ctx context.Context, pageID string, now time.Time) ([]models.PreviewLink, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+previewLinkColumns+` FROM preview_links
+		 WHERE page_id=$1 AND revoked_at IS NULL AND expires_at > $2
+		 ORDER BY expires_at, created_at`, pageID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.PreviewLink
+	for rows.Next() {
+		l, err := scanPreviewLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *l)
+	}
+	return links, rows.Err()
+}
+
+// RevokePreviewLink marks a page's link as revoked.
+// Returns false if the page has no such link or it was already revoked.
+func RevokePreviewLink(ctx context.Context, pageID, id string) (bool, error) {
+	tag, err := db.Pool.Exec(ctx,
+		`UPDATE preview_links SET revoked_at=NOW()
+		 WHERE id=$1 AND page_id=$2 AND revoked_at IS NULL`, id, pageID)
+	return tag.RowsAffected() > 0, err
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// productColumns is the column list selected for every product query, in scanProduct order.
+const productColumns = `id,sku,name,description,price_cents,currency,images,in_stock,created_at,updated_at`
+
+// scanProduct reads one product row selected with productColumns.
+// Unmarshals the JSONB images.
+func scanProduct(row pgx.Row) (*models.Product, error) {
+	var p models.Product
+	var imagesJSON []byte
+
+	err := row.Scan(&p.ID, &p.SKU, &p.Name, &p.Description, &p.PriceCents, &p.Currency,
+		&imagesJSON, &p.InStock, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(imagesJSON, &p.Images); err != nil {
+		return nil, err
+	}
+	if p.Images == nil {
+		p.Images = []models.ProductImage{}
+	}
+	return &p, nil
+}
+
+func queryProducts(ctx context.Context, sql string, args ...any) ([]models.Product, error) {
+	rows, err := db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []models.Product
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *p)
+	}
+	return products, rows.Err()
+}
+
+// GetProducts retrieves every product ordered by name.
+func GetProducts(ctx context.Context) ([]models.Product, error) {
+	return queryProducts(ctx,
+		`SELECT `+productColumns+` FROM products ORDER BY name, sku`)
+}
+
+// GetProductByID retrieves a single product by its UUID.
+// Returns an error if the product is not found.
+func GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	return scanProduct(db.Pool.QueryRow(ctx,
+		`SELECT `+productColumns+` FROM products WHERE id=$1`, id))
+}
+
+// GetProductsByIDs retrieves the products with the given IDs, keyed by ID; IDs that
+// match no product (including malformed ones) are skipped.
+func GetProductsByIDs(ctx context.Context, ids []string) (map[string]*models.Product, error) {
+	products, err := queryProducts(ctx,
+		`SELECT `+productColumns+` FROM products WHERE id::text = ANY($1)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	return byID, nil
+}
+
+// CreateProduct inserts a new product and returns it with its generated ID.
+func CreateProduct(ctx context.Context, product models.Product) (*models.Product, error) {
+	imagesJSON, err := json.Marshal(product.Images)
+	if err != nil {
+		return nil, err
+	}
+	return scanProduct(db.Pool.QueryRow(ctx,
+		`INSERT INTO products (sku, name, description, price_cents, currency, images, in_stock)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING `+productColumns,
+		product.SKU, product.Name, product.Description, product.PriceCents, product.Currency,
+		imagesJSON, product.InStock))
+}
+
+// UpdateProduct replaces a product's fields and bumps its updated_at timestamp.
+func UpdateProduct(ctx context.Context, product models.Product) (*models.Product, error) {
+	imagesJSON, err := json.Marshal(product.Images)
+	if err != nil {
+		return nil, err
+	}
+	return scanProduct(db.Pool.QueryRow(ctx,
+		`UPDATE products
+		 SET sku=$2, name=$3, description=$4, price_cents=$5, currency=$6, images=$7, in_stock=$8, updated_at=NOW()
+		 WHERE id=$1 RETURNING `+productColumns,
+		product.ID, product.SKU, product.Name, product.Description, product.PriceCents, product.Currency,
+		imagesJSON, product.InStock))
+}
+
+// DeleteProduct removes a product; it is taken out of every collection by cascade.
+func DeleteProduct(ctx context.Context, id string) error {
+	_, err := db.Pool.Exec(ctx, `DELETE FROM products WHERE id=$1`, id)
+	return err
+}
+
+// ProductSKUExists checks if a product other than excludeID already uses sku.
+// Pass an empty excludeID when creating.
+func ProductSKUExists(ctx context.Context, sku, excludeID string) (bool, error) {
+	var exists bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM products WHERE sku=$1 AND id::text != $2)`,
+		sku, excludeID).Scan(&exists)
+	return exists, err
+}
+
+// ProductInUse reports whether a widget, component definition or experiment override
+// lists the product in a product_grid's product_ids or binds one of its fields
+// ("{{product.<id>.name}}").
+func ProductInUse(ctx context.Context, id string) (bool, error) {
+	var inUse bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM widgets
+		                WHERE config->'product_ids' ? $1 OR strpos(config::text, 'product.' || $1 || '.') > 0)
+		     OR EXISTS (SELECT 1 FROM components
+		                WHERE jsonb_path_exists(root, '$.** ? (@.product_ids[*] == $id)', jsonb_build_object('id', $1::text))
+		                   OR strpos(root::text, 'product.' || $1 || '.') > 0)
+		     OR EXISTS (SELECT 1 FROM experiments
+		                WHERE jsonb_path_exists(variants, '$[*].overrides[*].config ? (@.product_ids[*] == $id)', jsonb_build_object('id', $1::text))
+		                   OR strpos(variants::text, 'product.' || $1 || '.') > 0)`,
+		id).Scan(&inUse)
+	return inUse, err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// redirectColumns is the column list selected for every redirect query, in scanRedirect order.
+const redirectColumns = `id,from_route,page_id,to_route,automatic,created_at,updated_at`
+
+// scanRedirect reads one redirect row selected with redirectColumns.
+func scanRedirect(row pgx.Row) (*models.Redirect, error) {
+	var r models.Redirect
+	err := row.Scan(&r.ID, &r.FromRoute, &r.PageID, &r.ToRoute, &r.Automatic, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetRedirects retrieves every redirect ordered by from_route.
+func GetRedirects(ctx context.Context) ([]models.Redirect, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+redirectColumns+` FROM redirects ORDER BY from_route`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redirects []models.Redirect
+	for rows.Next() {
+		r, err := scanRedirect(rows)
+		if err != nil {
+			return nil, err
+		}
+		redirects = append(redirects, *r)
+	}
+	return redirects, rows.Err()
+}
+
+// GetRedirectByID retrieves a single redirect by its UUID.
+func GetRedirectByID(ctx context.Context, id string) (*models.Redirect, error) {
+	return scanRedirect(db.Pool.QueryRow(ctx,
+		`SELECT `+redirectColumns+` FROM redirects WHERE id=$1`, id))
+}
+
+// CreateRedirect inserts a manual redirect and returns it.
+func CreateRedirect(ctx context.Context, r models.Redirect) (*models.Redirect, error) {
+	return scanRedirect(db.Pool.QueryRow(ctx,
+		`INSERT INTO redirects (from_route, page_id, to_route)
+		 VALUES ($1,$2,$3) RETURNING `+redirectColumns,
+		r.FromRoute, r.PageID, r.ToRoute))
+}
+
+// UpdateRedirect replaces a redirect's routes and target. An edited redirect is no
+// longer automatic.
+func UpdateRedirect(ctx context.Context, r models.Redirect) (*models.Redirect, error) {
+	return scanRedirect(db.Pool.QueryRow(ctx,
+		`UPDATE redirects SET from_route=$1, page_id=$2, to_route=$3, automatic=false, updated_at=NOW()
+		 WHERE id=$4 RETURNING `+redirectColumns,
+		r.FromRoute, r.PageID, r.ToRoute, r.ID))
+}
+
+// DeleteRedirect removes a redirect.
+func DeleteRedirect(ctx context.Context, id string) error {
+	_, err := db.Pool.Exec(ctx, `DELETE FROM redirects WHERE id=$1`, id)
+	return err
+}
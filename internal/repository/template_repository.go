@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// templateColumns is the column list selected for every template query, in scanTemplate order.
+const templateColumns = `id,name,category,description,preview_image_url,widgets,is_builtin,created_at,updated_at`
+
+// scanTemplate reads one template row selected with templateColumns.
+// Unmarshals the JSONB widgets into WidgetNode definitions.
+func scanTemplate(row pgx.Row) (*models.Template, error) {
+	var t models.Template
+	var widgetsJSON []byte
+
+	err := row.Scan(&t.ID, &t.Name, &t.Category, &t.Description, &t.PreviewImageURL,
+		&widgetsJSON, &t.IsBuiltin, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(widgetsJSON, &t.Widgets); err != nil {
+		return nil, err
+	}
+	if t.Widgets == nil {
+		t.Widgets = []models.WidgetNode{}
+	}
+	return &t, nil
+}
+
+// GetTemplates retrieves templates matching filter, built-in templates first, then by name.
+// Category matches exactly (ignoring case); Query matches anywhere in name or description.
+func GetTemplates(ctx context.Context, filter models.TemplateFilter) ([]models.Template, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+templateColumns+` FROM templates
+		 WHERE ($1 = '' OR lower(category) = lower($1))
+		   AND ($2 = '' OR name ILIKE '%' || $2 || '%' OR description ILIKE '%' || $2 || '%')
+		 ORDER BY is_builtin DESC, name`,
+		filter.Category, filter.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.Template
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// GetTemplateByID retrieves a single template by its UUID.
+// Returns an error if the template is not found.
+func GetTemplateByID(ctx context.Context, id string) (*models.Template, error) {
+	return scanTemplate(db.Pool.QueryRow(ctx,
+		`SELECT `+templateColumns+` FROM templates WHERE id=$1`, id))
+}
+
+// CreateTemplate inserts a new (non built-in) template and returns it with its generated ID and timestamps.
+func CreateTemplate(ctx context.Context, template models.Template) (*models.Template, error) {
+	widgetsData, err := json.Marshal(template.Widgets)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTemplate(db.Pool.QueryRow(ctx,
+		`INSERT INTO templates (name, category, description, preview_image_url, widgets)
+		 VALUES ($1,$2,$3,$4,$5) RETURNING `+templateColumns,
+		template.Name, template.Category, template.Description, template.PreviewImageURL, string(widgetsData)))
+}
+
+// DeleteTemplate removes a template. Pages created from it are unaffected.
+func DeleteTemplate(ctx context.Context, id string) error {
+	_, err := db.Pool.Exec(ctx, `DELETE FROM templates WHERE id=$1`, id)
+	return err
+}
+
+// TemplateNameExists checks if a template already uses name.
+func TemplateNameExists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM templates WHERE name=$1)`, name).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"context"
+	"encoding/json"
+)
+
+// GetTheme retrieves the app theme (a single row seeded by the migration).
+func GetTheme(ctx context.Context) (*models.Theme, error) {
+	var t models.Theme
+	var tokensJSON []byte
+
+	err := db.Pool.QueryRow(ctx,
+		`SELECT tokens, updated_at FROM theme`).Scan(&tokensJSON, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(tokensJSON, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpdateTheme replaces the app theme's tokens and returns the stored theme.
+func UpdateTheme(ctx context.Context, theme models.Theme) (*models.Theme, error) {
+	tokens := map[string]interface{}{
+		"colors":      theme.Colors,
+		"typography":  theme.Typography,
+		"spacing":     theme.Spacing,
+		"radius":      theme.Radius,
+		"dark_colors": theme.DarkColors,
+	}
+	tokensData, err := json.Marshal(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Pool.Exec(ctx,
+		`UPDATE theme SET tokens=$1, updated_at=NOW()`, string(tokensData))
+	if err != nil {
+		return nil, err
+	}
+	return GetTheme(ctx)
+}
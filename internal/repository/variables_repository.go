@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+)
+
+// GetStoreVariables retrieves the store variables (a single row seeded by the migration).
+func GetStoreVariables(ctx context.Context) (*models.StoreVariables, error) {
+	var v models.StoreVariables
+	var variablesJSON []byte
+
+	err := db.Pool.QueryRow(ctx,
+		`SELECT variables, updated_at FROM store_variables`).Scan(&variablesJSON, &v.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(variablesJSON, &v.Variables); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// UpdateStoreVariables replaces the store variables and returns the stored set.
+func UpdateStoreVariables(ctx context.Context, variables map[string]interface{}) (*models.StoreVariables, error) {
+	variablesData, err := json.Marshal(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Pool.Exec(ctx,
+		`UPDATE store_variables SET variables=$1, updated_at=NOW()`, string(variablesData))
+	if err != nil {
+		return nil, err
+	}
+	return GetStoreVariables(ctx)
+}
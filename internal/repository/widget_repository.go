@@ -3,17 +3,45 @@ package repository
 import (
 	"appdrop-api/internal/db"
 	"appdrop-api/internal/models"
+	"appdrop-api/internal/utils"
 	"context"
 	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
 )
 
-// GetWidgetsByPageID retrieves all widgets for a specific page.
-// Returns widgets ordered by position (top to bottom).
+// widgetColumns is the column list selected for every widget query, in scanWidget order.
+const widgetColumns = `id,page_id,parent_id,type,component_id,position,config,visible_from,visible_until,targeting,created_at,updated_at`
+
+// scanWidget reads one widget row selected with widgetColumns.
 // Unmarshals JSONB config field into Go map structure.
-func GetWidgetsByPageID(pageID string) ([]models.Widget, error) {
+func scanWidget(row pgx.Row) (*models.Widget, error) {
+	var w models.Widget
+	var configJSON []byte
+
+	err := row.Scan(
+		&w.ID, &w.PageID, &w.ParentID, &w.Type, &w.ComponentID,
+		&w.Position, &configJSON, &w.VisibleFrom, &w.VisibleUntil, &w.Targeting,
+		&w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse JSONB config
+	if configJSON != nil {
+		json.Unmarshal(configJSON, &w.Config)
+	}
+	return &w, nil
+}
+
+// GetWidgetsByPageID retrieves all widgets for a specific page, including nested children.
+// Returns a flat list ordered by position (top to bottom) within each parent;
+// the service layer assembles it into a tree using ParentID.
+func GetWidgetsByPageID(ctx context.Context, pageID string) ([]models.Widget, error) {
 
-	rows, err := db.Pool.Query(context.Background(),
-		`SELECT id,page_id,type,position,config,created_at,updated_at 
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+widgetColumns+`
 		 FROM widgets WHERE page_id=$1 ORDER BY position`, pageID)
 	if err != nil {
 		return nil, err
@@ -23,56 +51,48 @@ func GetWidgetsByPageID(pageID string) ([]models.Widget, error) {
 	var widgets []models.Widget
 
 	for rows.Next() {
-		var w models.Widget
-		var configJSON []byte
-		err := rows.Scan(
-			&w.ID, &w.PageID, &w.Type,
-			&w.Position, &configJSON,
-			&w.CreatedAt, &w.UpdatedAt,
-		)
+		w, err := scanWidget(rows)
 		if err != nil {
 			return nil, err
 		}
-		// Parse JSONB config
-		if configJSON != nil {
-			json.Unmarshal(configJSON, &w.Config)
-		}
-		widgets = append(widgets, w)
+		widgets = append(widgets, *w)
 	}
 
-	return widgets, nil
+	return widgets, rows.Err()
 }
 
-func GetWidgetByID(id string) (*models.Widget, error) {
-	// GetWidgetByID retrieves a single widget by its UUID.
-	// Unmarshals JSONB config field into Go map structure.
-	// Returns nil if widget not found.
-	var w models.Widget
-	var configJSON []byte
-
-	err := db.Pool.QueryRow(context.Background(),
-		`SELECT id,page_id,type,position,config,created_at,updated_at 
-		 FROM widgets WHERE id=$1`, id).
-		Scan(&w.ID, &w.PageID, &w.Type, &w.Position, &configJSON, &w.CreatedAt, &w.UpdatedAt)
-
+// GetAllWidgets retrieves every widget of every page, ordered by page and then by
+// position, for checks that look at all pages at once.
+func GetAllWidgets(ctx context.Context) ([]models.Widget, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+widgetColumns+` FROM widgets ORDER BY page_id, position`)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// Parse JSONB config
-	if configJSON != nil {
-		json.Unmarshal(configJSON, &w.Config)
+	var widgets []models.Widget
+	for rows.Next() {
+		w, err := scanWidget(rows)
+		if err != nil {
+			return nil, err
+		}
+		widgets = append(widgets, *w)
 	}
+	return widgets, rows.Err()
+}
 
-	return &w, nil
+func GetWidgetByID(ctx context.Context, id string) (*models.Widget, error) {
+	// GetWidgetByID retrieves a single widget by its UUID.
+	// Returns nil if widget not found.
+	return scanWidget(db.Pool.QueryRow(ctx,
+		`SELECT `+widgetColumns+` FROM widgets WHERE id=$1`, id))
 }
 
-func CreateWidget(widget models.Widget) (*models.Widget, error) {
+func CreateWidget(ctx context.Context, widget models.Widget) (*models.Widget, error) {
 	// CreateWidget inserts a new widget into the database.
 	// Marshals widget Config map to JSON JSONB for storage.
 	// Uses RETURNING clause to get auto-generated ID and timestamps.
-	var createdWidget models.Widget
-	var configJSON []byte
 
 	// Marshal config to JSON for storage
 	configData, err := json.Marshal(widget.Config)
@@ -80,30 +100,19 @@ func CreateWidget(widget models.Widget) (*models.Widget, error) {
 		return nil, err
 	}
 
-	err = db.Pool.QueryRow(context.Background(),
-		`INSERT INTO widgets (page_id,type,position,config)
-		 VALUES ($1,$2,$3,$4) RETURNING id,page_id,type,position,config,created_at,updated_at`,
-		widget.PageID, widget.Type, widget.Position, string(configData),
-	).Scan(&createdWidget.ID, &createdWidget.PageID, &createdWidget.Type, &createdWidget.Position, &configJSON, &createdWidget.CreatedAt, &createdWidget.UpdatedAt)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse returned config
-	if configJSON != nil {
-		json.Unmarshal(configJSON, &createdWidget.Config)
-	}
-
-	return &createdWidget, nil
+	return scanWidget(db.Pool.QueryRow(ctx,
+		`INSERT INTO widgets (page_id,parent_id,type,component_id,position,config,visible_from,visible_until,targeting)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING `+widgetColumns,
+		widget.PageID, widget.ParentID, widget.Type, widget.ComponentID, widget.Position, string(configData),
+		widget.VisibleFrom, widget.VisibleUntil, widget.Targeting,
+	))
 }
 
-func UpdateWidget(widget models.Widget) (*models.Widget, error) {
+func UpdateWidget(ctx context.Context, widget models.Widget) (*models.Widget, error) {
 	// UpdateWidget modifies widget properties and returns the updated widget.
 	// Marshals widget Config map to JSON JSONB for storage.
 	// Uses RETURNING clause to get updated timestamps and values.
-	var updatedWidget models.Widget
-	var configJSON []byte
+	// The widget's page and parent are not changed; use MoveWidget for that.
 
 	// Marshal config to JSON for storage
 	configData, err := json.Marshal(widget.Config)
@@ -111,44 +120,36 @@ func UpdateWidget(widget models.Widget) (*models.Widget, error) {
 		return nil, err
 	}
 
-	err = db.Pool.QueryRow(context.Background(),
-		`UPDATE widgets 
-		 SET type=$1, position=$2, config=$3, updated_at=NOW()
-		 WHERE id=$4 RETURNING id,page_id,type,position,config,created_at,updated_at`,
-		widget.Type, widget.Position, string(configData), widget.ID,
-	).Scan(&updatedWidget.ID, &updatedWidget.PageID, &updatedWidget.Type, &updatedWidget.Position, &configJSON, &updatedWidget.CreatedAt, &updatedWidget.UpdatedAt)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse returned config
-	if configJSON != nil {
-		json.Unmarshal(configJSON, &updatedWidget.Config)
-	}
-
-	return &updatedWidget, nil
+	return scanWidget(db.Pool.QueryRow(ctx,
+		`UPDATE widgets
+		 SET type=$1, component_id=$2, position=$3, config=$4,
+		     visible_from=$5, visible_until=$6, targeting=$7, updated_at=NOW()
+		 WHERE id=$8 RETURNING `+widgetColumns,
+		widget.Type, widget.ComponentID, widget.Position, string(configData),
+		widget.VisibleFrom, widget.VisibleUntil, widget.Targeting, widget.ID,
+	))
 }
 
-func DeleteWidget(id string) error {
+func DeleteWidget(ctx context.Context, id string) error {
 	// DeleteWidget removes a widget by its ID.
-	_, err := db.Pool.Exec(context.Background(),
+	// Child widgets of containers are removed too (due to ON DELETE CASCADE on parent_id).
+	_, err := db.Pool.Exec(ctx,
 		`DELETE FROM widgets WHERE id=$1`, id)
 	return err
 }
 
-func ReorderWidgets(pageID string, ids []string) error {
+func ReorderWidgets(ctx context.Context, pageID string, ids []string) error {
 	// ReorderWidgets updates the position of all widgets on a page.
 	// Uses a database transaction to ensure all updates succeed together or fail together.
 	// Position is set based on the index in the ids array (0-based).
-	tx, err := db.Pool.Begin(context.Background())
+	tx, err := db.Pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback(context.Background())
+	defer tx.Rollback(ctx)
 
 	for index, id := range ids {
-		_, err := tx.Exec(context.Background(),
+		_, err := tx.Exec(ctx,
 			`UPDATE widgets SET position=$1, updated_at=NOW() WHERE id=$2 AND page_id=$3`,
 			index, id, pageID)
 		if err != nil {
@@ -156,5 +157,138 @@ func ReorderWidgets(pageID string, ids []string) error {
 		}
 	}
 
-	return tx.Commit(context.Background())
+	return tx.Commit(ctx)
+}
+
+func MoveWidget(ctx context.Context, id string, parentID *string, oldSiblings, newSiblings []string) error {
+	// MoveWidget re-parents a widget and renumbers the positions of both sibling lists.
+	// oldSiblings is the former parent's child order without the widget;
+	// newSiblings is the new parent's child order including it.
+	// Runs in a transaction so the tree is never left half-moved.
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`UPDATE widgets SET parent_id=$1, updated_at=NOW() WHERE id=$2`,
+		parentID, id)
+	if err != nil {
+		return err
+	}
+
+	for _, siblings := range [][]string{oldSiblings, newSiblings} {
+		for index, siblingID := range siblings {
+			_, err := tx.Exec(ctx,
+				`UPDATE widgets SET position=$1, updated_at=NOW() WHERE id=$2`,
+				index, siblingID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func DetachWidget(ctx context.Context, id string, root models.WidgetNode) error {
+	// DetachWidget converts a component reference widget into a local copy of root.
+	// The widget keeps its ID, page and position; it takes root's type and config,
+	// drops its component_id, and root's children are inserted beneath it.
+	// Runs in a transaction so a half-copied subtree is never visible.
+	configData, err := json.Marshal(root.Config)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var pageID string
+	err = tx.QueryRow(ctx,
+		`UPDATE widgets SET type=$1, config=$2, component_id=NULL, updated_at=NOW()
+		 WHERE id=$3 RETURNING page_id`,
+		root.Type, string(configData), id).Scan(&pageID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertWidgetNodes(ctx, tx, pageID, &id, root.Children); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// insertWidgetNodes creates widgets for nodes (and, recursively, their children)
+// under parentID on a page, numbering positions from 0 in slice order.
+func insertWidgetNodes(ctx context.Context, tx pgx.Tx, pageID string, parentID *string, nodes []models.WidgetNode) error {
+	for index, node := range nodes {
+		configData, err := json.Marshal(node.Config)
+		if err != nil {
+			return err
+		}
+
+		var id string
+		err = tx.QueryRow(ctx,
+			`INSERT INTO widgets (page_id,parent_id,type,component_id,position,config)
+			 VALUES ($1,$2,$3,$4,$5,$6) RETURNING id`,
+			pageID, parentID, node.Type, node.ComponentID, index, string(configData)).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		if err := insertWidgetNodes(ctx, tx, pageID, &id, node.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetWidgetsWithReferences retrieves every widget, on any page, whose config contains
+// a "{{" reference. Used to check that theme changes don't break existing widgets.
+func GetWidgetsWithReferences(ctx context.Context) ([]models.Widget, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+widgetColumns+`
+		 FROM widgets WHERE config::text LIKE '%{{%' ORDER BY page_id, position`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var widgets []models.Widget
+	for rows.Next() {
+		w, err := scanWidget(rows)
+		if err != nil {
+			return nil, err
+		}
+		widgets = append(widgets, *w)
+	}
+	return widgets, rows.Err()
+}
+
+// GetWidgetsWithLinks retrieves every widget, on any page, whose config has one of
+// utils.LinkConfigKeys. Used to check links to pages.
+func GetWidgetsWithLinks(ctx context.Context) ([]models.Widget, error) {
+	rows, err := db.Pool.Query(ctx,
+		`SELECT `+widgetColumns+`
+		 FROM widgets WHERE config ?| $1 ORDER BY page_id, position`, utils.LinkConfigKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var widgets []models.Widget
+	for rows.Next() {
+		w, err := scanWidget(rows)
+		if err != nil {
+			return nil, err
+		}
+		widgets = append(widgets, *w)
+	}
+	return widgets, rows.Err()
 }
@@ -0,0 +1,187 @@
+// Package router defines the AppDrop API route table and builds the HTTP multiplexer.
+// Keeping every route in one table lets the OpenAPI specification be checked
+// against what the server actually serves.
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"appdrop-api/internal/handlers"
+)
+
+// Route describes a single API endpoint.
+type Route struct {
+	// Method is the HTTP method (GET, POST, PUT, DELETE)
+	Method string
+	// Path is the URL pattern with {name} wildcards, e.g. "/pages/{id}".
+	// The same syntax is used by net/http.ServeMux and OpenAPI path templates.
+	Path string
+	// Handler serves the endpoint; path wildcards are read with r.PathValue
+	Handler http.HandlerFunc
+}
+
+// Routes returns every endpoint served by the API.
+func Routes() []Route {
+	return []Route{
+		// Health probes and API documentation
+		{http.MethodGet, "/livez", handlers.LivezHandler},
+		{http.MethodGet, "/readyz", handlers.ReadyzHandler},
+		{http.MethodGet, "/health", handlers.ReadyzHandler},
+		{http.MethodGet, "/openapi.json", handlers.OpenAPIHandler},
+
+		// Pages
+		{http.MethodGet, "/pages", handlers.GetPagesHandler},
+		{http.MethodPost, "/pages", handlers.CreatePageHandler},
+		{http.MethodGet, "/pages/{id}", handlers.GetPageByIDHandler},
+		{http.MethodPut, "/pages/{id}", handlers.UpdatePageHandler},
+		{http.MethodDelete, "/pages/{id}", handlers.DeletePageHandler},
+		{http.MethodPost, "/pages/from-template/{templateId}", handlers.CreatePageFromTemplateHandler},
+		{http.MethodGet, "/resolve", handlers.ResolvePathHandler},
+		{http.MethodGet, "/routes", handlers.GetRouteTableHandler},
+
+		// Redirects
+		{http.MethodGet, "/redirects", handlers.GetRedirectsHandler},
+		{http.MethodPost, "/redirects", handlers.CreateRedirectHandler},
+		{http.MethodGet, "/redirects/{id}", handlers.GetRedirectHandler},
+		{http.MethodPut, "/redirects/{id}", handlers.UpdateRedirectHandler},
+		{http.MethodDelete, "/redirects/{id}", handlers.DeleteRedirectHandler},
+
+		// Widgets
+		{http.MethodPost, "/pages/{id}/widgets", handlers.CreateWidgetHandler},
+		{http.MethodPost, "/pages/{id}/widgets/reorder", handlers.ReorderWidgetsHandler},
+		{http.MethodPut, "/widgets/{id}", handlers.UpdateWidgetHandler},
+		{http.MethodDelete, "/widgets/{id}", handlers.DeleteWidgetHandler},
+		{http.MethodPost, "/widgets/{id}/move", handlers.MoveWidgetHandler},
+		{http.MethodPost, "/widgets/{id}/detach", handlers.DetachWidgetHandler},
+
+		// Reusable components
+		{http.MethodGet, "/components", handlers.GetComponentsHandler},
+		{http.MethodPost, "/components", handlers.CreateComponentHandler},
+		{http.MethodGet, "/components/{id}", handlers.GetComponentHandler},
+		{http.MethodPut, "/components/{id}", handlers.UpdateComponentHandler},
+		{http.MethodDelete, "/components/{id}", handlers.DeleteComponentHandler},
+		{http.MethodGet, "/components/{id}/usages", handlers.GetComponentUsagesHandler},
+
+		// Localization
+		{http.MethodGet, "/locales", handlers.GetLocalesHandler},
+		{http.MethodPut, "/locales", handlers.UpdateLocalesHandler},
+		{http.MethodGet, "/pages/{id}/translations", handlers.GetPageTranslationsHandler},
+		{http.MethodPut, "/pages/{id}/translations/{locale}", handlers.SetPageTranslationHandler},
+		{http.MethodDelete, "/pages/{id}/translations/{locale}", handlers.DeletePageTranslationHandler},
+		{http.MethodGet, "/pages/{id}/missing-translations", handlers.GetMissingTranslationsHandler},
+		{http.MethodPut, "/widgets/{id}/translations/{locale}", handlers.SetWidgetTranslationHandler},
+		{http.MethodDelete, "/widgets/{id}/translations/{locale}", handlers.DeleteWidgetTranslationHandler},
+
+		// Page templates
+		{http.MethodGet, "/templates", handlers.GetTemplatesHandler},
+		{http.MethodPost, "/templates", handlers.CreateTemplateHandler},
+		{http.MethodGet, "/templates/{id}", handlers.GetTemplateHandler},
+		{http.MethodDelete, "/templates/{id}", handlers.DeleteTemplateHandler},
+
+		// App navigation and manifest
+		{http.MethodGet, "/navigation", handlers.GetNavigationHandler},
+		{http.MethodPut, "/navigation", handlers.UpdateNavigationHandler},
+		{http.MethodGet, "/manifest", handlers.GetManifestHandler},
+
+		// Media assets
+		{http.MethodGet, "/assets", handlers.GetAssetsHandler},
+		{http.MethodPost, "/assets", handlers.UploadAssetHandler},
+		{http.MethodGet, "/assets/{id}", handlers.GetAssetHandler},
+		{http.MethodDelete, "/assets/{id}", handlers.DeleteAssetHandler},
+		{http.MethodGet, "/assets/{id}/content", handlers.GetAssetContentHandler},
+		{http.MethodGet, "/assets/{id}/variants/{width}", handlers.GetAssetVariantHandler},
+		{http.MethodPost, "/assets/{id}/variants", handlers.RegenerateAssetVariantsHandler},
+
+		// Product catalog
+		{http.MethodGet, "/products", handlers.GetProductsHandler},
+		{http.MethodPost, "/products", handlers.CreateProductHandler},
+		{http.MethodGet, "/products/{id}", handlers.GetProductHandler},
+		{http.MethodPut, "/products/{id}", handlers.UpdateProductHandler},
+		{http.MethodDelete, "/products/{id}", handlers.DeleteProductHandler},
+		{http.MethodGet, "/collections", handlers.GetCollectionsHandler},
+		{http.MethodPost, "/collections", handlers.CreateCollectionHandler},
+		{http.MethodGet, "/collections/{id}", handlers.GetCollectionHandler},
+		{http.MethodPut, "/collections/{id}", handlers.UpdateCollectionHandler},
+		{http.MethodDelete, "/collections/{id}", handlers.DeleteCollectionHandler},
+		{http.MethodGet, "/collections/{id}/products", handlers.GetCollectionProductsHandler},
+
+		// Theme
+		{http.MethodGet, "/theme", handlers.GetThemeHandler},
+		{http.MethodPut, "/theme", handlers.UpdateThemeHandler},
+
+		// Store variables
+		{http.MethodGet, "/variables", handlers.GetStoreVariablesHandler},
+		{http.MethodPut, "/variables", handlers.UpdateStoreVariablesHandler},
+
+		// A/B experiments
+		{http.MethodGet, "/experiments", handlers.GetExperimentsHandler},
+		{http.MethodPost, "/experiments", handlers.CreateExperimentHandler},
+		{http.MethodGet, "/experiments/{id}", handlers.GetExperimentHandler},
+		{http.MethodPut, "/experiments/{id}", handlers.UpdateExperimentHandler},
+		{http.MethodDelete, "/experiments/{id}", handlers.DeleteExperimentHandler},
+		{http.MethodGet, "/experiments/{id}/exposures", handlers.GetExperimentExposuresHandler},
+
+		// Page linter
+		{http.MethodGet, "/pages/{id}/lint", handlers.LintPageHandler},
+		{http.MethodGet, "/lint", handlers.LintAppHandler},
+		{http.MethodGet, "/lint/rules", handlers.GetLintRulesHandler},
+		{http.MethodGet, "/lint/settings", handlers.GetLintSettingsHandler},
+		{http.MethodPut, "/lint/settings", handlers.UpdateLintSettingsHandler},
+
+		// Audience targeting
+		{http.MethodPost, "/targeting/validate", handlers.ValidateTargetingHandler},
+
+		// Public reads, filtered by status, visibility windows and targeting
+		{http.MethodGet, "/public/pages/{id}", handlers.GetPublicPageHandler},
+		{http.MethodGet, "/public/manifest", handlers.GetPublicManifestHandler},
+		{http.MethodGet, "/public/resolve", handlers.GetPublicResolveHandler},
+
+		// Deep link association files
+		{http.MethodGet, "/.well-known/apple-app-site-association", handlers.GetAppleAppSiteAssociationHandler},
+		{http.MethodGet, "/.well-known/assetlinks.json", handlers.GetAssetLinksHandler},
+
+		// Preview links for unpublished pages
+		{http.MethodGet, "/pages/{id}/preview-links", handlers.GetPreviewLinksHandler},
+		{http.MethodPost, "/pages/{id}/preview-links", handlers.CreatePreviewLinkHandler},
+		{http.MethodDelete, "/pages/{id}/preview-links/{linkId}", handlers.RevokePreviewLinkHandler},
+		{http.MethodGet, "/preview/{token}", handlers.GetPreviewPageHandler},
+	}
+}
+
+// literalPagePrefixes are fixed path segments under /pages that ServeMux cannot tell
+// apart from a page ID: "POST /pages/from-template/{templateId}" and
+// "POST /pages/{id}/widgets" both match "/pages/from-template/widgets", so registering
+// them together panics. Routes under these prefixes are served by a separate mux.
+var literalPagePrefixes = []string{"/pages/from-template/"}
+
+// New returns a handler serving every route from Routes.
+// Requests with an unsupported method for a known path get 405 Method Not Allowed.
+func New() http.Handler {
+	mux := http.NewServeMux()
+	literal := http.NewServeMux()
+	for _, route := range Routes() {
+		target := mux
+		if hasLiteralPagePrefix(route.Path) {
+			target = literal
+		}
+		target.HandleFunc(route.Method+" "+route.Path, route.Handler)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasLiteralPagePrefix(r.URL.Path) {
+			literal.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func hasLiteralPagePrefix(path string) bool {
+	for _, prefix := range literalPagePrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
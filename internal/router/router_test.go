@@ -0,0 +1,56 @@
+package router
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"appdrop-api/internal/openapi"
+)
+
+// specPaths decodes the path items of the embedded OpenAPI document,
+// keyed by path and then lower-case method.
+func specPaths(t *testing.T) map[string]map[string]json.RawMessage {
+	t.Helper()
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapi.Spec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.1") {
+		t.Fatalf("openapi version = %q, want 3.1.x", spec.OpenAPI)
+	}
+	return spec.Paths
+}
+
+func TestSpecDocumentsEveryRoute(t *testing.T) {
+	paths := specPaths(t)
+	for _, route := range Routes() {
+		operations, ok := paths[route.Path]
+		if !ok {
+			t.Errorf("%s %s is registered but missing from openapi.json", route.Method, route.Path)
+			continue
+		}
+		if _, ok := operations[strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is registered but its operation is missing from openapi.json", route.Method, route.Path)
+		}
+	}
+}
+
+func TestSpecHasNoUnservedOperations(t *testing.T) {
+	served := make(map[string]bool)
+	for _, route := range Routes() {
+		served[strings.ToLower(route.Method)+" "+route.Path] = true
+	}
+
+	methods := []string{"get", "post", "put", "patch", "delete"}
+	for path, operations := range specPaths(t) {
+		for _, method := range methods {
+			if _, ok := operations[method]; ok && !served[method+" "+path] {
+				t.Errorf("openapi.json documents %s %s but no such route is registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/storage"
+)
+
+// Asset storage settings, set from configuration at startup.
+var (
+	// Assets stores uploaded asset files
+	Assets storage.BlobStore
+	// MaxUploadBytes is the largest file UploadAsset accepts
+	MaxUploadBytes int64 = 10 << 20
+)
+
+// assetFormats maps the image formats accepted for upload (as named by image.DecodeConfig)
+// to their MIME type and file extension.
+var assetFormats = map[string]struct{ mimeType, ext string }{
+	"png":  {"image/png", ".png"},
+	"jpeg": {"image/jpeg", ".jpg"},
+	"gif":  {"image/gif", ".gif"},
+}
+
+// maxAssetDimension is the largest width or height, in pixels, of an uploaded image.
+const maxAssetDimension = 10000
+
+// maxAssetPixels is the largest width x height of an uploaded image. Variant generation
+// decodes the whole image at 4 bytes per pixel, so this bounds its memory use (160 MB).
+const maxAssetPixels = 40_000_000
+
+// maxAssetFilenameLength is the longest filename recorded for an asset.
+const maxAssetFilenameLength = 255
+
+// GetAssets lists every asset, newest first.
+// Returns an empty list (never nil) when there are none.
+func GetAssets(ctx context.Context) ([]models.Asset, error) {
+	assets, err := repository.GetAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if assets == nil {
+		assets = []models.Asset{}
+	}
+	if err := loadAssetVariants(ctx, assets); err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+// GetAsset retrieves a single asset by ID, with its variants.
+// Returns error if asset not found.
+func GetAsset(ctx context.Context, id string) (*models.Asset, error) {
+	asset, err := repository.GetAssetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "asset not found")
+	}
+	assets := []models.Asset{*asset}
+	if err := loadAssetVariants(ctx, assets); err != nil {
+		return nil, err
+	}
+	return &assets[0], nil
+}
+
+// UploadAsset stores an uploaded image and records it.
+// Business Rules Enforced:
+//   - The file is at most MaxUploadBytes and not empty
+//   - The file is a PNG, JPEG or GIF image (detected from its contents, not its name)
+//     of at most 10000 pixels in each dimension and 40 megapixels in all
+//
+// The filename is only recorded; it is reduced to its base name and defaults to
+// "upload" plus the format's extension.
+// Resized variants are generated in the background (see StartVariantJob).
+// Returns the created asset with its UUID, dimensions, size and checksum.
+func UploadAsset(ctx context.Context, filename string, file io.Reader) (*models.Asset, error) {
+	data, err := io.ReadAll(io.LimitReader(file, MaxUploadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > MaxUploadBytes {
+		return nil, errors.New("file is too large")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	f, ok := assetFormats[format]
+	if err != nil || !ok {
+		return nil, errors.New("file must be a PNG, JPEG or GIF image")
+	}
+	if config.Width < 1 || config.Height < 1 {
+		return nil, errors.New("image has no pixels")
+	}
+	if config.Width > maxAssetDimension || config.Height > maxAssetDimension {
+		return nil, fmt.Errorf("image cannot be larger than %dx%d pixels", maxAssetDimension, maxAssetDimension)
+	}
+	if config.Width*config.Height > maxAssetPixels {
+		return nil, fmt.Errorf("image cannot have more than %d megapixels", maxAssetPixels/1_000_000)
+	}
+
+	filename = strings.TrimSpace(filepath.Base(strings.ReplaceAll(filename, `\`, "/")))
+	if filename == "" || filename == "." || filename == "/" {
+		filename = "upload" + f.ext
+	}
+	if len(filename) > maxAssetFilenameLength {
+		return nil, fmt.Errorf("filename cannot be longer than %d characters", maxAssetFilenameLength)
+	}
+
+	key, err := newAssetKey(f.ext)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+
+	if err := Assets.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	asset, err := repository.CreateAsset(ctx, models.Asset{
+		Filename:   filename,
+		MimeType:   f.mimeType,
+		SizeBytes:  int64(len(data)),
+		Width:      config.Width,
+		Height:     config.Height,
+		Checksum:   hex.EncodeToString(sum[:]),
+		StorageKey: key,
+	})
+	if err != nil {
+		// Don't leave an unreferenced file behind
+		Assets.Delete(context.WithoutCancel(ctx), key)
+		return nil, err
+	}
+	wakeVariantJob()
+	setAssetVariants(asset, nil)
+	return asset, nil
+}
+
+// RegenerateAssetVariants queues an asset for its variants to be generated again, e.g.
+// after the configured widths changed. The current variants are served until the new
+// ones replace them.
+// Returns the queued asset, or an error if asset not found.
+func RegenerateAssetVariants(ctx context.Context, id string) (*models.Asset, error) {
+	found, err := repository.SetAssetVariantsStatus(ctx, id, "pending")
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil || !found {
+		return nil, errors.New("asset not found")
+	}
+	wakeVariantJob()
+	return GetAsset(ctx, id)
+}
+
+// OpenAsset returns an asset with a reader over its file. The caller must close the reader.
+// Returns error if asset not found.
+func OpenAsset(ctx context.Context, id string) (*models.Asset, io.ReadCloser, error) {
+	asset, err := GetAsset(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := Assets.Open(ctx, asset.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, errors.New("asset file is missing")
+		}
+		return nil, nil, err
+	}
+	return asset, file, nil
+}
+
+// OpenAssetVariant returns an asset's variant of the given width with a reader over its
+// file. The caller must close the reader.
+// Returns error if the asset or variant is not found.
+func OpenAssetVariant(ctx context.Context, id string, width int) (*models.Asset, *models.AssetVariant, io.ReadCloser, error) {
+	asset, err := repository.GetAssetByID(ctx, id)
+	if err != nil {
+		return nil, nil, nil, notFound(ctx, "asset not found")
+	}
+	variant, err := repository.GetAssetVariant(ctx, id, width)
+	if err != nil {
+		return nil, nil, nil, notFound(ctx, "asset variant not found")
+	}
+	file, err := Assets.Open(ctx, variant.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, nil, errors.New("asset file is missing")
+		}
+		return nil, nil, nil, err
+	}
+	return asset, variant, file, nil
+}
+
+// DeleteAsset removes an asset, its variants and their files.
+// An asset referenced by a widget, component, experiment override or product image
+// cannot be deleted.
+func DeleteAsset(ctx context.Context, id string) error {
+	asset, err := repository.GetAssetByID(ctx, id)
+	if err != nil {
+		return notFound(ctx, "asset not found")
+	}
+
+	inUse, err := repository.AssetInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("asset is in use")
+	}
+
+	variants, err := repository.GetAssetVariants(ctx, []string{id})
+	if err != nil {
+		return err
+	}
+	if err := repository.DeleteAsset(ctx, id); err != nil {
+		return err
+	}
+	// The records are gone, so a file left behind by a failed delete is unreachable
+	// and harmless; the request has succeeded either way
+	Assets.Delete(context.WithoutCancel(ctx), asset.StorageKey)
+	for _, v := range variants[id] {
+		Assets.Delete(context.WithoutCancel(ctx), v.StorageKey)
+	}
+	return nil
+}
+
+// validateAssetReference checks that a config's asset_id, if set, references an
+// existing asset.
+func validateAssetReference(ctx context.Context, config map[string]interface{}) error {
+	value, ok := config["asset_id"]
+	if !ok {
+		return nil
+	}
+	id, ok := value.(string)
+	if !ok || id == "" {
+		return errors.New("config.asset_id must be an asset ID string")
+	}
+	if _, err := repository.GetAssetByID(ctx, id); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errors.New("config.asset_id must reference an existing asset")
+	}
+	return nil
+}
+
+// validateNodeAssetReferences is validateAssetReference for a WidgetNode tree.
+// path names the node in error messages.
+func validateNodeAssetReferences(ctx context.Context, n models.WidgetNode, path string) error {
+	if err := validateAssetReference(ctx, n.Config); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	for i, child := range n.Children {
+		if err := validateNodeAssetReferences(ctx, child, fmt.Sprintf("%s.children[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newAssetKey returns a new random storage key with the given extension,
+// spread over subdirectories by its first two characters.
+func newAssetKey(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(b)
+	return name[:2] + "/" + name + ext, nil
+}
+
+// loadAssetVariants fills in the URLs, variants and srcset of assets.
+func loadAssetVariants(ctx context.Context, assets []models.Asset) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	ids := make([]string, len(assets))
+	for i, a := range assets {
+		ids[i] = a.ID
+	}
+	variants, err := repository.GetAssetVariants(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range assets {
+		setAssetVariants(&assets[i], variants[assets[i].ID])
+	}
+	return nil
+}
+
+// setAssetVariants fills in an asset's URL, its variants (narrowest first) with their
+// URLs, and a srcset listing the variants and the original.
+func setAssetVariants(asset *models.Asset, variants []models.AssetVariant) {
+	asset.URL = "/assets/" + asset.ID + "/content"
+	asset.Variants = make([]models.AssetVariant, len(variants))
+
+	candidates := make([]string, 0, len(variants)+1)
+	for i, v := range variants {
+		v.URL = fmt.Sprintf("/assets/%s/variants/%d", asset.ID, v.Width)
+		asset.Variants[i] = v
+		candidates = append(candidates, fmt.Sprintf("%s %dw", v.URL, v.Width))
+	}
+	candidates = append(candidates, fmt.Sprintf("%s %dw", asset.URL, asset.Width))
+	asset.SrcSet = strings.Join(candidates, ", ")
+}
+
+// expandWidgetAssets adds an "asset" entry to every widget config (component definitions
+// included) with an asset_id: the asset with its URL, dimensions, variants and srcset,
+// so clients can pick an image size without another request. Configs are copied, not
+// modified in place. Unknown asset IDs are left unexpanded.
+func expandWidgetAssets(ctx context.Context, widgets []models.Widget) error {
+	var ids []string
+	walkWidgetConfigs(widgets, func(_ string, config map[string]interface{}) {
+		if id, ok := config["asset_id"].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	})
+	if len(ids) == 0 {
+		return nil
+	}
+
+	assets, err := repository.GetAssetsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := loadAssetVariants(ctx, assets); err != nil {
+		return err
+	}
+	byID := make(map[string]*models.Asset, len(assets))
+	for i := range assets {
+		byID[assets[i].ID] = &assets[i]
+	}
+
+	rewriteWidgetConfigs(widgets, func(_ string, config map[string]interface{}) map[string]interface{} {
+		return withAsset(config, byID)
+	})
+	return nil
+}
+
+// withAsset returns config with the asset its asset_id names under "asset", or config
+// itself if there is nothing to expand.
+func withAsset(config map[string]interface{}, assets map[string]*models.Asset) map[string]interface{} {
+	id, _ := config["asset_id"].(string)
+	asset, ok := assets[id]
+	if !ok {
+		return config
+	}
+	expanded := make(map[string]interface{}, len(config)+1)
+	for k, v := range config {
+		expanded[k] = v
+	}
+	expanded["asset"] = asset
+	return expanded
+}
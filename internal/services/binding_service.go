@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// GetStoreVariables retrieves the store variables.
+func GetStoreVariables(ctx context.Context) (*models.StoreVariables, error) {
+	return repository.GetStoreVariables(ctx)
+}
+
+// UpdateStoreVariables validates and replaces the store variables.
+// Business Rules Enforced:
+//   - At most utils.MaxStoreVariables variables, named like theme tokens (lowercase
+//     letters, digits and underscores, starting with a letter)
+//   - Values are strings of at most utils.MaxStoreVariableValueLen characters, numbers
+//     or booleans
+//   - Variables still referenced by a widget or component cannot be removed
+//
+// Returns the stored variables or an error.
+func UpdateStoreVariables(ctx context.Context, variables map[string]interface{}) (*models.StoreVariables, error) {
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+	if len(variables) > utils.MaxStoreVariables {
+		return nil, fmt.Errorf("at most %d store variables are allowed", utils.MaxStoreVariables)
+	}
+	for _, name := range sortedKeys(variables) {
+		if !tokenNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("variables.%s: invalid variable name", name)
+		}
+		switch v := variables[name].(type) {
+		case string:
+			if utf8.RuneCountInString(v) > utils.MaxStoreVariableValueLen {
+				return nil, fmt.Errorf("variables.%s: cannot be longer than %d characters", name, utils.MaxStoreVariableValueLen)
+			}
+		case float64, bool:
+		default:
+			return nil, fmt.Errorf("variables.%s: must be a string, number or boolean", name)
+		}
+	}
+
+	// Every existing store reference must still resolve against the new variables
+	widgets, err := repository.GetWidgetsWithReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range widgets {
+		if err := validateStoreReferences(w.Config, variables, "widget "+w.ID); err != nil {
+			return nil, fmt.Errorf("variable change would break existing content: %v", err)
+		}
+	}
+	components, err := repository.GetAllComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range components {
+		if err := validateNodeStoreReferences(c.Root, variables, "component "+c.Name); err != nil {
+			return nil, fmt.Errorf("variable change would break existing content: %v", err)
+		}
+	}
+
+	return repository.UpdateStoreVariables(ctx, variables)
+}
+
+// bindingData holds the app data that binding references resolve against: the store
+// variables and the products they name. Only what the references use is loaded.
+type bindingData struct {
+	variables map[string]interface{}
+	products  map[string]*models.Product
+}
+
+// loadBindingData loads the store variables and products named by refs.
+func loadBindingData(ctx context.Context, refs []configReference) (*bindingData, error) {
+	data := &bindingData{}
+	needVariables := false
+	var productIDs []string
+	for _, ref := range refs {
+		switch ref.namespace() {
+		case "store":
+			needVariables = true
+		case "product":
+			if id, _, ok := productBinding(ref.Path); ok {
+				productIDs = append(productIDs, id)
+			}
+		}
+	}
+
+	if needVariables {
+		variables, err := repository.GetStoreVariables(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data.variables = variables.Variables
+	}
+	if len(productIDs) > 0 {
+		products, err := repository.GetProductsByIDs(ctx, productIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range products {
+			setProductImageURLs(p)
+		}
+		data.products = products
+	}
+	return data, nil
+}
+
+// lookup resolves store and product references.
+func (d *bindingData) lookup(path string) (interface{}, bool) {
+	namespace, name, _ := strings.Cut(path, ".")
+	switch namespace {
+	case "store":
+		value, ok := d.variables[name]
+		return value, ok
+	case "product":
+		id, field, ok := productBinding(path)
+		if !ok || d.products[id] == nil {
+			return nil, false
+		}
+		return productField(d.products[id], field)
+	}
+	return nil, false
+}
+
+// validate reports the first reference that names an unknown namespace or cannot be
+// resolved against d. Theme tokens are checked by validateThemeReferences instead.
+// path names the config's owner in error messages ("" for none).
+func (d *bindingData) validate(refs []configReference, path string) error {
+	for _, ref := range refs {
+		var err error
+		namespace := ref.namespace()
+		switch {
+		case utils.ThemeTokenNamespaces[namespace]:
+			continue
+		case namespace == "store":
+			if _, ok := d.lookup(ref.Path); !ok {
+				err = fmt.Errorf("config.%s references unknown store variable %s", ref.Field, ref.Path)
+			}
+		case namespace == "product":
+			id, field, ok := productBinding(ref.Path)
+			if !ok || !utils.ProductBindingFields[field] {
+				err = fmt.Errorf("config.%s: %s must be product.<id>.<field> with field one of %s",
+					ref.Field, ref.Path, strings.Join(sortedKeys(utils.ProductBindingFields), ", "))
+			} else if d.products[id] == nil {
+				err = fmt.Errorf("config.%s references unknown product %s", ref.Field, id)
+			}
+		default:
+			err = fmt.Errorf("config.%s references unknown namespace %s", ref.Field, namespace)
+		}
+		if err != nil {
+			if path != "" {
+				err = fmt.Errorf("%s: %v", path, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBindings checks that every non-theme reference in config resolves: its
+// namespace must be a theme or binding namespace (see utils.BindingNamespaces), store
+// variables must exist and product references must name an existing product and one of
+// utils.ProductBindingFields. path names the config's owner in error messages ("" for none).
+func validateBindings(ctx context.Context, config map[string]interface{}, path string) error {
+	refs := findReferences(config)
+	if len(refs) == 0 {
+		return nil
+	}
+	data, err := loadBindingData(ctx, refs)
+	if err != nil {
+		return err
+	}
+	return data.validate(refs, path)
+}
+
+// validateNodeBindings is validateBindings for a WidgetNode tree.
+func validateNodeBindings(ctx context.Context, n models.WidgetNode, path string) error {
+	if err := validateBindings(ctx, n.Config, path); err != nil {
+		return err
+	}
+	for i, child := range n.Children {
+		if err := validateNodeBindings(ctx, child, fmt.Sprintf("%s.children[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateStoreReferences checks that every store variable referenced in config exists
+// in variables. path names the config's owner in error messages.
+func validateStoreReferences(config map[string]interface{}, variables map[string]interface{}, path string) error {
+	data := &bindingData{variables: variables}
+	for _, ref := range findReferences(config) {
+		if ref.namespace() != "store" {
+			continue
+		}
+		if _, ok := data.lookup(ref.Path); !ok {
+			return fmt.Errorf("%s: config.%s references unknown store variable %s", path, ref.Field, ref.Path)
+		}
+	}
+	return nil
+}
+
+// validateNodeStoreReferences is validateStoreReferences for a WidgetNode tree.
+func validateNodeStoreReferences(n models.WidgetNode, variables map[string]interface{}, path string) error {
+	if err := validateStoreReferences(n.Config, variables, path); err != nil {
+		return err
+	}
+	for i, child := range n.Children {
+		if err := validateNodeStoreReferences(child, variables, fmt.Sprintf("%s.children[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// widgetTreeLookup loads everything the references in a widget tree (component
+// definitions included) resolve against, and returns a lookup for theme tokens in
+// colorScheme, store variables and products.
+func widgetTreeLookup(ctx context.Context, widgets []models.Widget, colorScheme string) (referenceLookup, error) {
+	var refs []configReference
+	walkWidgetConfigs(widgets, func(_ string, config map[string]interface{}) {
+		refs = append(refs, findReferences(config)...)
+	})
+
+	theme, err := repository.GetTheme(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := loadBindingData(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	themeTokens := themeLookup(theme, colorScheme)
+	return func(path string) (interface{}, bool) {
+		namespace, _, _ := strings.Cut(path, ".")
+		if utils.ThemeTokenNamespaces[namespace] {
+			return themeTokens(path)
+		}
+		return data.lookup(path)
+	}, nil
+}
+
+// productBinding splits a product reference path "product.<id>.<field>".
+func productBinding(path string) (id, field string, ok bool) {
+	_, rest, _ := strings.Cut(path, ".")
+	id, field, ok = strings.Cut(rest, ".")
+	return id, field, ok && id != "" && field != ""
+}
+
+// productField returns a product field by its binding name (see utils.ProductBindingFields).
+// image_url is "" for a product without images.
+func productField(p *models.Product, field string) (interface{}, bool) {
+	switch field {
+	case "sku":
+		return p.SKU, true
+	case "name":
+		return p.Name, true
+	case "description":
+		return p.Description, true
+	case "price_cents":
+		return p.PriceCents, true
+	case "currency":
+		return p.Currency, true
+	case "in_stock":
+		return p.InStock, true
+	case "image_url":
+		if len(p.Images) == 0 {
+			return "", true
+		}
+		return p.Images[0].URL, true
+	}
+	return nil, false
+}
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// skuPattern matches SKUs: letters, digits, ".", "_" and "-", starting with a letter or digit.
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// currencyPattern matches ISO 4217 currency codes.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// maxProductNameLength is the longest product or collection name.
+const maxProductNameLength = 200
+
+// GetProducts lists every product ordered by name.
+// Returns an empty list (never nil) when there are none.
+func GetProducts(ctx context.Context) ([]models.Product, error) {
+	products, err := repository.GetProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if products == nil {
+		products = []models.Product{}
+	}
+	for i := range products {
+		setProductImageURLs(&products[i])
+	}
+	return products, nil
+}
+
+// GetProduct retrieves a single product by ID.
+// Returns error if product not found.
+func GetProduct(ctx context.Context, id string) (*models.Product, error) {
+	product, err := repository.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "product not found")
+	}
+	setProductImageURLs(product)
+	return product, nil
+}
+
+// CreateProduct validates and saves a new product.
+// Business Rules Enforced:
+//   - SKU is required, unique, at most 64 letters, digits, ".", "_" or "-"
+//   - Name is required and at most 200 characters
+//   - price_cents is not negative; currency is an ISO 4217 code such as "USD"
+//   - At most utils.MaxProductImages images, each with exactly one of asset_id (an
+//     uploaded asset) or url (absolute http or https)
+//
+// Returns the created product with its UUID or an error.
+func CreateProduct(ctx context.Context, product models.Product) (*models.Product, error) {
+	if err := validateProduct(ctx, &product, ""); err != nil {
+		return nil, err
+	}
+	created, err := repository.CreateProduct(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	setProductImageURLs(created)
+	return created, nil
+}
+
+// UpdateProduct validates and replaces an existing product (see CreateProduct).
+// Returns the updated product or an error.
+func UpdateProduct(ctx context.Context, id string, product models.Product) (*models.Product, error) {
+	if _, err := repository.GetProductByID(ctx, id); err != nil {
+		return nil, notFound(ctx, "product not found")
+	}
+	product.ID = id
+	if err := validateProduct(ctx, &product, id); err != nil {
+		return nil, err
+	}
+	updated, err := repository.UpdateProduct(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	setProductImageURLs(updated)
+	return updated, nil
+}
+
+// DeleteProduct removes a product and takes it out of every collection.
+// A product listed in a product_grid's product_ids, or whose fields a config binds,
+// cannot be deleted; mark it out of stock instead, or remove the references first.
+func DeleteProduct(ctx context.Context, id string) error {
+	if _, err := repository.GetProductByID(ctx, id); err != nil {
+		return notFound(ctx, "product not found")
+	}
+	inUse, err := repository.ProductInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("product is in use")
+	}
+	return repository.DeleteProduct(ctx, id)
+}
+
+// GetCollections lists every collection ordered by name.
+// Returns an empty list (never nil) when there are none.
+func GetCollections(ctx context.Context) ([]models.Collection, error) {
+	collections, err := repository.GetCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if collections == nil {
+		collections = []models.Collection{}
+	}
+	return collections, nil
+}
+
+// GetCollection retrieves a single collection by ID.
+// Returns error if collection not found.
+func GetCollection(ctx context.Context, id string) (*models.Collection, error) {
+	collection, err := repository.GetCollectionByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "collection not found")
+	}
+	return collection, nil
+}
+
+// GetCollectionProducts returns a collection's products in display order.
+// Returns error if collection not found.
+func GetCollectionProducts(ctx context.Context, id string) ([]models.Product, error) {
+	collection, err := repository.GetCollectionByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "collection not found")
+	}
+	byID, err := repository.GetProductsByIDs(ctx, collection.ProductIDs)
+	if err != nil {
+		return nil, err
+	}
+	return orderedProducts(collection.ProductIDs, byID), nil
+}
+
+// CreateCollection validates and saves a new collection.
+// Business Rules Enforced:
+//   - Name is required, unique and at most 200 characters
+//   - At most utils.MaxCollectionProducts products, each existing and listed once
+//
+// Returns the created collection with its UUID or an error.
+func CreateCollection(ctx context.Context, collection models.Collection) (*models.Collection, error) {
+	if err := validateCollection(ctx, &collection, ""); err != nil {
+		return nil, err
+	}
+	return repository.CreateCollection(ctx, collection)
+}
+
+// UpdateCollection validates and replaces an existing collection, including its
+// product list (see CreateCollection).
+// Returns the updated collection or an error.
+func UpdateCollection(ctx context.Context, id string, collection models.Collection) (*models.Collection, error) {
+	if _, err := repository.GetCollectionByID(ctx, id); err != nil {
+		return nil, notFound(ctx, "collection not found")
+	}
+	collection.ID = id
+	if err := validateCollection(ctx, &collection, id); err != nil {
+		return nil, err
+	}
+	return repository.UpdateCollection(ctx, collection)
+}
+
+// DeleteCollection removes a collection; its products are kept.
+// A collection used by a product_grid cannot be deleted.
+func DeleteCollection(ctx context.Context, id string) error {
+	if _, err := repository.GetCollectionByID(ctx, id); err != nil {
+		return notFound(ctx, "collection not found")
+	}
+	inUse, err := repository.CollectionInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("collection is in use")
+	}
+	return repository.DeleteCollection(ctx, id)
+}
+
+// validateProduct checks a product (see CreateProduct) and normalizes its text fields.
+// id is the product being updated, or "" on create.
+func validateProduct(ctx context.Context, product *models.Product, id string) error {
+	product.SKU = strings.TrimSpace(product.SKU)
+	product.Name = strings.TrimSpace(product.Name)
+	if product.SKU == "" || product.Name == "" {
+		return errors.New("sku and name are required")
+	}
+	if !skuPattern.MatchString(product.SKU) {
+		return errors.New("sku must be at most 64 letters, digits, ., _ or -")
+	}
+	if len(product.Name) > maxProductNameLength {
+		return fmt.Errorf("name cannot be longer than %d characters", maxProductNameLength)
+	}
+	if product.PriceCents < 0 {
+		return errors.New("price_cents cannot be negative")
+	}
+	if !currencyPattern.MatchString(product.Currency) {
+		return errors.New("currency must be a three-letter ISO 4217 code such as \"USD\"")
+	}
+
+	if len(product.Images) > utils.MaxProductImages {
+		return fmt.Errorf("a product can have at most %d images", utils.MaxProductImages)
+	}
+	if product.Images == nil {
+		product.Images = []models.ProductImage{}
+	}
+	for i := range product.Images {
+		img := &product.Images[i]
+		path := fmt.Sprintf("images[%d]", i)
+		if (img.AssetID == nil) == (img.URL == "") {
+			return fmt.Errorf("%s: exactly one of asset_id or url is required", path)
+		}
+		if img.AssetID != nil {
+			if _, err := repository.GetAssetByID(ctx, *img.AssetID); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return fmt.Errorf("%s: asset_id must reference an existing asset", path)
+			}
+			continue
+		}
+		u, err := url.Parse(img.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s: url must be an absolute http or https URL", path)
+		}
+	}
+
+	exists, err := repository.ProductSKUExists(ctx, product.SKU, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("product sku already exists")
+	}
+	return nil
+}
+
+// validateCollection checks a collection (see CreateCollection) and normalizes its name.
+// id is the collection being updated, or "" on create.
+func validateCollection(ctx context.Context, collection *models.Collection, id string) error {
+	collection.Name = strings.TrimSpace(collection.Name)
+	if collection.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(collection.Name) > maxProductNameLength {
+		return fmt.Errorf("name cannot be longer than %d characters", maxProductNameLength)
+	}
+	if len(collection.ProductIDs) > utils.MaxCollectionProducts {
+		return fmt.Errorf("a collection can hold at most %d products", utils.MaxCollectionProducts)
+	}
+	if collection.ProductIDs == nil {
+		collection.ProductIDs = []string{}
+	}
+	if err := validateProductIDs(ctx, collection.ProductIDs, "product_ids"); err != nil {
+		return err
+	}
+
+	exists, err := repository.CollectionNameExists(ctx, collection.Name, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("collection name already exists")
+	}
+	return nil
+}
+
+// validateProductIDs checks that every ID is a UUID naming an existing product and none
+// is listed twice.
+// field names the list in error messages.
+func validateProductIDs(ctx context.Context, ids []string, field string) error {
+	seen := make(map[string]bool, len(ids))
+	for i, id := range ids {
+		if !utils.IsUUID(id) {
+			return fmt.Errorf("%s[%d] must be a UUID", field, i)
+		}
+		if seen[id] {
+			return fmt.Errorf("%s[%d] lists product %s twice", field, i, id)
+		}
+		seen[id] = true
+	}
+	products, err := repository.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if products[id] == nil {
+			return fmt.Errorf("%s[%d] must reference an existing product", field, i)
+		}
+	}
+	return nil
+}
+
+// validateProductGrid checks the catalog fields of a product_grid config:
+//   - At most one of collection_id (an existing collection's UUID) or product_ids (1 to
+//     utils.MaxProductGridIDs existing products, each listed once); null counts as unset,
+//     and a grid with neither lists no products
+//   - sort is one of utils.ProductGridSorts
+//   - limit is a whole number from 1 to utils.MaxProductGridLimit
+func validateProductGrid(ctx context.Context, config map[string]interface{}) error {
+	collectionID, hasCollection := config["collection_id"]
+	productIDs, hasProducts := config["product_ids"]
+	hasCollection = hasCollection && collectionID != nil
+	hasProducts = hasProducts && productIDs != nil
+	if hasCollection && hasProducts {
+		return errors.New("config can have collection_id or product_ids, not both")
+	}
+
+	if hasCollection {
+		id, ok := collectionID.(string)
+		if !ok || !utils.IsUUID(id) {
+			return errors.New("config.collection_id must be a collection UUID")
+		}
+		if _, err := repository.GetCollectionByID(ctx, id); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return errors.New("config.collection_id must reference an existing collection")
+		}
+	}
+
+	if hasProducts {
+		ids, ok := stringList(productIDs)
+		if !ok || len(ids) == 0 || len(ids) > utils.MaxProductGridIDs {
+			return fmt.Errorf("config.product_ids must be a list of 1 to %d product IDs", utils.MaxProductGridIDs)
+		}
+		if err := validateProductIDs(ctx, ids, "config.product_ids"); err != nil {
+			return err
+		}
+	}
+
+	if sort, ok := config["sort"]; ok && sort != nil {
+		if s, ok := sort.(string); !ok || !utils.ProductGridSorts[s] {
+			return fmt.Errorf("config.sort must be one of %s", strings.Join(sortedKeys(utils.ProductGridSorts), ", "))
+		}
+	}
+	if limit, ok := config["limit"]; ok && limit != nil {
+		if n, ok := limit.(float64); !ok || n != float64(int(n)) || n < 1 || n > utils.MaxProductGridLimit {
+			return fmt.Errorf("config.limit must be a whole number from 1 to %d", utils.MaxProductGridLimit)
+		}
+	}
+	return nil
+}
+
+// validateNodeProductGrids is validateProductGrid for the product_grid nodes of a
+// WidgetNode tree. path names the node in error messages.
+func validateNodeProductGrids(ctx context.Context, n models.WidgetNode, path string) error {
+	if n.Type == utils.ProductGridWidgetType {
+		if err := validateProductGrid(ctx, n.Config); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	for i, child := range n.Children {
+		if err := validateNodeProductGrids(ctx, child, fmt.Sprintf("%s.children[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandProductGrids adds the listed products, sorted and limited, under "products" to
+// every product_grid config in a widget tree (component definitions included).
+// Configs are copied, not modified in place. Products and collections deleted since the
+// grid was saved are skipped.
+func expandProductGrids(ctx context.Context, widgets []models.Widget) error {
+	var collectionIDs, productIDs []string
+	walkWidgetConfigs(widgets, func(widgetType string, config map[string]interface{}) {
+		if widgetType != utils.ProductGridWidgetType {
+			return
+		}
+		if id, ok := config["collection_id"].(string); ok {
+			collectionIDs = append(collectionIDs, id)
+		}
+		if ids, ok := stringList(config["product_ids"]); ok {
+			productIDs = append(productIDs, ids...)
+		}
+	})
+	if len(collectionIDs) == 0 && len(productIDs) == 0 {
+		return nil
+	}
+
+	collections, err := repository.GetCollectionsByIDs(ctx, collectionIDs)
+	if err != nil {
+		return err
+	}
+	for _, c := range collections {
+		productIDs = append(productIDs, c.ProductIDs...)
+	}
+	products, err := repository.GetProductsByIDs(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	rewriteWidgetConfigs(widgets, func(widgetType string, config map[string]interface{}) map[string]interface{} {
+		if widgetType != utils.ProductGridWidgetType {
+			return config
+		}
+		var ids []string
+		if id, ok := config["collection_id"].(string); ok && collections[id] != nil {
+			ids = collections[id].ProductIDs
+		} else if list, ok := stringList(config["product_ids"]); ok {
+			ids = list
+		} else {
+			return config
+		}
+
+		expanded := make(map[string]interface{}, len(config)+1)
+		for k, v := range config {
+			expanded[k] = v
+		}
+		expanded["products"] = gridProducts(config, orderedProducts(ids, products))
+		return expanded
+	})
+	return nil
+}
+
+// gridProducts sorts and limits a grid's products as its config asks.
+func gridProducts(config map[string]interface{}, products []models.Product) []models.Product {
+	sort, _ := config["sort"].(string)
+	switch sort {
+	case "price_asc":
+		slices.SortStableFunc(products, func(a, b models.Product) int { return cmp.Compare(a.PriceCents, b.PriceCents) })
+	case "price_desc":
+		slices.SortStableFunc(products, func(a, b models.Product) int { return cmp.Compare(b.PriceCents, a.PriceCents) })
+	case "name":
+		slices.SortStableFunc(products, func(a, b models.Product) int { return strings.Compare(a.Name, b.Name) })
+	case "newest":
+		slices.SortStableFunc(products, func(a, b models.Product) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	}
+
+	limit := utils.DefaultProductGridLimit
+	if n, ok := config["limit"].(float64); ok && n >= 1 {
+		limit = min(int(n), utils.MaxProductGridLimit)
+	}
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products
+}
+
+// orderedProducts returns the products in ids order, skipping IDs with no product.
+// Never returns nil.
+func orderedProducts(ids []string, products map[string]*models.Product) []models.Product {
+	result := make([]models.Product, 0, len(ids))
+	for _, id := range ids {
+		if p := products[id]; p != nil {
+			setProductImageURLs(p)
+			result = append(result, *p)
+		}
+	}
+	return result
+}
+
+// setProductImageURLs fills in the URL of asset images with the asset's file path.
+func setProductImageURLs(product *models.Product) {
+	for i, img := range product.Images {
+		if img.AssetID != nil {
+			product.Images[i].URL = "/assets/" + *img.AssetID + "/content"
+		}
+	}
+}
+
+// stringList converts a decoded JSON array of strings to a []string.
+// Returns false if value is not an array or holds anything but strings.
+func stringList(value interface{}) ([]string, bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	list := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		list[i] = s
+	}
+	return list, true
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+)
+
+// GetComponents retrieves every component in the library, ordered by name.
+// Returns an empty list (never nil) when there are none.
+func GetComponents(ctx context.Context) ([]models.Component, error) {
+	components, err := repository.GetAllComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if components == nil {
+		components = []models.Component{}
+	}
+	return components, nil
+}
+
+// GetComponent retrieves a single component by ID.
+// Returns error if component not found.
+func GetComponent(ctx context.Context, id string) (*models.Component, error) {
+	component, err := repository.GetComponentByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "component not found")
+	}
+	return component, nil
+}
+
+// CreateComponent validates and creates a reusable component.
+// Business Rules Enforced:
+//   - Name is required and must be unique
+//   - Exactly one of root or sourceWidgetID must be given; a source widget is copied
+//     together with its children
+//   - The definition must be a valid widget tree without component references
+//   - Theme tokens, store variables and products referenced in configs must exist, and
+//     asset_id values must reference uploaded assets
+//   - product_grid nodes must reference existing collections or products
+//
+// Returns the created component with its UUID or an error.
+func CreateComponent(ctx context.Context, component models.Component, root *models.WidgetNode, sourceWidgetID *string) (*models.Component, error) {
+	if (root == nil) == (sourceWidgetID == nil) {
+		return nil, errors.New("exactly one of root or source_widget_id is required")
+	}
+
+	if sourceWidgetID != nil {
+		widget, err := repository.GetWidgetByID(ctx, *sourceWidgetID)
+		if err != nil {
+			return nil, notFound(ctx, "source widget not found")
+		}
+		ix, err := loadWidgetIndex(ctx, widget.PageID)
+		if err != nil {
+			return nil, err
+		}
+		node := ix.node(widget.ID)
+		root = &node
+	}
+	component.Root = *root
+
+	if err := validateComponent(ctx, component, ""); err != nil {
+		return nil, err
+	}
+
+	return repository.CreateComponent(ctx, component)
+}
+
+// UpdateComponent replaces a component's name, description and definition.
+// The change is visible on every page referencing the component.
+// Business Rules Enforced:
+//   - Component must exist
+//   - Name is required and must be unique
+//   - The definition must be a valid widget tree without component references
+//   - Theme tokens, store variables and products referenced in configs must exist, and
+//     asset_id values must reference uploaded assets
+//   - product_grid nodes must reference existing collections or products
+//   - Every page referencing the component must still accept the new definition
+//     (container child types and nesting depth)
+//
+// Returns the updated component or an error.
+func UpdateComponent(ctx context.Context, id string, component models.Component) (*models.Component, error) {
+	if _, err := repository.GetComponentByID(ctx, id); err != nil {
+		return nil, notFound(ctx, "component not found")
+	}
+
+	if err := validateComponent(ctx, component, id); err != nil {
+		return nil, err
+	}
+
+	// Edits propagate to every usage, so each must still be a valid placement
+	usages, err := repository.GetComponentUsages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[string]*widgetIndex)
+	for _, usage := range usages {
+		ix, ok := indexes[usage.PageID]
+		if !ok {
+			ix, err = loadWidgetIndex(ctx, usage.PageID)
+			if err != nil {
+				return nil, err
+			}
+			indexes[usage.PageID] = ix
+		}
+		widget := ix.byID[usage.WidgetID]
+		if widget == nil {
+			continue
+		}
+		if err := ix.validatePlacement(widget.ParentID, component.Root.Type, nodeHeight(component.Root)); err != nil {
+			return nil, fmt.Errorf("change would break usage on page %s: %v", usage.PageRoute, err)
+		}
+	}
+
+	component.ID = id
+	return repository.UpdateComponent(ctx, component)
+}
+
+// DeleteComponent removes a component from the library.
+// Business Rule: Cannot delete a component that is still referenced by a page;
+// detach or delete the referencing widgets first.
+// Returns error if component not found or still in use.
+func DeleteComponent(ctx context.Context, id string) error {
+	if _, err := repository.GetComponentByID(ctx, id); err != nil {
+		return notFound(ctx, "component not found")
+	}
+
+	usages, err := repository.GetComponentUsages(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(usages) > 0 {
+		return errors.New("component is in use")
+	}
+
+	return repository.DeleteComponent(ctx, id)
+}
+
+// GetComponentUsages lists every widget referencing a component and the page it is on.
+// Returns error if component not found.
+func GetComponentUsages(ctx context.Context, id string) ([]models.ComponentUsage, error) {
+	if _, err := repository.GetComponentByID(ctx, id); err != nil {
+		return nil, notFound(ctx, "component not found")
+	}
+
+	usages, err := repository.GetComponentUsages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if usages == nil {
+		usages = []models.ComponentUsage{}
+	}
+	return usages, nil
+}
+
+// validateComponent checks the name, definition, and theme token, binding, asset and
+// catalog references of a component.
+// excludeID is the component being updated, so it may keep its own name.
+func validateComponent(ctx context.Context, component models.Component, excludeID string) error {
+	if component.Name == "" {
+		return errors.New("name is required")
+	}
+
+	exists, err := repository.ComponentNameExists(ctx, component.Name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("component name already exists")
+	}
+
+	if err := validateNode(component.Root, "root", nil); err != nil {
+		return err
+	}
+
+	theme, err := repository.GetTheme(ctx)
+	if err != nil {
+		return err
+	}
+	if err := validateNodeThemeReferences(component.Root, theme, "root"); err != nil {
+		return err
+	}
+	if err := validateNodeBindings(ctx, component.Root, "root"); err != nil {
+		return err
+	}
+	if err := validateNodeAssetReferences(ctx, component.Root, "root"); err != nil {
+		return err
+	}
+	return validateNodeProductGrids(ctx, component.Root, "root")
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/pageroute"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// Apps listed in the generated deep link files, set from configuration at startup.
+var (
+	// AppleAppIDs are the iOS app IDs ("TEAMID.bundle.id") in apple-app-site-association
+	AppleAppIDs []string
+	// AndroidPackage is the Android app's package name in assetlinks.json
+	AndroidPackage string
+	// AndroidFingerprints are the SHA-256 fingerprints of the Android app's signing certificates
+	AndroidFingerprints []string
+)
+
+// GetRouteTable lists the routes of the pages app links can open and the redirects to
+// them, with each route converted to the apple-app-site-association and Android intent
+// filter path syntax. It is built from the current pages and redirects on every call.
+// Drafts are left out unless scheduled to publish; visibility windows are ignored, as
+// apps and link caches keep the table long after it is fetched.
+func GetRouteTable(ctx context.Context) (*models.RouteTable, error) {
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	redirects, err := repository.GetRedirects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &models.RouteTable{Routes: []models.RouteTableEntry{}, Redirects: []models.RouteTableRedirect{}}
+	pagesByID := make(map[string]*models.Page, len(pages))
+	for i, p := range pages {
+		pagesByID[p.ID] = &pages[i]
+		if p.Status == utils.PageStatusDraft && p.PublishAt == nil {
+			continue
+		}
+		ios, android, params := linkPatterns(p.Route)
+		table.Routes = append(table.Routes, models.RouteTableEntry{
+			PageID:         p.ID,
+			PageName:       p.Name,
+			Route:          p.Route,
+			IsHome:         p.IsHome,
+			Params:         params,
+			IOSPattern:     ios,
+			AndroidPattern: android,
+		})
+	}
+	sort.Slice(table.Routes, func(i, j int) bool { return table.Routes[i].Route < table.Routes[j].Route })
+
+	for _, r := range redirects {
+		to := ""
+		if r.PageID != nil {
+			page := pagesByID[*r.PageID]
+			if page == nil || (page.Status == utils.PageStatusDraft && page.PublishAt == nil) {
+				continue
+			}
+			to = page.Route
+		} else if r.ToRoute != nil {
+			to = *r.ToRoute
+		}
+		ios, android, _ := linkPatterns(r.FromRoute)
+		table.Redirects = append(table.Redirects, models.RouteTableRedirect{
+			RedirectID:     r.ID,
+			FromRoute:      r.FromRoute,
+			ToRoute:        to,
+			PageID:         r.PageID,
+			IOSPattern:     ios,
+			AndroidPattern: android,
+		})
+	}
+	return table, nil
+}
+
+// GetAppleAppSiteAssociation generates the apple-app-site-association file letting the
+// configured iOS apps open the page routes and redirects in the route table (see
+// GetRouteTable). Parameters become "*", so the app resolves opened paths with
+// GET /public/resolve.
+// Returns error if no iOS apps are configured.
+func GetAppleAppSiteAssociation(ctx context.Context) (*models.AppleAppSiteAssociation, error) {
+	if len(AppleAppIDs) == 0 {
+		return nil, errors.New("apple app links are not configured")
+	}
+	table, err := GetRouteTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	components := []models.AppleAppLinkComponent{}
+	seen := map[string]bool{}
+	add := func(path, comment string) {
+		if !seen[path] {
+			seen[path] = true
+			components = append(components, models.AppleAppLinkComponent{Path: path, Comment: comment})
+		}
+	}
+	for _, r := range table.Routes {
+		add(r.IOSPattern, r.PageName)
+	}
+	for _, r := range table.Redirects {
+		add(r.IOSPattern, "Redirects to "+r.ToRoute)
+	}
+
+	return &models.AppleAppSiteAssociation{AppLinks: models.AppleAppLinks{
+		Details: []models.AppleAppLinkDetail{{AppIDs: AppleAppIDs, Components: components}},
+	}}, nil
+}
+
+// GetAssetLinks generates the assetlinks.json statements letting the configured Android
+// app handle app links. Android reads the paths from the app's intent filters; the route
+// table's android_pattern values are meant for them.
+// Returns error if no Android app is configured.
+func GetAssetLinks() ([]models.AssetLink, error) {
+	if AndroidPackage == "" {
+		return nil, errors.New("android app links are not configured")
+	}
+	return []models.AssetLink{{
+		Relation: []string{"delegate_permission/common.handle_all_urls"},
+		Target: models.AssetLinkTarget{
+			Namespace:    "android_app",
+			PackageName:  AndroidPackage,
+			Fingerprints: AndroidFingerprints,
+		},
+	}}, nil
+}
+
+// linkPatterns converts a route to apple-app-site-association and Android pathPattern
+// syntax and returns its parameter names.
+func linkPatterns(route string) (string, string, []string) {
+	pattern, err := pageroute.Parse(route)
+	if err != nil {
+		// Routes saved before route syntax was checked have no parameters
+		return route, route, []string{}
+	}
+	params := pattern.Params()
+	if params == nil {
+		params = []string{}
+	}
+	return pattern.Wildcard("*"), pattern.Wildcard(".*"), params
+}
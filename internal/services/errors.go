@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"errors"
+)
+
+// notFound returns a "<resource> not found" error for a failed lookup.
+// If the lookup actually failed because the request was cancelled or timed out,
+// the context error is returned instead so handlers can report 499/504 rather than 404.
+func notFound(ctx context.Context, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// variantKeyPattern matches variant keys: lower-case letters, digits, "_" and "-".
+var variantKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// GetExperiments lists every experiment, newest first.
+// Returns an empty list (never nil) when there are none.
+func GetExperiments(ctx context.Context) ([]models.Experiment, error) {
+	experiments, err := repository.GetExperiments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if experiments == nil {
+		experiments = []models.Experiment{}
+	}
+	return experiments, nil
+}
+
+// GetExperiment retrieves a single experiment by ID.
+// Returns error if experiment not found.
+func GetExperiment(ctx context.Context, id string) (*models.Experiment, error) {
+	experiment, err := repository.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "experiment not found")
+	}
+	return experiment, nil
+}
+
+// CreateExperiment validates and saves a new experiment.
+// Business Rules Enforced:
+//   - Name is required and unique; the page must exist
+//   - Status is draft (default), running or stopped; a page has at most one running experiment
+//   - 2 to utils.MaxExperimentVariants variants with unique keys and weights from 0 to utils.MaxVariantWeight
+//     adding up to more than 0
+//   - A variant has an alternate page_id or widget overrides, not both (neither means the
+//     page as it is); overrides name widgets on the experiment page, hidden overrides
+//     have no config, and an override's references, asset_id and product_grid fields are
+//     checked as on widgets
+//
+// Returns the created experiment with its UUID or an error.
+func CreateExperiment(ctx context.Context, experiment models.Experiment) (*models.Experiment, error) {
+	if experiment.Status == "" {
+		experiment.Status = utils.ExperimentStatusDraft
+	}
+	if err := validateExperiment(ctx, &experiment, ""); err != nil {
+		return nil, err
+	}
+	return repository.CreateExperiment(ctx, experiment)
+}
+
+// UpdateExperiment validates and replaces an existing experiment.
+// Business Rules Enforced: as CreateExperiment, and additionally
+//   - An omitted status keeps the current status
+//   - A started experiment cannot return to draft
+//   - A running experiment's page and variants cannot change; stop it first so
+//     users are not moved between variants mid-test
+//
+// Returns the updated experiment or an error.
+func UpdateExperiment(ctx context.Context, id string, experiment models.Experiment) (*models.Experiment, error) {
+	existing, err := repository.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "experiment not found")
+	}
+
+	if experiment.Status == "" {
+		experiment.Status = existing.Status
+	}
+	if experiment.Status == utils.ExperimentStatusDraft && existing.Status != utils.ExperimentStatusDraft {
+		return nil, errors.New("a started experiment cannot return to draft")
+	}
+
+	experiment.ID = id
+	if err := validateExperiment(ctx, &experiment, id); err != nil {
+		return nil, err
+	}
+
+	if existing.Status == utils.ExperimentStatusRunning && experiment.Status == utils.ExperimentStatusRunning &&
+		(experiment.PageID != existing.PageID || !sameVariants(experiment.Variants, existing.Variants)) {
+		return nil, errors.New("stop the experiment before changing its page or variants")
+	}
+
+	return repository.UpdateExperiment(ctx, experiment)
+}
+
+// DeleteExperiment removes an experiment and its exposure log.
+// Running experiments must be stopped first.
+func DeleteExperiment(ctx context.Context, id string) error {
+	experiment, err := repository.GetExperimentByID(ctx, id)
+	if err != nil {
+		return notFound(ctx, "experiment not found")
+	}
+	if experiment.Status == utils.ExperimentStatusRunning {
+		return errors.New("cannot delete a running experiment")
+	}
+	return repository.DeleteExperiment(ctx, id)
+}
+
+// GetExperimentExposures returns an experiment's exposure log: the first time each
+// user was shown a variant.
+func GetExperimentExposures(ctx context.Context, id string) ([]models.ExperimentExposure, error) {
+	if _, err := repository.GetExperimentByID(ctx, id); err != nil {
+		return nil, notFound(ctx, "experiment not found")
+	}
+	exposures, err := repository.GetExposures(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if exposures == nil {
+		exposures = []models.ExperimentExposure{}
+	}
+	return exposures, nil
+}
+
+// validateExperiment checks an experiment (see CreateExperiment) and normalizes its
+// name and variants. id is the experiment being updated, or "" on create.
+func validateExperiment(ctx context.Context, experiment *models.Experiment, id string) error {
+	experiment.Name = strings.TrimSpace(experiment.Name)
+	if experiment.Name == "" || experiment.PageID == "" {
+		return errors.New("name and page_id are required")
+	}
+
+	switch experiment.Status {
+	case utils.ExperimentStatusDraft, utils.ExperimentStatusRunning, utils.ExperimentStatusStopped:
+	default:
+		return errors.New("status must be draft, running or stopped")
+	}
+
+	if _, err := repository.GetPageByID(ctx, experiment.PageID); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errors.New("page_id must reference an existing page")
+	}
+
+	if n := len(experiment.Variants); n < 2 || n > utils.MaxExperimentVariants {
+		return fmt.Errorf("an experiment needs 2 to %d variants", utils.MaxExperimentVariants)
+	}
+
+	ix, err := loadWidgetIndex(ctx, experiment.PageID)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]bool{}
+	total := 0
+	for i := range experiment.Variants {
+		v := &experiment.Variants[i]
+		path := fmt.Sprintf("variants[%d]", i)
+
+		if !variantKeyPattern.MatchString(v.Key) {
+			return fmt.Errorf("%s.key must be 1-32 lower-case letters, digits, _ or -", path)
+		}
+		if keys[v.Key] {
+			return fmt.Errorf("%s.key %q is used by another variant", path, v.Key)
+		}
+		keys[v.Key] = true
+
+		if v.Weight < 0 || v.Weight > utils.MaxVariantWeight {
+			return fmt.Errorf("%s.weight must be between 0 and %d", path, utils.MaxVariantWeight)
+		}
+		total += v.Weight
+
+		if v.PageID != nil && len(v.Overrides) > 0 {
+			return fmt.Errorf("%s has both page_id and overrides; use one", path)
+		}
+		if v.PageID != nil {
+			if *v.PageID == experiment.PageID {
+				return fmt.Errorf("%s.page_id must be a different page from the experiment page", path)
+			}
+			if _, err := repository.GetPageByID(ctx, *v.PageID); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return fmt.Errorf("%s.page_id must reference an existing page", path)
+			}
+		}
+
+		if v.Overrides == nil {
+			v.Overrides = []models.WidgetOverride{}
+		}
+		overridden := map[string]bool{}
+		for j, o := range v.Overrides {
+			opath := fmt.Sprintf("%s.overrides[%d]", path, j)
+			if ix.byID[o.WidgetID] == nil {
+				return fmt.Errorf("%s.widget_id must be a widget on the experiment page", opath)
+			}
+			if overridden[o.WidgetID] {
+				return fmt.Errorf("%s overrides widget %s twice", path, o.WidgetID)
+			}
+			overridden[o.WidgetID] = true
+
+			if o.Hidden && len(o.Config) > 0 {
+				return fmt.Errorf("%s hides the widget, so it cannot also override config", opath)
+			}
+			if !o.Hidden && len(o.Config) == 0 {
+				return fmt.Errorf("%s needs config or hidden", opath)
+			}
+			if err := validateWidgetReferences(ctx, o.Config); err != nil {
+				return fmt.Errorf("%s: %v", opath, err)
+			}
+			if err := validateAssetReference(ctx, o.Config); err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				return fmt.Errorf("%s: %v", opath, err)
+			}
+			if ix.byID[o.WidgetID].Type == utils.ProductGridWidgetType {
+				if err := validateProductGrid(ctx, o.Config); err != nil {
+					if ctx.Err() != nil {
+						return err
+					}
+					return fmt.Errorf("%s: %v", opath, err)
+				}
+			}
+		}
+	}
+	if total == 0 {
+		return errors.New("variant weights must add up to more than 0")
+	}
+
+	exists, err := repository.ExperimentNameExists(ctx, experiment.Name, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("experiment name already exists")
+	}
+
+	if experiment.Status == utils.ExperimentStatusRunning {
+		running, err := repository.GetRunningExperimentForPage(ctx, experiment.PageID)
+		if err != nil {
+			return err
+		}
+		if running != nil && running.ID != id {
+			return errors.New("page already has a running experiment")
+		}
+	}
+	return nil
+}
+
+// sameVariants reports whether two variant lists are identical once stored.
+func sameVariants(a, b []models.ExperimentVariant) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// pageExperiment assigns a user a variant of the running experiment on a page.
+// Returns nil values if the page has no running experiment.
+func pageExperiment(ctx context.Context, pageID, userID string) (*models.ExperimentAssignment, *models.ExperimentVariant, error) {
+	experiment, err := repository.GetRunningExperimentForPage(ctx, pageID)
+	if err != nil || experiment == nil {
+		return nil, nil, err
+	}
+	variant := assignVariant(*experiment, userID)
+	if variant == nil {
+		return nil, nil, nil
+	}
+	return &models.ExperimentAssignment{ExperimentID: experiment.ID, PageID: pageID, Variant: variant.Key}, variant, nil
+}
+
+// assignVariant buckets a user into one of an experiment's variants.
+// The user ID is hashed together with the experiment ID, so a user always gets
+// the same variant of an experiment while assignments across experiments are
+// independent. Variants get a share of users proportional to their weight.
+func assignVariant(experiment models.Experiment, userID string) *models.ExperimentVariant {
+	total := 0
+	for _, v := range experiment.Variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(experiment.ID + "/" + userID))
+	bucket := int(h.Sum64() % uint64(total))
+
+	for i := range experiment.Variants {
+		bucket -= experiment.Variants[i].Weight
+		if bucket < 0 {
+			return &experiment.Variants[i]
+		}
+	}
+	return nil
+}
+
+// applyOverrides applies a variant's widget overrides to a widget tree: override
+// config fields replace the widget's own, and hidden widgets are dropped with their children.
+func applyOverrides(widgets []models.Widget, overrides map[string]models.WidgetOverride) []models.Widget {
+	result := make([]models.Widget, 0, len(widgets))
+	for _, w := range widgets {
+		o, ok := overrides[w.ID]
+		if ok && o.Hidden {
+			continue
+		}
+		if ok {
+			config := make(map[string]interface{}, len(w.Config)+len(o.Config))
+			for k, v := range w.Config {
+				config[k] = v
+			}
+			for k, v := range o.Config {
+				config[k] = v
+			}
+			w.Config = config
+		}
+		if w.Children != nil {
+			w.Children = applyOverrides(w.Children, overrides)
+		}
+		result = append(result, w)
+	}
+	return result
+}
+
+// experimentAssignments returns the variants a user is assigned in every running
+// experiment on a page in visible, for the public manifest.
+func experimentAssignments(ctx context.Context, userID string, visible map[string]bool) ([]models.ExperimentAssignment, error) {
+	experiments, err := repository.GetRunningExperiments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := []models.ExperimentAssignment{}
+	for _, e := range experiments {
+		if !visible[e.PageID] {
+			continue
+		}
+		if v := assignVariant(e, userID); v != nil {
+			assignments = append(assignments, models.ExperimentAssignment{ExperimentID: e.ID, PageID: e.PageID, Variant: v.Key})
+		}
+	}
+	return assignments, nil
+}
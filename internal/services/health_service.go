@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"appdrop-api/internal/db"
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+)
+
+// shuttingDown is set once graceful shutdown begins so readiness fails
+// and load balancers stop routing new traffic to this instance.
+var shuttingDown atomic.Bool
+
+// BeginShutdown marks the server as draining. Readiness checks fail from then on.
+func BeginShutdown() {
+	shuttingDown.Store(true)
+}
+
+// CheckLiveness reports that the process is up and able to serve requests.
+// It deliberately touches no dependencies so a database outage doesn't get the process restarted.
+func CheckLiveness() models.HealthReport {
+	return models.HealthReport{Status: "pass", Checks: []models.HealthCheck{}}
+}
+
+// CheckReadiness runs every dependency check, each bounded by timeout.
+// Checks performed:
+//   - shutdown: the server is not draining
+//   - database: a ping succeeds
+//   - migrations: schema_migrations is at db.SchemaVersion
+//   - pool: at least one connection can still be acquired
+//
+// The report status is "fail" if any check fails.
+func CheckReadiness(ctx context.Context, timeout time.Duration) models.HealthReport {
+	checks := []models.HealthCheck{
+		runCheck("shutdown", func() error {
+			if shuttingDown.Load() {
+				return fmt.Errorf("server is shutting down")
+			}
+			return nil
+		}),
+		runCheck("database", func() error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return repository.PingDB(ctx)
+		}),
+		runCheck("migrations", func() error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			version, err := repository.GetSchemaVersion(ctx)
+			if err != nil {
+				return err
+			}
+			if version != db.SchemaVersion {
+				return fmt.Errorf("schema version %d, expected %d", version, db.SchemaVersion)
+			}
+			return nil
+		}),
+		runCheck("pool", func() error {
+			stat := db.Pool.Stat()
+			if stat.AcquiredConns() >= stat.MaxConns() {
+				return fmt.Errorf("all %d connections in use", stat.MaxConns())
+			}
+			return nil
+		}),
+	}
+
+	report := models.HealthReport{Status: "pass", Checks: checks}
+	for _, c := range checks {
+		if c.Status != "pass" {
+			report.Status = "fail"
+		}
+	}
+	return report
+}
+
+// runCheck times fn and converts its result into a HealthCheck.
+func runCheck(name string, fn func() error) models.HealthCheck {
+	start := time.Now()
+	err := fn()
+	check := models.HealthCheck{
+		Name:      name,
+		Status:    "pass",
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = "fail"
+		check.Message = err.Error()
+	}
+	return check
+}
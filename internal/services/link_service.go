@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/pageroute"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// BlockLinkedPageDeletes makes DeletePage refuse to delete a page that widgets or
+// components link to. Set from configuration at startup.
+var BlockLinkedPageDeletes bool
+
+// PageLinkedError is returned by DeletePage when widgets or components link to the page.
+type PageLinkedError struct {
+	// Links lists the widgets and components linking to the page
+	Links []models.PageLink
+}
+
+func (e *PageLinkedError) Error() string {
+	return "page is linked from other content"
+}
+
+// configLink is an app path found in a widget config.
+type configLink struct {
+	// field is the config key holding the link, e.g. "link"
+	field string
+	// path is the app path linked to
+	path string
+}
+
+// findLinks returns the app paths in a config's utils.LinkConfigKeys fields.
+// External URLs and values with {{...}} references are skipped.
+func findLinks(config map[string]interface{}) []configLink {
+	var links []configLink
+	for _, key := range utils.LinkConfigKeys {
+		value, ok := config[key].(string)
+		if !ok || !strings.HasPrefix(value, "/") || strings.HasPrefix(value, "//") || strings.Contains(value, "{{") {
+			continue
+		}
+		links = append(links, configLink{field: key, path: value})
+	}
+	return links
+}
+
+// linkData holds the pages and redirects links are resolved against.
+type linkData struct {
+	pages     []models.Page
+	redirects []models.Redirect
+}
+
+// loadLinkData loads every page and redirect.
+func loadLinkData(ctx context.Context) (*linkData, error) {
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	redirects, err := repository.GetRedirects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &linkData{pages: pages, redirects: redirects}, nil
+}
+
+// withPage returns a copy of d with page as it is about to be saved: it replaces the stored
+// page with its ID, or is added if it has no ID yet. If the page's route changes from
+// oldRoute ("" if unchanged), the redirects change as UpdatePage changes them (see
+// movedRedirects).
+func (d *linkData) withPage(page models.Page, oldRoute string) *linkData {
+	c := &linkData{pages: d.pagesWith(page), redirects: make([]models.Redirect, 0, len(d.redirects)+1)}
+	var deleted map[string]bool
+	var automatic *models.Redirect
+	if oldRoute != "" && oldRoute != page.Route {
+		deleted, automatic = d.movedRedirects(page, oldRoute)
+	}
+	for _, r := range d.redirects {
+		if !deleted[r.ID] {
+			c.redirects = append(c.redirects, r)
+		}
+	}
+	if automatic != nil {
+		c.redirects = append(c.redirects, *automatic)
+	}
+	return c
+}
+
+// pagesWith returns d's pages with page replacing the stored page with its ID, or added
+// if it has no ID yet.
+func (d *linkData) pagesWith(page models.Page) []models.Page {
+	pages := make([]models.Page, 0, len(d.pages)+1)
+	for _, p := range d.pages {
+		if page.ID == "" || p.ID != page.ID {
+			pages = append(pages, p)
+		}
+	}
+	return append(pages, page)
+}
+
+// movedRedirects returns how the redirects change when page moves from oldRoute: the IDs
+// of the redirects to delete, and the automatic redirect from oldRoute to add, if any.
+// Redirects the new route shadows, whose from_route only matches paths the page's route
+// matches, are deleted. oldRoute redirects to the page, replacing the redirects from the
+// same paths, unless the new route has parameters oldRoute cannot fill in (see
+// routeChangeRedirects) or the redirect breaks a rule of CreateRedirect, e.g. another
+// page's route matches every path oldRoute does.
+func (d *linkData) movedRedirects(page models.Page, oldRoute string) (map[string]bool, *models.Redirect) {
+	deleted := map[string]bool{}
+	var kept []models.Redirect
+	for _, r := range d.redirects {
+		if routeWithin(r.FromRoute, page.Route) {
+			deleted[r.ID] = true
+		} else {
+			kept = append(kept, r)
+		}
+	}
+	if !routeChangeRedirects(oldRoute, page.Route) {
+		return deleted, nil
+	}
+
+	var replaced []string
+	var others []models.Redirect
+	for _, r := range kept {
+		if routeWithin(r.FromRoute, oldRoute) && routeWithin(oldRoute, r.FromRoute) {
+			replaced = append(replaced, r.ID)
+		} else {
+			others = append(others, r)
+		}
+	}
+	pageID := page.ID
+	automatic := &models.Redirect{FromRoute: oldRoute, PageID: &pageID, Automatic: true}
+	pages := d.pagesWith(page)
+	if _, err := pageroute.Parse(oldRoute); err == nil {
+		if checkRedirect(*automatic, pages, others) != nil {
+			return deleted, nil
+		}
+	} else {
+		// A route saved before route syntax was checked only matches itself
+		for _, p := range pages {
+			if p.Route == oldRoute {
+				return deleted, nil
+			}
+		}
+	}
+	for _, id := range replaced {
+		deleted[id] = true
+	}
+	return deleted, automatic
+}
+
+// routeWithin reports whether every path route matches is matched by other too (see
+// pageroute.Within). Routes saved before route syntax was checked only match themselves.
+func routeWithin(route, other string) bool {
+	a, err := pageroute.Parse(route)
+	if err != nil {
+		return route == other
+	}
+	b, err := pageroute.Parse(other)
+	if err != nil {
+		return false
+	}
+	return pageroute.Within(a, b)
+}
+
+// resolve resolves a link like GET /resolve. Drafts and hidden pages count as
+// existing, since they can be published or shown later.
+func (d *linkData) resolve(path string) (*models.RouteMatch, error) {
+	if _, ok := pageroute.SplitPath(path); !ok {
+		return nil, errors.New("path must start with / and have no empty segments")
+	}
+	return resolveRoute(d.pages, d.redirects, path, models.RenderOptions{})
+}
+
+// check returns an issue for each link in config that leads nowhere (rule broken_link,
+// an error) or only through redirects (rule redirected_link, a warning). The issues
+// copy base; prefix is prepended to the field names, e.g. "root.children[0]." for
+// a component definition.
+func (d *linkData) check(config map[string]interface{}, base models.LintIssue, prefix string) []models.LintIssue {
+	var issues []models.LintIssue
+	for _, link := range findLinks(config) {
+		issue := base
+		issue.Field = prefix + "config." + link.field
+
+		match, err := d.resolve(link.path)
+		switch {
+		case err != nil:
+			issue.Rule, issue.Severity = "broken_link", utils.LintSeverityError
+			switch err.Error() {
+			case "no page matches the path":
+				issue.Message = fmt.Sprintf("%s %q does not lead to any page", issue.Field, link.path)
+			case "too many redirects":
+				issue.Message = fmt.Sprintf("%s %q goes through too many redirects", issue.Field, link.path)
+			default:
+				issue.Message = fmt.Sprintf("%s %q is not a valid app path", issue.Field, link.path)
+			}
+		case len(match.Redirects) > 0:
+			issue.Rule, issue.Severity = "redirected_link", utils.LintSeverityWarning
+			issue.Message = fmt.Sprintf("%s %q is redirected to %q; link to it directly", issue.Field, link.path, match.Path)
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// linksTo returns the links in config that lead to the page, directly or through redirects.
+func (d *linkData) linksTo(config map[string]interface{}, pageID string) []configLink {
+	var found []configLink
+	for _, link := range findLinks(config) {
+		if match, err := d.resolve(link.path); err == nil && match.Page.ID == pageID {
+			found = append(found, link)
+		}
+	}
+	return found
+}
+
+// inboundLinks lists the widgets on other pages and the component definitions that
+// link to a page, directly or through redirects.
+func inboundLinks(ctx context.Context, pageID string) ([]models.PageLink, error) {
+	widgets, err := repository.GetWidgetsWithLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	components, err := repository.GetAllComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := loadLinkData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []models.PageLink
+	for i := range widgets {
+		w := &widgets[i]
+		if w.PageID == pageID {
+			// Deleted along with the page
+			continue
+		}
+		for _, link := range data.linksTo(w.Config, pageID) {
+			links = append(links, models.PageLink{WidgetID: &w.ID, PageID: &w.PageID, Field: "config." + link.field, Link: link.path})
+		}
+	}
+	for i := range components {
+		c := &components[i]
+		var walk func(n models.WidgetNode, path string)
+		walk = func(n models.WidgetNode, path string) {
+			for _, link := range data.linksTo(n.Config, pageID) {
+				links = append(links, models.PageLink{ComponentID: &c.ID, Field: path + ".config." + link.field, Link: link.path})
+			}
+			for j, child := range n.Children {
+				walk(child, fmt.Sprintf("%s.children[%d]", path, j))
+			}
+		}
+		walk(c.Root, "root")
+	}
+	return links, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"appdrop-api/internal/models"
+)
+
+func TestMovedRedirects(t *testing.T) {
+	to := func(id, from, route string) models.Redirect {
+		return models.Redirect{ID: id, FromRoute: from, ToRoute: &route}
+	}
+	tests := []struct {
+		name      string
+		oldRoute  string
+		newRoute  string
+		others    []models.Page
+		redirects []models.Redirect
+		deleted   []string
+		automatic bool
+	}{
+		{
+			name:     "shadowed and replaced redirects",
+			oldRoute: "/items/:productId",
+			newRoute: "/products/:id",
+			redirects: []models.Redirect{
+				to("same-shape", "/products/:slug", "/catalog/:slug"),
+				to("covered", "/products/featured", "/catalog/featured"),
+				to("old-route", "/items/:sku", "/catalog/:sku"),
+				to("unrelated", "/other", "/catalog"),
+				to("partly-covered", "/:section/:id", "/catalog/:id"),
+			},
+			deleted:   []string{"covered", "old-route", "same-shape"},
+			automatic: true,
+		},
+		{
+			name:      "old route covered by another page",
+			oldRoute:  "/deals/sale",
+			newRoute:  "/sale",
+			others:    []models.Page{{ID: "deal", Route: "/deals/:slug"}},
+			redirects: []models.Redirect{to("old-route", "/deals/sale", "/")},
+		},
+		{
+			name:      "old route ambiguous with a redirect",
+			oldRoute:  "/:category/new",
+			newRoute:  "/new/:category",
+			redirects: []models.Redirect{to("ambiguous", "/shop/:productId", "/products/:productId")},
+		},
+		{
+			name:      "new route has parameters the old one cannot fill in",
+			oldRoute:  "/sale",
+			newRoute:  "/products/:id",
+			redirects: []models.Redirect{to("same-shape", "/products/:slug", "/")},
+			deleted:   []string{"same-shape"},
+		},
+		{
+			name:      "old route predating route syntax checks",
+			oldRoute:  "/Old Page",
+			newRoute:  "/new-page",
+			redirects: []models.Redirect{to("old-route", "/Old Page", "/"), to("unrelated", "/Old", "/")},
+			deleted:   []string{"old-route"},
+			automatic: true,
+		},
+	}
+	for _, tt := range tests {
+		page := models.Page{ID: "page", Route: tt.newRoute}
+		links := &linkData{pages: append([]models.Page{{ID: "page", Route: tt.oldRoute}}, tt.others...), redirects: tt.redirects}
+		deleted, automatic := links.movedRedirects(page, tt.oldRoute)
+		if got := slices.Sorted(maps.Keys(deleted)); !reflect.DeepEqual(got, tt.deleted) {
+			t.Errorf("%s: deleted = %v, want %v", tt.name, got, tt.deleted)
+		}
+		if (automatic != nil) != tt.automatic {
+			t.Errorf("%s: automatic redirect = %+v, want one: %v", tt.name, automatic, tt.automatic)
+		} else if automatic != nil && (automatic.FromRoute != tt.oldRoute || *automatic.PageID != "page" || !automatic.Automatic) {
+			t.Errorf("%s: automatic redirect = %+v", tt.name, automatic)
+		}
+	}
+}
+
+func TestWithPageMovesRedirects(t *testing.T) {
+	links := &linkData{
+		pages:     []models.Page{{ID: "page", Route: "/items/:productId"}},
+		redirects: []models.Redirect{{ID: "r1", FromRoute: "/products/:slug", PageID: strPtr("page")}},
+	}
+	moved := links.withPage(models.Page{ID: "page", Route: "/products/:id"}, "/items/:productId")
+
+	match, err := moved.resolve("/items/42")
+	if err != nil || match.Path != "/products/42" || len(match.Redirects) != 1 {
+		t.Errorf("resolve(/items/42) = %+v, %v; want /products/42 after one redirect", match, err)
+	}
+	if len(moved.redirects) != 1 || moved.redirects[0].FromRoute != "/items/:productId" {
+		t.Errorf("redirects = %+v, want only the automatic one", moved.redirects)
+	}
+	if len(links.redirects) != 1 || links.pages[0].Route != "/items/:productId" {
+		t.Error("withPage changed the original link data")
+	}
+}
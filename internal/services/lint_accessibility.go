@@ -0,0 +1,281 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/utils"
+)
+
+// textColorFields are the config fields holding a widget's text color, in order of preference.
+var textColorFields = []string{"color", "text_color"}
+
+// linkTextFields are the config fields holding the text of a linked widget, in order of preference.
+var linkTextFields = []string{"link_text", "label", "title", "content"}
+
+// vagueLinkTexts are link texts that do not say where a link leads, lowercased.
+var vagueLinkTexts = map[string]bool{
+	"click": true, "click here": true, "tap": true, "tap here": true, "here": true,
+	"more": true, "read more": true, "learn more": true, "see more": true, "view more": true,
+	"more info": true, "details": true, "link": true, "this": true, "go": true,
+}
+
+// rgb is an opaque sRGB color with channels from 0 to 1.
+type rgb struct{ r, g, b float64 }
+
+// parseColor parses a hex color (#RGB, #RRGGBB or #RRGGBBAA). A translucent color is
+// blended over under. Returns false for anything else, e.g. an unresolved reference.
+func parseColor(value interface{}, under rgb) (rgb, bool) {
+	s, _ := value.(string)
+	if !hexColorPattern.MatchString(s) {
+		return rgb{}, false
+	}
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	channel := func(i int) float64 {
+		v, _ := strconv.ParseUint(hex[i:i+2], 16, 8)
+		return float64(v) / 255
+	}
+	c := rgb{channel(0), channel(2), channel(4)}
+	if len(hex) == 8 {
+		c = blend(under, c, channel(6))
+	}
+	return c, true
+}
+
+// blend mixes a and b, taking the fraction t of b.
+func blend(a, b rgb, t float64) rgb {
+	return rgb{a.r + (b.r-a.r)*t, a.g + (b.g-a.g)*t, a.b + (b.b-a.b)*t}
+}
+
+func (c rgb) String() string {
+	return fmt.Sprintf("#%02X%02X%02X", int(math.Round(c.r*255)), int(math.Round(c.g*255)), int(math.Round(c.b*255)))
+}
+
+// luminance is the WCAG relative luminance of c.
+func (c rgb) luminance() float64 {
+	linear := func(v float64) float64 {
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.r) + 0.7152*linear(c.g) + 0.0722*linear(c.b)
+}
+
+// contrastRatio is the WCAG contrast ratio of two colors, from 1 to 21.
+func contrastRatio(a, b rgb) float64 {
+	la, lb := a.luminance(), b.luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// fixContrast returns the color closest to fg, moving towards black or white (whichever
+// contrasts more with bg), that reaches ratio against bg.
+func fixContrast(fg, bg rgb, ratio float64) rgb {
+	black, white := rgb{}, rgb{1, 1, 1}
+	target := white
+	if contrastRatio(black, bg) > contrastRatio(white, bg) {
+		target = black
+	}
+	for step := 1; step < 100; step++ {
+		c := blend(fg, target, float64(step)/100)
+		// Compare the color as it will be written out
+		if c, _ = parseColor(c.String(), rgb{}); contrastRatio(c, bg) >= ratio {
+			return c
+		}
+	}
+	return target
+}
+
+// configNumber reads a numeric config value: a number, or a string such as "18" or "18pt".
+func configNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		v = strings.TrimSpace(v)
+		v = strings.TrimSuffix(strings.TrimSuffix(v, "pt"), "px")
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// largeText reports whether a config's font_size and font_weight make its text large in
+// the WCAG sense, which lowers the contrast it needs.
+func largeText(config map[string]interface{}) bool {
+	size, ok := configNumber(config["font_size"])
+	if !ok {
+		return false
+	}
+	weight, _ := configNumber(config["font_weight"])
+	if s, _ := config["font_weight"].(string); s == "bold" {
+		weight = 700
+	}
+	return size >= utils.LargeTextSize || (size >= utils.LargeBoldTextSize && weight >= 700)
+}
+
+// lintColorSchemes returns the color schemes the app is shown in: light, and dark if the
+// theme has dark colors.
+func (t *lintTarget) lintColorSchemes() []string {
+	schemes := []string{utils.ColorSchemeLight}
+	if len(t.theme.DarkColors) > 0 {
+		schemes = append(schemes, utils.ColorSchemeDark)
+	}
+	return schemes
+}
+
+// resolvedConfig returns a node's config with theme references resolved for colorScheme.
+func (t *lintTarget) resolvedConfig(n *lintNode, colorScheme string) map[string]interface{} {
+	return resolveReferences(n.config, themeLookup(t.theme, colorScheme))
+}
+
+func checkMissingAltText(t *lintTarget) []models.LintIssue {
+	var issues []models.LintIssue
+	t.walk(func(n *lintNode) {
+		switch n.widgetType {
+		case "image":
+		case "banner":
+			// A banner without an image is text only
+			if n.config["image_url"] == nil && n.config["asset_id"] == nil {
+				return
+			}
+		default:
+			return
+		}
+		if alt, _ := n.config["alt_text"].(string); strings.TrimSpace(alt) == "" {
+			issues = append(issues, n.issue("config.alt_text", n.widgetType+" widget has no alt_text"))
+		}
+	})
+	return issues
+}
+
+// checkLowContrast compares each configured text color with the configured
+// background_color, or else the theme's "background" color, or else the platform
+// default (white, or black in dark mode). The theme's "text" color stands in for a
+// missing text color. Only widgets that set a text or background color are checked.
+func checkLowContrast(t *lintTarget) []models.LintIssue {
+	var issues []models.LintIssue
+	t.walk(func(n *lintNode) {
+		fgField := ""
+		for _, field := range textColorFields {
+			if n.config[field] != nil {
+				fgField = field
+				break
+			}
+		}
+		if fgField == "" && n.config["background_color"] == nil {
+			return
+		}
+
+		seen := map[string]bool{}
+		for _, scheme := range t.lintColorSchemes() {
+			config := t.resolvedConfig(n, scheme)
+			lookup := themeLookup(t.theme, scheme)
+
+			base := rgb{1, 1, 1}
+			if scheme == utils.ColorSchemeDark {
+				base = rgb{}
+			}
+			bgValue := config["background_color"]
+			if value, ok := lookup("color.background"); ok && bgValue == nil {
+				bgValue = value
+			}
+			bg := base
+			if bgValue != nil {
+				var ok bool
+				if bg, ok = parseColor(bgValue, base); !ok {
+					continue
+				}
+			}
+			fgValue := config[fgField]
+			if value, ok := lookup("color.text"); ok && fgField == "" {
+				fgValue = value
+			}
+			fg, ok := parseColor(fgValue, bg)
+			if !ok || seen[fg.String()+bg.String()] {
+				continue
+			}
+			seen[fg.String()+bg.String()] = true
+
+			minimum := utils.MinContrastRatio
+			if largeText(config) {
+				minimum = utils.MinLargeTextContrastRatio
+			}
+			ratio := contrastRatio(fg, bg)
+			if ratio >= minimum {
+				continue
+			}
+
+			field := "config." + fgField
+			if fgField == "" {
+				field = "config.background_color"
+			}
+			issue := n.issue(field, fmt.Sprintf("text color %s on background %s has a contrast ratio of %.2f:1, below %.1f:1", fg, bg, ratio, minimum))
+			if len(t.lintColorSchemes()) > 1 {
+				issue.Message += " in " + scheme + " mode"
+			}
+			if fgField != "" {
+				fixed := fixContrast(fg, bg, minimum)
+				verb := "Lighten"
+				if fixed.luminance() < fg.luminance() {
+					verb = "Darken"
+				}
+				issue.Hint = fmt.Sprintf("%s the text color to %s or beyond, or change background_color.", verb, fixed)
+			}
+			issues = append(issues, issue)
+		}
+	})
+	return issues
+}
+
+func checkSmallFontSize(t *lintTarget) []models.LintIssue {
+	var issues []models.LintIssue
+	t.walk(func(n *lintNode) {
+		if n.config["font_size"] == nil {
+			return
+		}
+		size, ok := configNumber(t.resolvedConfig(n, utils.ColorSchemeLight)["font_size"])
+		if ok && size < utils.MinFontSize {
+			issues = append(issues, n.issue("config.font_size", fmt.Sprintf("font size %g is below %d points", size, utils.MinFontSize)))
+		}
+	})
+	return issues
+}
+
+func checkVagueLinkText(t *lintTarget) []models.LintIssue {
+	var issues []models.LintIssue
+	t.walk(func(n *lintNode) {
+		linked := false
+		for _, key := range utils.LinkConfigKeys {
+			if value, _ := n.config[key].(string); value != "" {
+				linked = true
+			}
+		}
+		if !linked {
+			return
+		}
+		for _, field := range linkTextFields {
+			text, _ := n.config[field].(string)
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			normalized := strings.ToLower(strings.Trim(text, " \t\n.!?:…>»→"))
+			if vagueLinkTexts[normalized] {
+				issues = append(issues, n.issue("config."+field, fmt.Sprintf("link text %q does not say where the link leads", text)))
+			}
+			return
+		}
+	})
+	return issues
+}
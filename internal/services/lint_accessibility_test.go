@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	testBlack = rgb{}
+	testWhite = rgb{1, 1, 1}
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		under rgb
+		want  string
+		ok    bool
+	}{
+		{"#000000", testWhite, "#000000", true},
+		{"#FFFFFF", testBlack, "#FFFFFF", true},
+		{"#777", testWhite, "#777777", true},
+		{"#1a2B3c", testWhite, "#1A2B3C", true},
+		{"#F0A", testWhite, "#FF00AA", true},
+		// Alpha blends over the color underneath
+		{"#000000FF", testWhite, "#000000", true},
+		{"#00000000", testWhite, "#FFFFFF", true},
+		{"#00000080", testWhite, "#7F7F7F", true},
+		{"#FFFFFF80", testBlack, "#808080", true},
+		// Anything else is not a color
+		{"777777", testWhite, "", false},
+		{"#7777", testWhite, "", false},
+		{"#GGGGGG", testWhite, "", false},
+		{"{{color.primary}}", testWhite, "", false},
+		{"", testWhite, "", false},
+		{nil, testWhite, "", false},
+		{float64(0), testWhite, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseColor(tt.value, tt.under)
+		if ok != tt.ok || (ok && got.String() != tt.want) {
+			t.Errorf("parseColor(%v, %s) = %s, %v; want %s, %v", tt.value, tt.under, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		fg, bg string
+		want   float64
+	}{
+		{"#000", "#fff", 21},
+		{"#fff", "#fff", 1},
+		{"#777", "#fff", 4.48},
+		{"#767676", "#fff", 4.54},
+		{"#808080", "#fff", 3.95},
+		{"#fff", "#00f", 8.59},
+		{"#f00", "#fff", 4},
+		{"#777", "#000", 4.69},
+	}
+	for _, tt := range tests {
+		fg, _ := parseColor(tt.fg, testWhite)
+		bg, _ := parseColor(tt.bg, testWhite)
+		got := contrastRatio(fg, bg)
+		if math.Abs(got-tt.want) > 0.005 {
+			t.Errorf("contrastRatio(%s, %s) = %.4f, want %.2f", tt.fg, tt.bg, got, tt.want)
+		}
+		if reverse := contrastRatio(bg, fg); reverse != got {
+			t.Errorf("contrastRatio(%s, %s) = %.4f, but %.4f the other way round", tt.fg, tt.bg, got, reverse)
+		}
+	}
+}
+
+func TestFixContrast(t *testing.T) {
+	tests := []struct {
+		fg, bg string
+		ratio  float64
+		darker bool
+	}{
+		{"#777", "#fff", 4.5, true},
+		{"#999", "#fff", 4.5, true},
+		{"#777", "#000", 7, false},
+		{"#333", "#222", 4.5, false},
+		{"#ccc", "#eee", 3, true},
+	}
+	for _, tt := range tests {
+		fg, _ := parseColor(tt.fg, testWhite)
+		bg, _ := parseColor(tt.bg, testWhite)
+		fixed := fixContrast(fg, bg, tt.ratio)
+		if got := contrastRatio(fixed, bg); got < tt.ratio {
+			t.Errorf("fixContrast(%s, %s, %g) = %s with ratio %.2f, want at least %g", tt.fg, tt.bg, tt.ratio, fixed, got, tt.ratio)
+		}
+		if darker := fixed.luminance() < fg.luminance(); darker != tt.darker {
+			t.Errorf("fixContrast(%s, %s, %g) = %s, darker = %v, want %v", tt.fg, tt.bg, tt.ratio, fixed, darker, tt.darker)
+		}
+	}
+
+	// The smallest change that reaches the ratio: #777 on white only just misses 4.5
+	fg, _ := parseColor("#777", testWhite)
+	if got := fixContrast(fg, testWhite, 4.5).String(); got != "#767676" {
+		t.Errorf("fixContrast(#777, #fff, 4.5) = %s, want #767676", got)
+	}
+}
+
+func TestConfigNumber(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  float64
+		ok    bool
+	}{
+		{float64(18.5), 18.5, true},
+		{16, 16, true},
+		{"18", 18, true},
+		{" 18pt ", 18, true},
+		{"14px", 14, true},
+		{"large", 0, false},
+		{"", 0, false},
+		{true, 0, false},
+		{nil, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := configNumber(tt.value)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("configNumber(%#v) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLargeText(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		want   bool
+	}{
+		{map[string]interface{}{}, false},
+		{map[string]interface{}{"font_size": float64(23.9)}, false},
+		{map[string]interface{}{"font_size": float64(24)}, true},
+		{map[string]interface{}{"font_size": "24pt"}, true},
+		{map[string]interface{}{"font_size": float64(18.66)}, false},
+		{map[string]interface{}{"font_size": float64(18.66), "font_weight": "bold"}, true},
+		{map[string]interface{}{"font_size": float64(18.66), "font_weight": float64(700)}, true},
+		{map[string]interface{}{"font_size": float64(18.66), "font_weight": "600"}, false},
+		{map[string]interface{}{"font_size": float64(18), "font_weight": "bold"}, false},
+		{map[string]interface{}{"font_weight": "bold"}, false},
+	}
+	for _, tt := range tests {
+		if got := largeText(tt.config); got != tt.want {
+			t.Errorf("largeText(%v) = %v, want %v", tt.config, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/utils"
+)
+
+// lintRules are the rules the page linter runs, in report order.
+var lintRules = []lintRule{
+	{
+		name:        "empty_page",
+		description: "The page has no widgets.",
+		category:    "layout",
+		hint:        "Add widgets to the page, or delete it if it is no longer needed.",
+		severity:    utils.LintSeverityWarning,
+		check:       checkEmptyPage,
+	},
+	{
+		name:        "leading_spacer",
+		description: "The page starts with a spacer, leaving a gap at the top of the screen.",
+		category:    "layout",
+		hint:        "Remove the spacer or move it below the first widget.",
+		severity:    utils.LintSeverityWarning,
+		check:       checkLeadingSpacer,
+	},
+	{
+		name:        "adjacent_banners",
+		description: "Two banners follow each other in the page or a column; use a carousel instead.",
+		category:    "layout",
+		hint:        "Put the banners in a carousel.",
+		severity:    utils.LintSeverityWarning,
+		check:       checkAdjacentBanners,
+	},
+	{
+		name:        "empty_text",
+		description: "A text widget has no content.",
+		category:    "content",
+		hint:        "Add content or remove the widget.",
+		severity:    utils.LintSeverityError,
+		check:       checkEmptyText,
+	},
+	{
+		name:        "missing_alt_text",
+		description: "An image widget, or a banner with an image, has no alt_text for screen readers.",
+		category:    "accessibility",
+		hint:        "Describe what the image shows, or where tapping it leads, in alt_text so VoiceOver and TalkBack can read it out.",
+		severity:    utils.LintSeverityWarning,
+		check:       checkMissingAltText,
+	},
+	{
+		name:        "low_contrast",
+		description: "A text color does not contrast enough with its background (WCAG 2.1 AA: 4.5:1, or 3:1 for large text), in light mode or, if the theme has dark colors, dark mode.",
+		category:    "accessibility",
+		hint:        "Use a darker text color on light backgrounds and a lighter one on dark backgrounds, or change background_color.",
+		severity:    utils.LintSeverityWarning,
+		check:       checkLowContrast,
+	},
+	{
+		name:        "small_font_size",
+		description: "A font_size is below 12 points.",
+		category:    "accessibility",
+		hint:        "Use at least 12 points, preferably through a theme typography token.",
+		severity:    utils.LintSeverityWarning,
+		check:       checkSmallFontSize,
+	},
+	{
+		name:        "vague_link_text",
+		description: "A linked widget's text, such as \"click here\" or \"read more\", does not say where the link leads.",
+		category:    "accessibility",
+		hint:        "Name the destination, e.g. \"See the summer sale\" instead of \"Click here\"; screen readers often list links out of context.",
+		severity:    utils.LintSeverityWarning,
+		check:       checkVagueLinkText,
+	},
+	{
+		name:        "broken_link",
+		description: "A link or target_route app path leads to no page.",
+		category:    "links",
+		hint:        "Link to an existing page's route, or add a redirect from the old path.",
+		severity:    utils.LintSeverityError,
+		check:       linkCheck("broken_link"),
+	},
+	{
+		name:        "redirected_link",
+		description: "A link or target_route app path reaches its page only through redirects.",
+		category:    "links",
+		hint:        "Replace the link with the path it is redirected to.",
+		severity:    utils.LintSeverityWarning,
+		check:       linkCheck("redirected_link"),
+	},
+	{
+		name:        "broken_navigation",
+		description: "A navigation item lost its page when the page was deleted.",
+		category:    "links",
+		hint:        "Give the item a new page or remove it.",
+		severity:    utils.LintSeverityError,
+		check:       checkBrokenNavigation,
+	},
+}
+
+func checkEmptyPage(t *lintTarget) []models.LintIssue {
+	if t.page == nil || len(t.nodes) > 0 {
+		return nil
+	}
+	return []models.LintIssue{{Message: "page has no widgets"}}
+}
+
+func checkLeadingSpacer(t *lintTarget) []models.LintIssue {
+	if t.page == nil || len(t.nodes) == 0 || t.nodes[0].widgetType != "spacer" {
+		return nil
+	}
+	return []models.LintIssue{t.nodes[0].issue("", "page starts with a spacer")}
+}
+
+func checkAdjacentBanners(t *lintTarget) []models.LintIssue {
+	var issues []models.LintIssue
+	t.siblings(func(parent *lintNode, nodes []lintNode) {
+		// Rows, tabs and carousels show their children side by side or one at a time
+		if parent != nil && parent.widgetType != "column" {
+			return
+		}
+		for i := 1; i < len(nodes); i++ {
+			if nodes[i].widgetType == "banner" && nodes[i-1].widgetType == "banner" {
+				issues = append(issues, nodes[i].issue("", "banner directly follows another banner"))
+			}
+		}
+	})
+	return issues
+}
+
+func checkEmptyText(t *lintTarget) []models.LintIssue {
+	var issues []models.LintIssue
+	t.walk(func(n *lintNode) {
+		if n.widgetType != "text" {
+			return
+		}
+		if content, _ := n.config["content"].(string); strings.TrimSpace(content) == "" {
+			issues = append(issues, n.issue("config.content", "text widget has no content"))
+		}
+	})
+	return issues
+}
+
+// linkCheck returns a check reporting the link problems of one rule (see linkData.check).
+func linkCheck(rule string) func(t *lintTarget) []models.LintIssue {
+	return func(t *lintTarget) []models.LintIssue {
+		var issues []models.LintIssue
+		t.walk(func(n *lintNode) {
+			for _, issue := range t.links.check(n.config, n.issue("", ""), n.field) {
+				if issue.Rule == rule {
+					issues = append(issues, issue)
+				}
+			}
+		})
+		return issues
+	}
+}
+
+func checkBrokenNavigation(t *lintTarget) []models.LintIssue {
+	if t.nav == nil {
+		return nil
+	}
+	var issues []models.LintIssue
+	menus := []struct {
+		name  string
+		items []models.NavigationItem
+	}{{"tab bar", t.nav.TabBar}, {"drawer", t.nav.Drawer}}
+	for _, menu := range menus {
+		for i := range menu.items {
+			item := &menu.items[i]
+			if item.Broken {
+				issues = append(issues, models.LintIssue{
+					NavigationItemID: &item.ID,
+					Message:          fmt.Sprintf("%s item %q lost its page and needs a new target", menu.name, item.Label),
+				})
+			}
+		}
+	}
+	return issues
+}
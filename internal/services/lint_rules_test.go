@@ -0,0 +1,243 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/utils"
+)
+
+// lintWidget returns a top-level lint node for a widget.
+func lintWidget(id, widgetType string, config map[string]interface{}, children ...lintNode) lintNode {
+	return lintNode{widgetID: &id, widgetType: widgetType, config: config, children: children}
+}
+
+// strPtr returns a pointer to s.
+func strPtr(s string) *string { return &s }
+
+// issueNames summarises issues as "rule field" (or "rule" when not about a field).
+func issueNames(issues []models.LintIssue) []string {
+	names := []string{}
+	for _, issue := range issues {
+		names = append(names, strings.TrimSpace(issue.Rule+" "+issue.Field))
+	}
+	return names
+}
+
+func TestLintRules(t *testing.T) {
+	page := &models.Page{ID: "page", Route: "/promo"}
+	links := &linkData{
+		pages: []models.Page{{ID: "home", Route: "/home"}, *page},
+		redirects: []models.Redirect{
+			{ID: "r1", FromRoute: "/old-home", PageID: strPtr("home")},
+		},
+	}
+	text := func(id, content string) lintNode {
+		return lintWidget(id, "text", map[string]interface{}{"content": content})
+	}
+	banner := func(id string) lintNode {
+		return lintWidget(id, "banner", map[string]interface{}{"title": "Sale"})
+	}
+
+	tests := []struct {
+		name  string
+		nodes []lintNode
+		nav   *models.Navigation
+		theme *models.Theme
+		want  []string
+	}{
+		{"clean page", []lintNode{text("w1", "Hello")}, nil, nil, []string{}},
+		{"empty_page", nil, nil, nil, []string{"empty_page"}},
+		{"leading_spacer", []lintNode{lintWidget("w1", "spacer", map[string]interface{}{}), text("w2", "Hello")}, nil, nil,
+			[]string{"leading_spacer"}},
+		{"spacer after a widget", []lintNode{text("w1", "Hello"), lintWidget("w2", "spacer", map[string]interface{}{})}, nil, nil,
+			[]string{}},
+		{"adjacent_banners", []lintNode{banner("w1"), banner("w2"), text("w3", "Hello"), banner("w4")}, nil, nil,
+			[]string{"adjacent_banners"}},
+		{"adjacent_banners in a column", []lintNode{lintWidget("w1", "column", map[string]interface{}{}, banner("w2"), banner("w3"))}, nil, nil,
+			[]string{"adjacent_banners"}},
+		{"banners side by side in a row", []lintNode{lintWidget("w1", "row", map[string]interface{}{}, banner("w2"), banner("w3"))}, nil, nil,
+			[]string{}},
+		{"empty_text", []lintNode{text("w1", " \n")}, nil, nil, []string{"empty_text config.content"}},
+		{"empty_text in a component definition", []lintNode{
+			definitionLintNode(models.WidgetNode{Type: "column", Config: map[string]interface{}{}, Children: []models.WidgetNode{
+				{Type: "text", Config: map[string]interface{}{"content": "Hello"}},
+				{Type: "text", Config: map[string]interface{}{}},
+			}}, strPtr("w1"), strPtr("c1"), "root"),
+		}, nil, nil, []string{"empty_text root.children[1].config.content"}},
+		{"missing_alt_text", []lintNode{
+			lintWidget("w1", "image", map[string]interface{}{"image_url": "https://example.com/a.png"}),
+			lintWidget("w2", "image", map[string]interface{}{"image_url": "https://example.com/b.png", "alt_text": "A red shirt"}),
+			lintWidget("w3", "banner", map[string]interface{}{"image_url": "https://example.com/c.png", "alt_text": " "}),
+		}, nil, nil, []string{"missing_alt_text config.alt_text", "missing_alt_text config.alt_text"}},
+		{"low_contrast", []lintNode{
+			lintWidget("w1", "text", map[string]interface{}{"content": "Hello", "color": "#777777"}),
+			lintWidget("w2", "text", map[string]interface{}{"content": "Hello", "color": "#767676"}),
+			// Large text needs 3:1
+			lintWidget("w3", "text", map[string]interface{}{"content": "Hello", "color": "#777777", "font_size": float64(24)}),
+			lintWidget("w4", "text", map[string]interface{}{"content": "Hello", "color": "#FFFFFF", "background_color": "#FFFF00"}),
+		}, nil, nil, []string{"low_contrast config.color", "low_contrast config.color"}},
+		{"low_contrast with the theme's text color", []lintNode{
+			lintWidget("w1", "text", map[string]interface{}{"content": "Hello", "background_color": "#FFFFFF"}),
+		}, nil, &models.Theme{Colors: map[string]string{"text": "#999999"}}, []string{"low_contrast config.background_color"}},
+		{"low_contrast in dark mode", []lintNode{
+			lintWidget("w1", "text", map[string]interface{}{"content": "Hello", "color": "{{color.primary}}"}),
+		}, nil, &models.Theme{
+			Colors:     map[string]string{"primary": "#1A1A1A"},
+			DarkColors: map[string]string{"primary": "#222222"},
+		}, []string{"low_contrast config.color"}},
+		{"small_font_size", []lintNode{
+			lintWidget("w1", "text", map[string]interface{}{"content": "Hello", "font_size": "11pt"}),
+			lintWidget("w2", "text", map[string]interface{}{"content": "Hello", "font_size": float64(12)}),
+			lintWidget("w3", "text", map[string]interface{}{"content": "Hello", "font_size": "{{typography.caption.font_size}}"}),
+		}, nil, &models.Theme{Typography: map[string]models.TypographyToken{"caption": {FontSize: 10}}},
+			[]string{"small_font_size config.font_size", "small_font_size config.font_size"}},
+		{"vague_link_text", []lintNode{
+			lintWidget("w1", "button", map[string]interface{}{"label": "Click here!", "link": "/home"}),
+			lintWidget("w2", "button", map[string]interface{}{"label": "See the summer sale", "link": "/home"}),
+			// Without a link, the text is not link text
+			lintWidget("w3", "button", map[string]interface{}{"label": "Read more"}),
+		}, nil, nil, []string{"vague_link_text config.label"}},
+		{"broken_link", []lintNode{
+			lintWidget("w1", "button", map[string]interface{}{"label": "Shop", "link": "/missing"}),
+			lintWidget("w2", "banner", map[string]interface{}{"title": "Sale", "target_route": "/promo"}),
+			lintWidget("w3", "button", map[string]interface{}{"label": "Shop", "link": "https://example.com/missing"}),
+		}, nil, nil, []string{"broken_link config.link"}},
+		{"redirected_link", []lintNode{
+			lintWidget("w1", "button", map[string]interface{}{"label": "Home", "link": "/old-home"}),
+		}, nil, nil, []string{"redirected_link config.link"}},
+		{"broken_navigation", []lintNode{text("w1", "Hello")}, &models.Navigation{
+			TabBar: []models.NavigationItem{{ID: "n1", Label: "Home", PageID: strPtr("home")}},
+			Drawer: []models.NavigationItem{{ID: "n2", Label: "Sale", Broken: true}},
+		}, nil, []string{"broken_navigation"}},
+	}
+	for _, tt := range tests {
+		theme := tt.theme
+		if theme == nil {
+			theme = &models.Theme{}
+		}
+		target := &lintTarget{page: page, nodes: tt.nodes, links: links, nav: tt.nav, theme: theme}
+		if got := issueNames(runLintRules(target, &models.LintSettings{}, "")); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: issues = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunLintRulesFillsIssues(t *testing.T) {
+	nodes := []lintNode{lintWidget("w1", "text", map[string]interface{}{"content": ""})}
+	issues := runLintRules(&lintTarget{page: &models.Page{ID: "page"}, nodes: nodes, theme: &models.Theme{}}, &models.LintSettings{}, "")
+	if len(issues) != 1 {
+		t.Fatalf("issues = %v, want one", issues)
+	}
+	issue := issues[0]
+	if issue.Rule != "empty_text" || issue.Severity != utils.LintSeverityError || issue.Hint != "Add content or remove the widget." ||
+		issue.PageID == nil || *issue.PageID != "page" || issue.WidgetID == nil || *issue.WidgetID != "w1" {
+		t.Errorf("issue = %+v", issue)
+	}
+
+	// A page not created yet has no ID to report
+	issues = runLintRules(&lintTarget{page: &models.Page{}, nodes: nodes, theme: &models.Theme{}}, &models.LintSettings{}, "")
+	if len(issues) != 1 || issues[0].PageID != nil {
+		t.Errorf("issues for a new page = %+v, want one without a page ID", issues)
+	}
+}
+
+func TestRunLintRulesSettings(t *testing.T) {
+	// Triggers empty_text (an error) and leading_spacer (a warning)
+	nodes := []lintNode{
+		lintWidget("w1", "spacer", map[string]interface{}{}),
+		lintWidget("w2", "text", map[string]interface{}{"content": ""}),
+	}
+	tests := []struct {
+		name       string
+		severities map[string]string
+		category   string
+		want       []string
+	}{
+		{"defaults", nil, "", []string{"leading_spacer warning", "empty_text error"}},
+		{"override", map[string]string{"empty_text": "warning", "leading_spacer": "error"}, "",
+			[]string{"leading_spacer error", "empty_text warning"}},
+		{"off", map[string]string{"leading_spacer": "off"}, "", []string{"empty_text error"}},
+		{"all off", map[string]string{"leading_spacer": "off", "empty_text": "off"}, "", []string{}},
+		{"category", nil, "content", []string{"empty_text error"}},
+		{"category and off", map[string]string{"empty_text": "off"}, "content", []string{}},
+		{"other category", nil, "links", []string{}},
+	}
+	for _, tt := range tests {
+		target := &lintTarget{page: &models.Page{ID: "page"}, nodes: nodes, theme: &models.Theme{}}
+		got := []string{}
+		for _, issue := range runLintRules(target, &models.LintSettings{Severities: tt.severities}, tt.category) {
+			got = append(got, issue.Rule+" "+issue.Severity)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: issues = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewLintReport(t *testing.T) {
+	issues := []models.LintIssue{
+		{Rule: "a", Severity: utils.LintSeverityWarning},
+		{Rule: "b", Severity: utils.LintSeverityError},
+		{Rule: "c", Severity: utils.LintSeverityWarning},
+		{Rule: "d", Severity: utils.LintSeverityError},
+	}
+	report := newLintReport(issues)
+	got := []string{}
+	for _, issue := range report.Issues {
+		got = append(got, issue.Rule)
+	}
+	if want := []string{"b", "d", "a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("report order = %v, want %v", got, want)
+	}
+	if report.Errors != 2 || report.Warnings != 2 {
+		t.Errorf("report counts = %d errors, %d warnings; want 2, 2", report.Errors, report.Warnings)
+	}
+	if issues[0].Rule != "a" {
+		t.Error("newLintReport reordered its argument")
+	}
+
+	// An empty report lists no issues rather than null
+	if report := newLintReport(nil); report.Issues == nil || len(report.Issues) != 0 || report.Errors != 0 || report.Warnings != 0 {
+		t.Errorf("newLintReport(nil) = %+v", report)
+	}
+}
+
+func TestUpdateLintSettingsValidation(t *testing.T) {
+	tests := []struct {
+		severities map[string]string
+		err        string
+	}{
+		{map[string]string{"no_such_rule": "error"}, "unknown lint rule no_such_rule"},
+		{map[string]string{"empty_text": "fatal"}, "severities.empty_text must be error, warning or off"},
+		{map[string]string{"empty_text": ""}, "severities.empty_text must be error, warning or off"},
+		{map[string]string{"broken_link": "Error"}, "severities.broken_link must be error, warning or off"},
+	}
+	for _, tt := range tests {
+		_, err := UpdateLintSettings(t.Context(), models.LintSettings{Severities: tt.severities})
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("UpdateLintSettings(%v) error = %v, want %s", tt.severities, err, tt.err)
+		}
+	}
+}
+
+func TestLintRuleDefinitions(t *testing.T) {
+	seen := map[string]bool{}
+	for _, rule := range lintRules {
+		if seen[rule.name] {
+			t.Errorf("rule %s is listed twice", rule.name)
+		}
+		seen[rule.name] = true
+		if !utils.LintCategories[rule.category] {
+			t.Errorf("rule %s has unknown category %q", rule.name, rule.category)
+		}
+		if rule.severity != utils.LintSeverityError && rule.severity != utils.LintSeverityWarning {
+			t.Errorf("rule %s has default severity %q, want error or warning", rule.name, rule.severity)
+		}
+		if rule.description == "" || rule.hint == "" || rule.check == nil {
+			t.Errorf("rule %s is missing a description, hint or check", rule.name)
+		}
+	}
+}
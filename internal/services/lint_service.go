@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// lintRule is a check run by the page linter. Rules are listed in lintRules; a new rule
+// only needs to be added there.
+type lintRule struct {
+	// name identifies the rule in reports and lint settings
+	name string
+	// description explains what the rule looks for
+	description string
+	// category is one of utils.LintCategories
+	category string
+	// hint suggests how to fix the problems the rule finds, unless an issue has its own
+	hint string
+	// severity is the default severity, utils.LintSeverityError or utils.LintSeverityWarning
+	severity string
+	// check returns the problems found in t. Rule, severity, page ID and (if empty)
+	// hint are filled in by the linter.
+	check func(t *lintTarget) []models.LintIssue
+}
+
+// lintTarget is what one lint pass looks at: a page's widget tree, a component definition
+// that no page uses, or (for app-wide rules) the navigation.
+type lintTarget struct {
+	// page is the page linted, or nil for a component definition or the navigation
+	page *models.Page
+	// nodes are the top-level widgets, with component references expanded
+	nodes []lintNode
+	// links resolves app paths in link fields
+	links *linkData
+	// nav is the navigation, set only for the app-wide pass
+	nav *models.Navigation
+	// theme resolves token references in widget configs; never nil
+	theme *models.Theme
+}
+
+// lintNode is a widget, or a node of a component definition, as seen by lint rules.
+type lintNode struct {
+	// widgetID is the widget, or the component widget referencing the definition
+	widgetID *string
+	// componentID is the component the node belongs to, if any
+	componentID *string
+	// field prefixes config field names in issues, e.g. "root.children[0]."
+	field string
+	// widgetType is the type the node renders as
+	widgetType string
+	// config is the node's configuration
+	config map[string]interface{}
+	// children are the nested nodes of a container
+	children []lintNode
+}
+
+// widgetLintNodes converts a widget tree (see widgetIndex.tree) to lint nodes. A component
+// widget becomes its component's definition.
+func widgetLintNodes(widgets []models.Widget) []lintNode {
+	nodes := make([]lintNode, 0, len(widgets))
+	for i := range widgets {
+		w := &widgets[i]
+		if w.Component != nil {
+			nodes = append(nodes, definitionLintNode(w.Component.Root, &w.ID, &w.Component.ID, "root"))
+			continue
+		}
+		nodes = append(nodes, lintNode{
+			widgetID:   &w.ID,
+			widgetType: w.Type,
+			config:     w.Config,
+			children:   widgetLintNodes(w.Children),
+		})
+	}
+	return nodes
+}
+
+// definitionLintNode converts a component definition to a lint node. path names the node
+// in field names, e.g. "root".
+func definitionLintNode(d models.WidgetNode, widgetID, componentID *string, path string) lintNode {
+	n := lintNode{widgetID: widgetID, componentID: componentID, field: path + ".", widgetType: d.Type, config: d.Config}
+	for i, child := range d.Children {
+		n.children = append(n.children, definitionLintNode(child, widgetID, componentID, fmt.Sprintf("%s.children[%d]", path, i)))
+	}
+	return n
+}
+
+// issue returns a problem found in the node. field is the config field at fault, e.g.
+// "config.content", or "" for the node as a whole; a component definition node is then
+// named by its path, e.g. "root.children[0]".
+func (n *lintNode) issue(field, message string) models.LintIssue {
+	issue := models.LintIssue{WidgetID: n.widgetID, ComponentID: n.componentID, Message: message}
+	if field != "" {
+		issue.Field = n.field + field
+	} else {
+		issue.Field = strings.TrimSuffix(n.field, ".")
+	}
+	return issue
+}
+
+// walk calls fn for every node, parents before children.
+func (t *lintTarget) walk(fn func(n *lintNode)) {
+	var walk func(nodes []lintNode)
+	walk = func(nodes []lintNode) {
+		for i := range nodes {
+			fn(&nodes[i])
+			walk(nodes[i].children)
+		}
+	}
+	walk(t.nodes)
+}
+
+// siblings calls fn for the top-level nodes (with parent nil) and for the children of
+// every container.
+func (t *lintTarget) siblings(fn func(parent *lintNode, nodes []lintNode)) {
+	fn(nil, t.nodes)
+	t.walk(func(n *lintNode) {
+		if len(n.children) > 0 {
+			fn(n, n.children)
+		}
+	})
+}
+
+// ruleSeverity returns a rule's severity under settings: its override, or its default.
+func ruleSeverity(rule lintRule, settings *models.LintSettings) string {
+	if severity, ok := settings.Severities[rule.name]; ok {
+		return severity
+	}
+	return rule.severity
+}
+
+// runLintRules runs every rule in category ("" for all) not turned off in settings over t.
+func runLintRules(t *lintTarget, settings *models.LintSettings, category string) []models.LintIssue {
+	var issues []models.LintIssue
+	for _, rule := range lintRules {
+		severity := ruleSeverity(rule, settings)
+		if severity == utils.LintSeverityOff || (category != "" && rule.category != category) {
+			continue
+		}
+		for _, issue := range rule.check(t) {
+			issue.Rule, issue.Severity = rule.name, severity
+			// A page not created yet has no ID
+			if issue.PageID == nil && t.page != nil && t.page.ID != "" {
+				issue.PageID = &t.page.ID
+			}
+			if issue.Hint == "" {
+				issue.Hint = rule.hint
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// lintPage runs the lint rules in category ("" for all) over a page's current widgets.
+func lintPage(ctx context.Context, page *models.Page, settings *models.LintSettings, category string) (*models.LintReport, error) {
+	ix, err := loadWidgetIndex(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := loadLinkData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lintPageNodes(ctx, page, widgetLintNodes(ix.tree()), links, settings, category)
+}
+
+// lintPageNodes runs the lint rules in category ("" for all) over a page with the given
+// top-level widget nodes, resolving links against links.
+func lintPageNodes(ctx context.Context, page *models.Page, nodes []lintNode, links *linkData, settings *models.LintSettings, category string) (*models.LintReport, error) {
+	theme, err := repository.GetTheme(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t := &lintTarget{page: page, nodes: nodes, links: links, theme: theme}
+	return newLintReport(runLintRules(t, settings, category)), nil
+}
+
+// newPageLintNodes converts the top-level widget definitions of a page not created yet
+// (see CreatePageFromTemplate) to lint nodes, expanding component references. Nodes are
+// named by their path, e.g. "widgets[0].children[1]".
+func newPageLintNodes(ctx context.Context, widgets []models.WidgetNode) ([]lintNode, error) {
+	var componentIDs []string
+	var collect func(n models.WidgetNode)
+	collect = func(n models.WidgetNode) {
+		if n.ComponentID != nil {
+			componentIDs = append(componentIDs, *n.ComponentID)
+		}
+		for _, child := range n.Children {
+			collect(child)
+		}
+	}
+	for _, n := range widgets {
+		collect(n)
+	}
+	components, err := repository.GetComponentsByIDs(ctx, componentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var convert func(nodes []models.WidgetNode, path string) []lintNode
+	convert = func(nodes []models.WidgetNode, path string) []lintNode {
+		result := make([]lintNode, 0, len(nodes))
+		for i, d := range nodes {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			if d.ComponentID != nil && components[*d.ComponentID] != nil {
+				c := components[*d.ComponentID]
+				result = append(result, definitionLintNode(c.Root, nil, &c.ID, p))
+				continue
+			}
+			result = append(result, lintNode{field: p + ".", widgetType: d.Type, config: d.Config, children: convert(d.Children, p+".children")})
+		}
+		return result
+	}
+	return convert(widgets, "widgets"), nil
+}
+
+// validateLintCategory checks that category is "" (all rules) or one of utils.LintCategories.
+func validateLintCategory(category string) error {
+	if category != "" && !utils.LintCategories[category] {
+		return errors.New("category must be layout, content, links or accessibility")
+	}
+	return nil
+}
+
+// LintPage runs the lint rules (see GetLintRules) in category ("" for all) over a page's
+// widgets, including the definitions of the components it uses, with the severities
+// from the lint settings.
+// Returns error if the category is unknown or the page is not found.
+func LintPage(ctx context.Context, id, category string) (*models.LintReport, error) {
+	if err := validateLintCategory(category); err != nil {
+		return nil, err
+	}
+	page, err := repository.GetPageByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+	settings, err := repository.GetLintSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lintPage(ctx, page, settings, category)
+}
+
+// LintApp runs the lint rules in category ("" for all) over every page, over the
+// definitions of components no page uses, and over the navigation.
+// Returns error if the category is unknown.
+func LintApp(ctx context.Context, category string) (*models.LintReport, error) {
+	if err := validateLintCategory(category); err != nil {
+		return nil, err
+	}
+	settings, err := repository.GetLintSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links, err := loadLinkData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	theme, err := repository.GetTheme(ctx)
+	if err != nil {
+		return nil, err
+	}
+	widgets, err := repository.GetAllWidgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	components, err := repository.GetAllComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nav, err := repository.GetNavigation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	componentsByID := make(map[string]*models.Component, len(components))
+	for i := range components {
+		componentsByID[components[i].ID] = &components[i]
+	}
+	widgetsByPage := map[string][]models.Widget{}
+	used := map[string]bool{}
+	for _, w := range widgets {
+		widgetsByPage[w.PageID] = append(widgetsByPage[w.PageID], w)
+		if w.ComponentID != nil {
+			used[*w.ComponentID] = true
+		}
+	}
+
+	var issues []models.LintIssue
+	for i := range links.pages {
+		page := &links.pages[i]
+		ix := newWidgetIndex(widgetsByPage[page.ID])
+		ix.components = componentsByID
+		t := &lintTarget{page: page, nodes: widgetLintNodes(ix.tree()), links: links, theme: theme}
+		issues = append(issues, runLintRules(t, settings, category)...)
+	}
+	for i := range components {
+		c := &components[i]
+		if used[c.ID] {
+			continue
+		}
+		t := &lintTarget{nodes: []lintNode{definitionLintNode(c.Root, nil, &c.ID, "root")}, links: links, theme: theme}
+		issues = append(issues, runLintRules(t, settings, category)...)
+	}
+	issues = append(issues, runLintRules(&lintTarget{links: links, nav: nav, theme: theme}, settings, category)...)
+	return newLintReport(issues), nil
+}
+
+// GetLintRules lists the lint rules with their severities under the lint settings.
+func GetLintRules(ctx context.Context) ([]models.LintRule, error) {
+	settings, err := repository.GetLintSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]models.LintRule, 0, len(lintRules))
+	for _, rule := range lintRules {
+		rules = append(rules, models.LintRule{
+			Name:            rule.name,
+			Description:     rule.description,
+			Category:        rule.category,
+			DefaultSeverity: rule.severity,
+			Severity:        ruleSeverity(rule, settings),
+		})
+	}
+	return rules, nil
+}
+
+// GetLintSettings retrieves the lint settings.
+func GetLintSettings(ctx context.Context) (*models.LintSettings, error) {
+	return repository.GetLintSettings(ctx)
+}
+
+// UpdateLintSettings replaces the lint settings.
+// Business Rules Enforced:
+//   - Severities only name existing rules (see GetLintRules)
+//   - Each severity is error, warning or off
+func UpdateLintSettings(ctx context.Context, settings models.LintSettings) (*models.LintSettings, error) {
+	if settings.Severities == nil {
+		settings.Severities = map[string]string{}
+	}
+	known := make(map[string]bool, len(lintRules))
+	for _, rule := range lintRules {
+		known[rule.name] = true
+	}
+	for name, severity := range settings.Severities {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule %s", name)
+		}
+		switch severity {
+		case utils.LintSeverityError, utils.LintSeverityWarning, utils.LintSeverityOff:
+		default:
+			return nil, fmt.Errorf("severities.%s must be error, warning or off", name)
+		}
+	}
+	return repository.UpdateLintSettings(ctx, settings)
+}
+
+// PageLintError is returned when the publish gate keeps a draft with lint errors from
+// being published.
+type PageLintError struct {
+	// Report is the page's lint report
+	Report *models.LintReport
+}
+
+func (e *PageLintError) Error() string {
+	return "page has lint errors"
+}
+
+// checkPublishGate lints a page about to be published, as it will be saved, when the lint
+// settings turn the publish gate on, and returns a *PageLintError if the page has lint
+// errors. A stored page is linted with its current widgets; a page not created yet (with
+// no ID) with widgets, its top-level widget definitions. Links are resolved as if the page
+// were saved with its route changed from oldRoute ("" if unchanged; see linkData.withPage).
+func checkPublishGate(ctx context.Context, page *models.Page, oldRoute string, widgets []models.WidgetNode) error {
+	settings, err := repository.GetLintSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if !settings.PublishGate {
+		return nil
+	}
+
+	var nodes []lintNode
+	if page.ID == "" {
+		nodes, err = newPageLintNodes(ctx, widgets)
+	} else {
+		var ix *widgetIndex
+		if ix, err = loadWidgetIndex(ctx, page.ID); err == nil {
+			nodes = widgetLintNodes(ix.tree())
+		}
+	}
+	if err != nil {
+		return err
+	}
+	links, err := loadLinkData(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := lintPageNodes(ctx, page, nodes, links.withPage(*page, oldRoute), settings, "")
+	if err != nil {
+		return err
+	}
+	if report.Errors > 0 {
+		return &PageLintError{Report: report}
+	}
+	return nil
+}
+
+// newLintReport sorts issues errors first, keeping their order otherwise, and counts them.
+func newLintReport(issues []models.LintIssue) *models.LintReport {
+	report := &models.LintReport{Issues: []models.LintIssue{}}
+	report.Issues = append(report.Issues, issues...)
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Severity == utils.LintSeverityError && report.Issues[j].Severity != utils.LintSeverityError
+	})
+	for _, issue := range report.Issues {
+		if issue.Severity == utils.LintSeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	return report
+}
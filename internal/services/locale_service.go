@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// localeCodePattern accepts BCP 47 codes in canonical case: a language, an optional
+// script and an optional region ("en", "zh-Hant", "pt-BR", "es-419").
+var localeCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+// GetLocaleSettings retrieves the app's locales and default locale.
+func GetLocaleSettings(ctx context.Context) (*models.LocaleSettings, error) {
+	return repository.GetLocaleSettings(ctx)
+}
+
+// UpdateLocaleSettings validates and replaces the app's locales.
+// Business Rules Enforced:
+//   - At least one locale; codes are BCP 47 in canonical case and not repeated
+//   - The default locale must be one of the locales
+//
+// Removing a locale deletes its translations. Changing the default does not change
+// any content: the base content on pages and widgets is then read as the new default.
+// Returns the stored settings or an error.
+func UpdateLocaleSettings(ctx context.Context, settings models.LocaleSettings) (*models.LocaleSettings, error) {
+	if len(settings.Locales) == 0 {
+		return nil, errors.New("at least one locale is required")
+	}
+
+	seen := make(map[string]bool, len(settings.Locales))
+	for _, code := range settings.Locales {
+		if !localeCodePattern.MatchString(code) {
+			return nil, fmt.Errorf("invalid locale code %q", code)
+		}
+		if seen[code] {
+			return nil, fmt.Errorf("duplicate locale %s", code)
+		}
+		seen[code] = true
+	}
+	if !seen[settings.Default] {
+		return nil, errors.New("default locale must be one of the locales")
+	}
+
+	if err := repository.ReplaceLocaleSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return repository.GetLocaleSettings(ctx)
+}
+
+// GetPageTranslations lists every stored translation of a page and its widgets.
+// Returns error if page not found.
+func GetPageTranslations(ctx context.Context, pageID string) (*models.PageTranslations, error) {
+	if _, err := repository.GetPageByID(ctx, pageID); err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+	return repository.GetPageTranslations(ctx, pageID)
+}
+
+// SetPageTranslation creates or replaces a page's name in a non-default locale.
+// Business Rules Enforced:
+//   - Page must exist and the locale must be one of the app's locales
+//   - The default locale's name is the page's own name and is edited on the page
+//   - Name is required
+func SetPageTranslation(ctx context.Context, pageID, locale, name string) (*models.PageTranslation, error) {
+	if _, err := repository.GetPageByID(ctx, pageID); err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+	if err := checkTranslationLocale(ctx, locale); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	return repository.UpsertPageTranslation(ctx, models.PageTranslation{PageID: pageID, Locale: locale, Name: name})
+}
+
+// DeletePageTranslation removes a page's name translation; reads fall back to the default locale.
+func DeletePageTranslation(ctx context.Context, pageID, locale string) error {
+	deleted, err := repository.DeletePageTranslation(ctx, pageID, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return notFound(ctx, "translation not found")
+	}
+	return nil
+}
+
+// SetWidgetTranslation creates or replaces a widget's translated config fields in a non-default locale.
+// Business Rules Enforced:
+//   - Widget must exist and the locale must be one of the app's locales (not the default)
+//   - Only the widget type's translatable fields (see utils.TranslatableWidgetFields) may be set
+//   - At least one field is required, and values cannot be empty
+func SetWidgetTranslation(ctx context.Context, widgetID, locale string, fields map[string]string) (*models.WidgetTranslation, error) {
+	widget, err := repository.GetWidgetByID(ctx, widgetID)
+	if err != nil {
+		return nil, notFound(ctx, "widget not found")
+	}
+	if err := checkTranslationLocale(ctx, locale); err != nil {
+		return nil, err
+	}
+
+	translatable := utils.TranslatableWidgetFields[widget.Type]
+	if len(translatable) == 0 {
+		return nil, fmt.Errorf("%s widgets have no translatable fields", widget.Type)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+	for _, field := range sortedKeys(fields) {
+		if !slices.Contains(translatable, field) {
+			return nil, fmt.Errorf("field %s is not translatable for %s widgets (allowed: %s)",
+				field, widget.Type, strings.Join(translatable, ", "))
+		}
+		if fields[field] == "" {
+			return nil, fmt.Errorf("field %s cannot be empty", field)
+		}
+	}
+
+	return repository.UpsertWidgetTranslation(ctx, models.WidgetTranslation{WidgetID: widgetID, Locale: locale, Fields: fields})
+}
+
+// DeleteWidgetTranslation removes a widget's translation; reads fall back to the default locale.
+func DeleteWidgetTranslation(ctx context.Context, widgetID, locale string) error {
+	deleted, err := repository.DeleteWidgetTranslation(ctx, widgetID, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return notFound(ctx, "translation not found")
+	}
+	return nil
+}
+
+// GetMissingTranslations reports, for every non-default locale, whether the page name is
+// untranslated and which widget fields have base text but no translation.
+// Returns error if page not found.
+func GetMissingTranslations(ctx context.Context, pageID string) (*models.MissingTranslations, error) {
+	if _, err := repository.GetPageByID(ctx, pageID); err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+
+	settings, err := repository.GetLocaleSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	translations, err := repository.GetPageTranslations(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	ix, err := loadWidgetIndex(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	pageNames := make(map[string]bool)
+	for _, t := range translations.Page {
+		pageNames[t.Locale] = true
+	}
+	widgetFields := make(map[string]map[string]string) // locale+widget ID -> fields
+	for _, t := range translations.Widgets {
+		widgetFields[t.Locale+"/"+t.WidgetID] = t.Fields
+	}
+
+	report := &models.MissingTranslations{
+		PageID:        pageID,
+		DefaultLocale: settings.Default,
+		Locales:       []models.LocaleMissingTranslations{},
+	}
+	for _, locale := range settings.Locales {
+		if locale == settings.Default {
+			continue
+		}
+		missing := models.LocaleMissingTranslations{
+			Locale:   locale,
+			PageName: !pageNames[locale],
+			Widgets:  []models.MissingWidgetFields{},
+		}
+		ix.walk(func(w *models.Widget) {
+			translated := widgetFields[locale+"/"+w.ID]
+			var fields []string
+			for _, field := range utils.TranslatableWidgetFields[w.Type] {
+				if text, _ := w.Config[field].(string); text != "" && translated[field] == "" {
+					fields = append(fields, field)
+				}
+			}
+			if len(fields) > 0 {
+				missing.Widgets = append(missing.Widgets, models.MissingWidgetFields{WidgetID: w.ID, Type: w.Type, Fields: fields})
+			}
+		})
+		missing.Complete = !missing.PageName && len(missing.Widgets) == 0
+		report.Locales = append(report.Locales, missing)
+	}
+	return report, nil
+}
+
+// checkTranslationLocale checks that translations can be stored for locale.
+func checkTranslationLocale(ctx context.Context, locale string) error {
+	settings, err := repository.GetLocaleSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(settings.Locales, locale) {
+		return errors.New("locale not found")
+	}
+	if locale == settings.Default {
+		return errors.New("the default locale is stored on the page and widgets themselves; edit them directly")
+	}
+	return nil
+}
+
+// resolveLocale picks the locale to render a read request in.
+// An explicit opts.Locale must be one of the app's locales; otherwise the
+// Accept-Language header is negotiated, falling back to the default locale.
+func resolveLocale(ctx context.Context, opts models.RenderOptions) (*models.LocaleSettings, string, error) {
+	settings, err := repository.GetLocaleSettings(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if opts.Locale != "" {
+		for _, code := range settings.Locales {
+			if strings.EqualFold(code, opts.Locale) {
+				return settings, code, nil
+			}
+		}
+		return nil, "", errors.New("unsupported locale")
+	}
+	return settings, negotiateLocale(settings, opts.AcceptLanguage), nil
+}
+
+// negotiateLocale matches an Accept-Language header against the app's locales.
+// Language ranges are tried in order of preference (q value); each matches a locale
+// exactly, then by language ("fr-CA" matches "fr", "pt" matches "pt-BR").
+// Returns the default locale if nothing matches.
+func negotiateLocale(settings *models.LocaleSettings, acceptLanguage string) string {
+	type languageRange struct {
+		tag string
+		q   float64
+	}
+
+	var ranges []languageRange
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			ranges = append(ranges, languageRange{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	language := func(code string) string {
+		lang, _, _ := strings.Cut(code, "-")
+		return strings.ToLower(lang)
+	}
+	for _, r := range ranges {
+		if r.tag == "*" {
+			return settings.Default
+		}
+		for _, code := range settings.Locales {
+			if strings.EqualFold(code, r.tag) {
+				return code
+			}
+		}
+		for _, code := range settings.Locales {
+			if language(code) == language(r.tag) {
+				return code
+			}
+		}
+	}
+	return settings.Default
+}
+
+// translateWidgetTree overlays translated fields onto the configs of a nested widget tree.
+// fields maps widget IDs to their translated fields; configs are copied, not modified.
+func translateWidgetTree(widgets []models.Widget, fields map[string]map[string]string) {
+	for i := range widgets {
+		w := &widgets[i]
+		if translated := fields[w.ID]; len(translated) > 0 {
+			config := make(map[string]interface{}, len(w.Config)+len(translated))
+			for key, value := range w.Config {
+				config[key] = value
+			}
+			for key, value := range translated {
+				config[key] = value
+			}
+			w.Config = config
+		}
+		translateWidgetTree(w.Children, fields)
+	}
+}
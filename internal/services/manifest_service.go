@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+)
+
+// GetManifest assembles the app manifest: every page, the home page, the navigation menus,
+// the theme and the available locales. Page names are returned in the requested or
+// negotiated locale (see opts), falling back to the default locale.
+// With opts.Public, only pages visible at opts.At are listed, the home page is only set
+// while it is visible, and navigation items for hidden or deleted pages are left out.
+// A public read with opts.UserID also lists the user's variants in running experiments.
+func GetManifest(ctx context.Context, opts models.RenderOptions) (*models.Manifest, error) {
+	settings, locale, err := resolveLocale(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	if locale != settings.Default {
+		names, err = repository.GetPageNameTranslations(ctx, locale)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := &models.Manifest{Pages: []models.Page{}, Locale: locale, Locales: settings}
+	visible := map[string]bool{}
+	for _, p := range pages {
+		if opts.Public && !pageVisible(p, opts.At) {
+			continue
+		}
+		visible[p.ID] = true
+		if p.IsHome {
+			id := p.ID
+			manifest.HomePageID = &id
+		}
+		if name, ok := names[p.ID]; ok {
+			p.Name = name
+		}
+		manifest.Pages = append(manifest.Pages, p)
+	}
+
+	manifest.Navigation, err = repository.GetNavigation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Public {
+		filterNavigation(manifest.Navigation, visible)
+	}
+	if opts.Public && opts.UserID != "" {
+		manifest.Experiments, err = experimentAssignments(ctx, opts.UserID, visible)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifest.Theme, err = repository.GetTheme(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
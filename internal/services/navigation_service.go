@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// GetNavigation retrieves the app's tab bar and drawer menus.
+// Items whose target page has been deleted are returned with broken=true.
+func GetNavigation(ctx context.Context) (*models.Navigation, error) {
+	return repository.GetNavigation(ctx)
+}
+
+// UpdateNavigation validates and replaces the app's tab bar and drawer menus.
+// Business Rules Enforced:
+//   - Every item needs a label and exactly one target: page_id or url
+//   - Target pages must exist; URLs must be absolute http or https links
+//   - The tab bar holds at most utils.MaxTabBarItems items, each with an icon,
+//     and cannot link the same page twice
+//
+// Returns the stored navigation with page routes filled in.
+func UpdateNavigation(ctx context.Context, nav models.Navigation) (*models.Navigation, error) {
+	if len(nav.TabBar) > utils.MaxTabBarItems {
+		return nil, fmt.Errorf("tab_bar cannot have more than %d items", utils.MaxTabBarItems)
+	}
+
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pageIDs := make(map[string]bool, len(pages))
+	for _, p := range pages {
+		pageIDs[p.ID] = true
+	}
+
+	linkedPages := make(map[string]bool)
+	for i, item := range nav.TabBar {
+		path := fmt.Sprintf("%s[%d]", utils.NavigationTabBar, i)
+		if err := validateNavigationItem(item, path, pageIDs); err != nil {
+			return nil, err
+		}
+		if item.Icon == "" {
+			return nil, fmt.Errorf("%s: icon is required for tab bar items", path)
+		}
+		if item.PageID != nil {
+			if linkedPages[*item.PageID] {
+				return nil, fmt.Errorf("%s: page is already in the tab bar", path)
+			}
+			linkedPages[*item.PageID] = true
+		}
+	}
+	for i, item := range nav.Drawer {
+		if err := validateNavigationItem(item, fmt.Sprintf("%s[%d]", utils.NavigationDrawer, i), pageIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := repository.ReplaceNavigation(ctx, nav); err != nil {
+		return nil, err
+	}
+	return repository.GetNavigation(ctx)
+}
+
+// validateNavigationItem checks one menu item. path names the item in error messages.
+func validateNavigationItem(item models.NavigationItem, path string, pageIDs map[string]bool) error {
+	if item.Label == "" {
+		return fmt.Errorf("%s: label is required", path)
+	}
+	if (item.PageID == nil) == (item.URL == nil) {
+		return fmt.Errorf("%s: exactly one of page_id or url is required", path)
+	}
+
+	if item.PageID != nil {
+		if !pageIDs[*item.PageID] {
+			return fmt.Errorf("%s: page not found", path)
+		}
+		return nil
+	}
+
+	u, err := url.Parse(*item.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s: url must be an absolute http or https URL", path)
+	}
+	return nil
+}
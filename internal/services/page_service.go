@@ -4,82 +4,207 @@
 package services
 
 import (
+	"context"
+	"maps"
+	"slices"
+
 	"appdrop-api/internal/models"
 	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
 	"errors"
 )
 
 // GetPages retrieves all pages from the database.
 // Returns a list of all pages or an error if database operation fails.
-func GetPages() ([]models.Page, error) {
-	return repository.GetAllPages()
+func GetPages(ctx context.Context) ([]models.Page, error) {
+	return repository.GetAllPages(ctx)
 }
 
-func CreatePage(page models.Page) (*models.Page, error) {
+func CreatePage(ctx context.Context, page models.Page) (*models.Page, error) {
 
 	// CreatePage validates and creates a new page.
 	// Business Rules Enforced:
 	//   - Name and route are required (non-empty strings)
-	//   - Route must be globally unique
+	//   - Route is "/" or /-separated literal and :param segments (see pageroute), and
+	//     must not match the same paths as another page's route or overlap it ambiguously
 	//   - If is_home=true, ensures only one home page by resetting others
+	//   - Status is draft or published (default); publish_at is only allowed on drafts
+	//   - visible_until must be after visible_from
+	//   - With the publish gate on (see UpdateLintSettings), a published page is linted
+	//     before it is created and refused with a *PageLintError if it has lint errors
 	// Returns the created page with its UUID or an error.
 
 	if page.Name == "" || page.Route == "" {
 		return nil, errors.New("name and route are required")
 	}
 
-	exists, err := repository.RouteExists(page.Route)
-	if err != nil {
+	if err := validatePageSchedule(&page, ""); err != nil {
+		return nil, err
+	}
+
+	if err := validatePageRoute(ctx, page.Route, ""); err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, errors.New("page route already exists")
+
+	if page.Status == utils.PageStatusPublished {
+		page.ID = ""
+		if err := checkPublishGate(ctx, &page, "", nil); err != nil {
+			return nil, err
+		}
 	}
 
 	if page.IsHome {
-		err := repository.ResetHomePage()
+		err := repository.ResetHomePage(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return repository.CreatePage(page)
+	return repository.CreatePage(ctx, page)
 }
 
-func GetPageWithWidgets(id string) (map[string]interface{}, error) {
+func GetPageWithWidgets(ctx context.Context, id string, opts models.RenderOptions) (map[string]interface{}, error) {
 	// GetPageWithWidgets retrieves a page and all its associated widgets.
-	// Returns a map containing both page details and widgets array.
+	// Returns a map containing the page details, the widget tree (top-level widgets
+	// in order, with container children nested under "children") and the app navigation.
 	// Ensures widgets array is empty array instead of null.
-	page, err := repository.GetPageByID(id)
+	// The page name and translatable widget fields are returned in the requested or
+	// negotiated locale, falling back to the default locale's content.
+	// With opts.Resolve, theme token references in widget configs are replaced by their
+	// values for opts.ColorScheme and bindings by the current store variables and product
+	// fields, asset_id references are expanded under "asset" (with image variants and
+	// srcset) and product_grid configs list their products under "products"; otherwise
+	// configs are returned as stored. Bindings that no longer resolve are left in place.
+	// With opts.Public, drafts and pages outside their visibility window are not found,
+	// and widgets and navigation items hidden at opts.At are left out, as are widgets
+	// whose targeting rule does not match opts.Client. With opts.UserID, a public read of
+	// a page with a running experiment renders the user's variant, reports it under
+	// "experiment" and logs the exposure.
+	page, err := repository.GetPageByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+	if opts.Public && !opts.Preview && !pageVisible(*page, opts.At) {
+		return nil, errors.New("page not found")
+	}
+
+	settings, locale, err := resolveLocale(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The variant decides whose widgets are rendered: the page's own, or an alternate page's
+	var assignment *models.ExperimentAssignment
+	var variant *models.ExperimentVariant
+	widgetPageID := id
+	if opts.Public && opts.UserID != "" {
+		assignment, variant, err = pageExperiment(ctx, id, opts.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if variant != nil && variant.PageID != nil {
+			// Fall back to the page's own widgets if the alternate page was deleted
+			if _, err := repository.GetPageByID(ctx, *variant.PageID); err == nil {
+				widgetPageID = *variant.PageID
+			} else if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	ix, err := loadWidgetIndex(ctx, widgetPageID)
 	if err != nil {
 		return nil, err
 	}
 
-	widgets, err := repository.GetWidgetsByPageID(id)
+	nav, err := repository.GetNavigation(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Ensure empty array instead of null
-	if widgets == nil {
-		widgets = []models.Widget{}
+	// Nest children under their containers; tree() never returns null
+	widgets := ix.tree()
+
+	if opts.Public {
+		widgets = filterPublicWidgets(widgets, opts)
+
+		visible, err := visiblePageIDs(ctx, opts.At)
+		if err != nil {
+			return nil, err
+		}
+		filterNavigation(nav, visible)
+	}
+
+	if locale != settings.Default {
+		names, err := repository.GetPageNameTranslations(ctx, locale)
+		if err != nil {
+			return nil, err
+		}
+		if name, ok := names[page.ID]; ok {
+			page.Name = name
+		}
+
+		translations, err := repository.GetWidgetTranslations(ctx, widgetPageID, locale)
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]map[string]string, len(translations))
+		for _, t := range translations {
+			fields[t.WidgetID] = t.Fields
+		}
+		translateWidgetTree(widgets, fields)
+	}
+
+	// Experiment overrides take precedence over translations
+	if variant != nil && len(variant.Overrides) > 0 {
+		overrides := make(map[string]models.WidgetOverride, len(variant.Overrides))
+		for _, o := range variant.Overrides {
+			overrides[o.WidgetID] = o
+		}
+		widgets = applyOverrides(widgets, overrides)
+	}
+
+	if opts.Resolve {
+		lookup, err := widgetTreeLookup(ctx, widgets, opts.ColorScheme)
+		if err != nil {
+			return nil, err
+		}
+		resolveWidgetTree(widgets, lookup)
+		if err := expandWidgetAssets(ctx, widgets); err != nil {
+			return nil, err
+		}
+		if err := expandProductGrids(ctx, widgets); err != nil {
+			return nil, err
+		}
 	}
 
 	response := map[string]interface{}{
-		"page":    page,
-		"widgets": widgets,
+		"page":       page,
+		"widgets":    widgets,
+		"navigation": nav,
+		"locale":     locale,
+	}
+
+	if assignment != nil {
+		if err := repository.RecordExposure(ctx, assignment.ExperimentID, opts.UserID, assignment.Variant); err != nil {
+			return nil, err
+		}
+		response["experiment"] = assignment
 	}
 
 	return response, nil
 }
 
-func DeletePage(id string) error {
+func DeletePage(ctx context.Context, id string) error {
 	// DeletePage removes a page from the database.
 	// Business Rule: Cannot delete the home page (is_home=true).
+	// Tab bar items linking to the page are removed; drawer items are kept and flagged as broken.
+	// With BlockLinkedPageDeletes, a page other pages' widgets or components link to is kept
+	// and a *PageLinkedError listing the links is returned.
 	// Returns error if page not found or if attempting to delete home page.
-	page, err := repository.GetPageByID(id)
+	page, err := repository.GetPageByID(ctx, id)
 	if err != nil {
-		return errors.New("page not found")
+		return notFound(ctx, "page not found")
 	}
 
 	// Rule: cannot delete home page
@@ -87,17 +212,38 @@ func DeletePage(id string) error {
 		return errors.New("cannot delete home page")
 	}
 
-	return repository.DeletePage(id)
+	if BlockLinkedPageDeletes {
+		links, err := inboundLinks(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(links) > 0 {
+			return &PageLinkedError{Links: links}
+		}
+	}
+
+	return repository.DeletePage(ctx, id)
 }
 
-func UpdatePage(id string, page models.Page) (*models.Page, error) {
+func UpdatePage(ctx context.Context, id string, page models.Page) (*models.Page, error) {
 
 	// UpdatePage modifies an existing page's details.
 	// Business Rules Enforced:
 	//   - Name and route are required (non-empty strings)
 	//   - Page must exist
-	//   - Route must be unique (excluding the current page)
+	//   - Route must be valid and must not clash with another page's route (see CreatePage)
 	//   - If is_home=true, ensures only one home page by resetting others
+	//   - Status is draft or published (omitted keeps the current status);
+	//     publish_at is only allowed on drafts
+	//   - visible_until must be after visible_from
+	//   - With the publish gate on (see UpdateLintSettings), a draft with lint errors
+	//     cannot be published; a *PageLintError holding the lint report is returned. The
+	//     page is linted as updated, with links resolved against its new route
+	// When the route changes, the old route is recorded as an automatic redirect to the
+	// page, replacing redirects from the same paths, unless the new route has parameters
+	// the old one cannot fill in or the redirect breaks a rule of CreateRedirect (e.g.
+	// another page's route covers the old one). Redirects the new route shadows, whose
+	// from_route only matches paths the new route matches, are removed.
 	// Returns the updated page or an error.
 
 	if page.Name == "" || page.Route == "" {
@@ -105,28 +251,44 @@ func UpdatePage(id string, page models.Page) (*models.Page, error) {
 	}
 
 	// check page exists
-	_, err := repository.GetPageByID(id)
+	existing, err := repository.GetPageByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("page not found")
+		return nil, notFound(ctx, "page not found")
 	}
 
-	// route must be unique (excluding same page)
-	exists, err := repository.RouteExistsForOtherPage(page.Route, id)
-	if err != nil {
+	if err := validatePageSchedule(&page, existing.Status); err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, errors.New("page route already exists")
+
+	// route must not clash with another page's (excluding same page)
+	if err := validatePageRoute(ctx, page.Route, id); err != nil {
+		return nil, err
+	}
+
+	page.ID = id
+
+	if existing.Status == utils.PageStatusDraft && page.Status == utils.PageStatusPublished {
+		if err := checkPublishGate(ctx, &page, existing.Route, nil); err != nil {
+			return nil, err
+		}
 	}
 
 	// only one home page rule
 	if page.IsHome {
-		err := repository.ResetHomePage()
+		err := repository.ResetHomePage(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	page.ID = id
-	return repository.UpdatePage(page)
+	// Keep deep links to the old route working
+	if page.Route != existing.Route {
+		links, err := loadLinkData(ctx)
+		if err != nil {
+			return nil, err
+		}
+		deleted, automatic := links.movedRedirects(page, existing.Route)
+		return repository.UpdatePageWithRedirects(ctx, page, slices.Sorted(maps.Keys(deleted)), automatic)
+	}
+	return repository.UpdatePage(ctx, page)
 }
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// Preview link settings, set from configuration at startup.
+var (
+	// PreviewSecret signs preview link tokens
+	PreviewSecret []byte
+	// DefaultPreviewTTL is how long a link is valid when no expiry is requested
+	DefaultPreviewTTL = 24 * time.Hour
+	// MaxPreviewTTL is the longest validity a link can be issued with
+	MaxPreviewTTL = 7 * 24 * time.Hour
+)
+
+// maxPreviewLabelLength is the longest label a preview link can have.
+const maxPreviewLabelLength = 200
+
+// CreatePreviewLink issues a signed preview link for a page.
+// Business Rules Enforced:
+//   - Page must exist (drafts included)
+//   - expiresIn is a Go duration ("48h"); empty uses DefaultPreviewTTL, and it must be
+//     positive and at most MaxPreviewTTL
+//   - Label is at most 200 characters
+//
+// Returns the link with its token.
+func CreatePreviewLink(ctx context.Context, pageID, label, expiresIn string) (*models.PreviewLink, error) {
+	if _, err := repository.GetPageByID(ctx, pageID); err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+
+	ttl := DefaultPreviewTTL
+	if expiresIn != "" {
+		d, err := time.ParseDuration(expiresIn)
+		if err != nil {
+			return nil, errors.New("expires_in must be a duration such as \"48h\"")
+		}
+		ttl = d
+	}
+	if ttl <= 0 || ttl > MaxPreviewTTL {
+		return nil, fmt.Errorf("expires_in must be positive and at most %s", MaxPreviewTTL)
+	}
+
+	label = strings.TrimSpace(label)
+	if len(label) > maxPreviewLabelLength {
+		return nil, fmt.Errorf("label cannot be longer than %d characters", maxPreviewLabelLength)
+	}
+
+	// Tokens carry the expiry in whole seconds; store the same instant
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	link, err := repository.CreatePreviewLink(ctx, pageID, label, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	signPreviewLink(link)
+	return link, nil
+}
+
+// GetPreviewLinks lists a page's active links (not revoked or expired), with their tokens.
+func GetPreviewLinks(ctx context.Context, pageID string) ([]models.PreviewLink, error) {
+	if _, err := repository.GetPageByID(ctx, pageID); err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+
+	links, err := repository.GetActivePreviewLinks(ctx, pageID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if links == nil {
+		links = []models.PreviewLink{}
+	}
+	for i := range links {
+		signPreviewLink(&links[i])
+	}
+	return links, nil
+}
+
+// RevokePreviewLink stops a page's preview link from working.
+// Returns "preview link not found" for a link ID that is not a UUID.
+func RevokePreviewLink(ctx context.Context, pageID, linkID string) error {
+	if _, err := repository.GetPageByID(ctx, pageID); err != nil {
+		return notFound(ctx, "page not found")
+	}
+	if !utils.IsUUID(linkID) {
+		return errors.New("preview link not found")
+	}
+
+	revoked, err := repository.RevokePreviewLink(ctx, pageID, linkID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("preview link not found")
+	}
+	return nil
+}
+
+// GetPreviewPage renders the page a preview token opens, regardless of its status and
+// visibility window. Widgets are filtered as on other public reads (see GetPageWithWidgets).
+// The response adds "preview" with the link's ID and expiry.
+// Returns "invalid preview link" for tokens that are malformed, forged, expired or revoked.
+func GetPreviewPage(ctx context.Context, token string, opts models.RenderOptions) (map[string]interface{}, error) {
+	linkID, expiresAt, ok := verifyPreviewToken(token)
+	if !ok || !time.Now().Before(expiresAt) {
+		return nil, errors.New("invalid preview link")
+	}
+
+	link, err := repository.GetPreviewLinkByID(ctx, linkID)
+	if err != nil {
+		return nil, notFound(ctx, "invalid preview link")
+	}
+	if link.RevokedAt != nil || !link.ExpiresAt.Equal(expiresAt) {
+		return nil, errors.New("invalid preview link")
+	}
+
+	opts.Public = true
+	opts.Preview = true
+	data, err := GetPageWithWidgets(ctx, link.PageID, opts)
+	if err != nil {
+		return nil, err
+	}
+	data["preview"] = map[string]interface{}{"link_id": link.ID, "expires_at": link.ExpiresAt}
+	return data, nil
+}
+
+// signPreviewLink fills in a link's token and preview path.
+// A token is "<payload>.<signature>", both base64url: the payload is
+// "<link ID>.<expiry in Unix seconds>" and the signature its HMAC-SHA256 with PreviewSecret.
+func signPreviewLink(link *models.PreviewLink) {
+	payload := link.ID + "." + strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	link.Token = base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(previewSignature(payload))
+	link.Path = "/preview/" + link.Token
+}
+
+// verifyPreviewToken checks a token's signature and returns the link ID and expiry it carries.
+func verifyPreviewToken(token string) (string, time.Time, bool) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return "", time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, previewSignature(string(payload))) {
+		return "", time.Time{}, false
+	}
+
+	linkID, expiry, found := strings.Cut(string(payload), ".")
+	if !found {
+		return "", time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return linkID, time.Unix(seconds, 0), true
+}
+
+func previewSignature(payload string) []byte {
+	mac := hmac.New(sha256.New, PreviewSecret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"appdrop-api/internal/repository"
+)
+
+// StartPublishJob runs the scheduled-publish job in the background: every interval it
+// publishes the drafts whose publish_at has passed. A failed run is reported and retried
+// on the next tick. The job stops when ctx is cancelled; the returned channel is closed
+// once it has stopped, so the caller can wait for it before closing the database pool.
+func StartPublishJob(ctx context.Context, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			publishDuePages(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
+
+// publishDuePages runs one pass of the publish job against the server clock.
+// With the publish gate on, drafts with lint errors are not published; their publish_at
+// is cleared so they are reported once rather than on every pass.
+func publishDuePages(ctx context.Context) {
+	ids, err := publishDue(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Fprintln(os.Stderr, "publish job:", err)
+		}
+		return
+	}
+	for _, id := range ids {
+		fmt.Println("publish job: published page " + id)
+	}
+}
+
+// publishDue publishes the drafts due at now and returns their IDs.
+func publishDue(ctx context.Context, now time.Time) ([]string, error) {
+	settings, err := repository.GetLintSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.PublishGate {
+		return repository.PublishDuePages(ctx, now)
+	}
+
+	pages, err := repository.GetDuePages(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for i := range pages {
+		report, err := lintPage(ctx, &pages[i], settings, "")
+		if err != nil {
+			return ids, err
+		}
+		if report.Errors > 0 {
+			if err := repository.UnschedulePage(ctx, pages[i].ID); err != nil {
+				return ids, err
+			}
+			fmt.Fprintf(os.Stderr, "publish job: page %s not published: %d lint errors; publish_at cleared\n", pages[i].ID, report.Errors)
+			continue
+		}
+		published, err := repository.PublishScheduledPage(ctx, pages[i].ID)
+		if err != nil {
+			return ids, err
+		}
+		if published {
+			ids = append(ids, pages[i].ID)
+		}
+	}
+	return ids, nil
+}
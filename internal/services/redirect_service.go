@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/pageroute"
+	"appdrop-api/internal/repository"
+)
+
+// GetRedirects lists every redirect, automatic ones included, ordered by from_route.
+// Returns an empty list (never nil) when there are none.
+func GetRedirects(ctx context.Context) ([]models.Redirect, error) {
+	redirects, err := repository.GetRedirects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if redirects == nil {
+		redirects = []models.Redirect{}
+	}
+	return redirects, nil
+}
+
+// GetRedirect retrieves a single redirect by ID.
+// Returns error if redirect not found.
+func GetRedirect(ctx context.Context, id string) (*models.Redirect, error) {
+	redirect, err := repository.GetRedirectByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "redirect not found")
+	}
+	return redirect, nil
+}
+
+// CreateRedirect validates and saves a manual redirect.
+// Business Rules Enforced:
+//   - from_route is a valid route pattern (see pageroute) that is not a page's route
+//     and matches some path no page route matches (a redirect from "/p/sale" next to a
+//     page at "/p/:slug" would never be followed); it must not match the same paths as
+//     another redirect's from_route, or overlap one ambiguously. A from_route that only
+//     partly overlaps page routes, like "/p/:slug" next to "/p/sale", is allowed: the page
+//     wins for the paths it matches
+//   - Exactly one of page_id (an existing page) or to_route (a valid route pattern)
+//   - Every parameter of the target route can be filled in from from_route, by name or
+//     by position
+//   - Following redirects from to_route must not lead back to this redirect
+//
+// Returns the created redirect with its UUID or an error.
+func CreateRedirect(ctx context.Context, redirect models.Redirect) (*models.Redirect, error) {
+	if err := validateRedirect(ctx, redirect, ""); err != nil {
+		return nil, err
+	}
+	return repository.CreateRedirect(ctx, redirect)
+}
+
+// UpdateRedirect validates and replaces a redirect's routes and target, with the same
+// rules as CreateRedirect. An edited automatic redirect becomes a manual one.
+// Returns the updated redirect or an error.
+func UpdateRedirect(ctx context.Context, id string, redirect models.Redirect) (*models.Redirect, error) {
+	if _, err := repository.GetRedirectByID(ctx, id); err != nil {
+		return nil, notFound(ctx, "redirect not found")
+	}
+	if err := validateRedirect(ctx, redirect, id); err != nil {
+		return nil, err
+	}
+	redirect.ID = id
+	return repository.UpdateRedirect(ctx, redirect)
+}
+
+// DeleteRedirect removes a redirect. Links to its from_route stop resolving.
+func DeleteRedirect(ctx context.Context, id string) error {
+	if _, err := repository.GetRedirectByID(ctx, id); err != nil {
+		return notFound(ctx, "redirect not found")
+	}
+	return repository.DeleteRedirect(ctx, id)
+}
+
+// validateRedirect checks a redirect against the rules of CreateRedirect.
+// excludeID is the redirect being updated ("" for none).
+func validateRedirect(ctx context.Context, redirect models.Redirect, excludeID string) error {
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return err
+	}
+	redirects, err := repository.GetRedirects(ctx)
+	if err != nil {
+		return err
+	}
+	others := make([]models.Redirect, 0, len(redirects))
+	for _, r := range redirects {
+		if r.ID != excludeID {
+			others = append(others, r)
+		}
+	}
+	return checkRedirect(redirect, pages, others)
+}
+
+// checkRedirect checks a redirect against the rules of CreateRedirect, given every page
+// and the other redirects.
+func checkRedirect(redirect models.Redirect, pages []models.Page, others []models.Redirect) error {
+	if redirect.FromRoute == "" {
+		return errors.New("from_route is required")
+	}
+	from, err := pageroute.Parse(redirect.FromRoute)
+	if err != nil {
+		return fmt.Errorf("from_route: %v", err)
+	}
+	if (redirect.PageID == nil) == (redirect.ToRoute == nil) {
+		return errors.New("exactly one of page_id or to_route is required")
+	}
+
+	var target *pageroute.Pattern
+	targetFound := false
+	for _, p := range pages {
+		isTarget := redirect.PageID != nil && p.ID == *redirect.PageID
+		targetFound = targetFound || isTarget
+		pattern, err := pageroute.Parse(p.Route)
+		if err != nil {
+			if p.Route == redirect.FromRoute {
+				return errors.New("from_route is a page route; page routes take precedence over redirects")
+			}
+			continue
+		}
+		if pattern.Shape() == from.Shape() {
+			return errors.New("from_route is a page route; page routes take precedence over redirects")
+		}
+		// The redirect would never be followed
+		if pageroute.Within(from, pattern) {
+			return fmt.Errorf("from_route only matches paths of page route %s; page routes take precedence over redirects", p.Route)
+		}
+		if isTarget {
+			target = pattern
+		}
+	}
+
+	if redirect.PageID != nil {
+		if !targetFound {
+			return errors.New("page_id must reference an existing page")
+		}
+		// A page route that predates route syntax checks has no parameters
+		if target != nil && !carriesParams(from, target) {
+			return errors.New("the page's route has parameters from_route cannot fill in")
+		}
+	} else {
+		target, err = pageroute.Parse(*redirect.ToRoute)
+		if err != nil {
+			return fmt.Errorf("to_route: %v", err)
+		}
+		if !carriesParams(from, target) {
+			return errors.New("to_route has parameters from_route cannot fill in")
+		}
+	}
+
+	for _, r := range others {
+		pattern, err := pageroute.Parse(r.FromRoute)
+		if err != nil {
+			if r.FromRoute == redirect.FromRoute {
+				return errors.New("redirect from_route already exists")
+			}
+			continue
+		}
+		if !pageroute.Conflicts(from, pattern) {
+			continue
+		}
+		if from.Shape() == pattern.Shape() {
+			return errors.New("redirect from_route already exists")
+		}
+		return errors.New("redirect from_route is ambiguous with an existing redirect")
+	}
+
+	if redirect.ToRoute != nil && redirectLoops(from, target, others) {
+		return errors.New("redirect would create a loop")
+	}
+	return nil
+}
+
+// redirectLoops reports whether following a redirect from one route to another could
+// lead back to from. A redirect to a route leads on to every redirect whose from_route
+// overlaps it; redirects to pages end a chain, since page routes take precedence.
+func redirectLoops(from, to *pageroute.Pattern, others []models.Redirect) bool {
+	type edge struct{ from, to *pageroute.Pattern }
+	var edges []edge
+	for _, r := range others {
+		if r.ToRoute == nil {
+			continue
+		}
+		f, err := pageroute.Parse(r.FromRoute)
+		if err != nil {
+			continue
+		}
+		t, err := pageroute.Parse(*r.ToRoute)
+		if err != nil {
+			continue
+		}
+		edges = append(edges, edge{f, t})
+	}
+
+	visited := make([]bool, len(edges))
+	var visit func(route *pageroute.Pattern) bool
+	visit = func(route *pageroute.Pattern) bool {
+		if pageroute.Overlaps(route, from) {
+			return true
+		}
+		for i, e := range edges {
+			if !visited[i] && pageroute.Overlaps(route, e.from) {
+				visited[i] = true
+				if visit(e.to) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return visit(to)
+}
+
+// carriesParams reports whether a redirect from one route can fill in every parameter
+// of the route it leads to (see pageroute.Pattern.Build).
+func carriesParams(from, to *pageroute.Pattern) bool {
+	names := from.Params()
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		values[name] = name
+	}
+	_, ok := to.Build(values, names)
+	return ok
+}
+
+// routeChangeRedirects reports whether a page's old route can redirect to its new one.
+// An old route saved before route syntax was checked has no parameters.
+func routeChangeRedirects(oldRoute, newRoute string) bool {
+	to, err := pageroute.Parse(newRoute)
+	if err != nil {
+		return false
+	}
+	from, err := pageroute.Parse(oldRoute)
+	if err != nil {
+		from, _ = pageroute.Parse("/")
+	}
+	return carriesParams(from, to)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"appdrop-api/internal/models"
+)
+
+// referencePattern matches a {{namespace.path}} reference inside a config string value.
+var referencePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// configReference is one {{...}} reference found in a widget config.
+type configReference struct {
+	// Field is where the reference appears, e.g. "title_color" or "items[0].label"
+	Field string
+	// Path is the referenced value, e.g. "color.primary"
+	Path string
+}
+
+// namespace returns the first segment of the reference path ("color" for "color.primary").
+func (r configReference) namespace() string {
+	namespace, _, _ := strings.Cut(r.Path, ".")
+	return namespace
+}
+
+// referenceLookup returns the value for a reference path, or false if it cannot be resolved.
+type referenceLookup func(path string) (interface{}, bool)
+
+// findReferences lists every reference in a config, walking nested objects and arrays.
+func findReferences(config map[string]interface{}) []configReference {
+	var refs []configReference
+	var walk func(field string, value interface{})
+	walk = func(field string, value interface{}) {
+		switch v := value.(type) {
+		case string:
+			for _, match := range referencePattern.FindAllStringSubmatch(v, -1) {
+				refs = append(refs, configReference{Field: field, Path: match[1]})
+			}
+		case map[string]interface{}:
+			for key, child := range v {
+				childField := key
+				if field != "" {
+					childField = field + "." + key
+				}
+				walk(childField, child)
+			}
+		case []interface{}:
+			for i, child := range v {
+				walk(fmt.Sprintf("%s[%d]", field, i), child)
+			}
+		}
+	}
+	walk("", config)
+	return refs
+}
+
+// resolveReferences returns a copy of config with every resolvable reference substituted.
+// A string that consists of a single reference takes the referenced value as-is
+// (so "{{spacing.md}}" becomes the number 16); references embedded in longer text
+// are substituted as text. Unresolvable references are left in place.
+// The input config is not modified.
+func resolveReferences(config map[string]interface{}, lookup referenceLookup) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	return resolveValue(config, lookup).(map[string]interface{})
+}
+
+func resolveValue(value interface{}, lookup referenceLookup) interface{} {
+	switch v := value.(type) {
+	case string:
+		if match := referencePattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			if resolved, ok := lookup(match[1]); ok {
+				return resolved
+			}
+			return v
+		}
+		return referencePattern.ReplaceAllStringFunc(v, func(ref string) string {
+			path := referencePattern.FindStringSubmatch(ref)[1]
+			if resolved, ok := lookup(path); ok {
+				return fmt.Sprint(resolved)
+			}
+			return ref
+		})
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			resolved[key] = resolveValue(child, lookup)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, child := range v {
+			resolved[i] = resolveValue(child, lookup)
+		}
+		return resolved
+	default:
+		return v
+	}
+}
+
+// resolveWidgetTree substitutes references in the configs of a nested widget tree,
+// including the definitions of expanded components. Widgets are modified in place;
+// expanded components are copied since they may be shared between widgets.
+func resolveWidgetTree(widgets []models.Widget, lookup referenceLookup) {
+	for i := range widgets {
+		w := &widgets[i]
+		w.Config = resolveReferences(w.Config, lookup)
+		if w.Component != nil {
+			component := *w.Component
+			component.Root = resolveNode(component.Root, lookup)
+			w.Component = &component
+		}
+		resolveWidgetTree(w.Children, lookup)
+	}
+}
+
+// resolveNode returns a copy of a WidgetNode tree with references substituted.
+func resolveNode(n models.WidgetNode, lookup referenceLookup) models.WidgetNode {
+	n.Config = resolveReferences(n.Config, lookup)
+	if n.Children != nil {
+		children := make([]models.WidgetNode, len(n.Children))
+		for i, child := range n.Children {
+			children[i] = resolveNode(child, lookup)
+		}
+		n.Children = children
+	}
+	return n
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/pageroute"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// ResolvePath finds the page whose route matches an app path, such as a deep link
+// "/products/123", and returns it with the route's parameter values.
+// When several routes match, the most specific one wins (see pageroute). A path that
+// matches no page route follows the most specific matching redirect, up to
+// utils.MaxRedirectHops times; the redirects followed are reported in the result.
+// With opts.Public, only pages visible at opts.At are considered.
+// Returns error if the path is malformed, no page matches or redirects go on too long.
+func ResolvePath(ctx context.Context, path string, opts models.RenderOptions) (*models.RouteMatch, error) {
+	if _, ok := pageroute.SplitPath(path); !ok {
+		return nil, errors.New("path must start with / and have no empty segments")
+	}
+
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	redirects, err := repository.GetRedirects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resolveRoute(pages, redirects, path, opts)
+}
+
+// resolveRoute resolves an app path against the given pages and redirects (see ResolvePath).
+func resolveRoute(pages []models.Page, redirects []models.Redirect, path string, opts models.RenderOptions) (*models.RouteMatch, error) {
+	pagesByID := make(map[string]*models.Page, len(pages))
+	for i := range pages {
+		pagesByID[pages[i].ID] = &pages[i]
+	}
+
+	hops := []models.RedirectHop{}
+	for {
+		// Page routes take precedence over redirects
+		var page *models.Page
+		var params map[string]string
+		best := -1
+		for i := range pages {
+			if opts.Public && !pageVisible(pages[i], opts.At) {
+				continue
+			}
+			if values, specificity, ok := matchRoute(pages[i].Route, path); ok && specificity > best {
+				page, params, best = &pages[i], values, specificity
+			}
+		}
+		if page != nil {
+			return &models.RouteMatch{Page: *page, Params: params, Path: path, Redirects: hops}, nil
+		}
+
+		var redirect *models.Redirect
+		best = -1
+		for i := range redirects {
+			if values, specificity, ok := matchRoute(redirects[i].FromRoute, path); ok && specificity > best {
+				redirect, params, best = &redirects[i], values, specificity
+			}
+		}
+		if redirect == nil {
+			return nil, errors.New("no page matches the path")
+		}
+		if len(hops) == utils.MaxRedirectHops {
+			return nil, errors.New("too many redirects")
+		}
+		target, ok := redirectTarget(*redirect, params, pagesByID)
+		if !ok {
+			return nil, errors.New("no page matches the path")
+		}
+		hops = append(hops, models.RedirectHop{RedirectID: redirect.ID, From: path, To: target})
+		path = target
+	}
+}
+
+// matchRoute matches an app path against a page or redirect route and returns the
+// parameter values and the route's specificity.
+func matchRoute(route, path string) (map[string]string, int, bool) {
+	pattern, err := pageroute.Parse(route)
+	if err != nil {
+		// Routes saved before route syntax was checked can only match literally
+		parts, ok := pageroute.SplitPath(route)
+		if !ok || route != path {
+			return nil, 0, false
+		}
+		return map[string]string{}, len(parts), true
+	}
+	params, ok := pattern.Match(path)
+	return params, pattern.Specificity(), ok
+}
+
+// redirectTarget returns the path a redirect sends a matched path to: its page's current
+// route or its to_route, with the parameters carried over (see pageroute.Pattern.Build).
+// Returns false if the page no longer exists or a parameter has no value.
+func redirectTarget(r models.Redirect, params map[string]string, pages map[string]*models.Page) (string, bool) {
+	route := ""
+	if r.PageID != nil {
+		page := pages[*r.PageID]
+		if page == nil {
+			return "", false
+		}
+		route = page.Route
+	} else if r.ToRoute != nil {
+		route = *r.ToRoute
+	}
+
+	target, err := pageroute.Parse(route)
+	if err != nil {
+		// Routes saved before route syntax was checked have no parameters
+		return route, true
+	}
+	var ordered []string
+	if from, err := pageroute.Parse(r.FromRoute); err == nil {
+		for _, name := range from.Params() {
+			ordered = append(ordered, params[name])
+		}
+	}
+	return target.Build(params, ordered)
+}
+
+// validatePageRoute checks a page route's syntax (see pageroute.Parse) and that no other
+// page's route matches the same paths or overlaps it ambiguously (see pageroute.Conflicts).
+// excludeID is the page being updated, so it may keep its own route ("" for none).
+func validatePageRoute(ctx context.Context, route, excludeID string) error {
+	pattern, err := pageroute.Parse(route)
+	if err != nil {
+		return err
+	}
+
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range pages {
+		if p.ID == excludeID {
+			continue
+		}
+		other, err := pageroute.Parse(p.Route)
+		if err != nil {
+			// Routes saved before route syntax was checked only clash when equal
+			if p.Route == route {
+				return errors.New("page route already exists")
+			}
+			continue
+		}
+		if !pageroute.Conflicts(pattern, other) {
+			continue
+		}
+		if pattern.Shape() == other.Shape() {
+			return errors.New("page route already exists")
+		}
+		return errors.New("page route is ambiguous with an existing route")
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"appdrop-api/internal/models"
+)
+
+func TestResolveRoutePageRoutesWinOverRedirects(t *testing.T) {
+	pages := []models.Page{
+		{ID: "sale", Route: "/p/sale"},
+		{ID: "product", Route: "/products/:slug"},
+	}
+	toRoute := "/products/:slug"
+	redirects := []models.Redirect{
+		// Partly shadowed: /p/sale is a page, every other /p/... path is redirected
+		{ID: "r1", FromRoute: "/p/:slug", ToRoute: &toRoute},
+	}
+
+	tests := []struct {
+		path     string
+		pageID   string
+		resolved string
+		hops     int
+	}{
+		{"/p/sale", "sale", "/p/sale", 0},
+		{"/p/shirt", "product", "/products/shirt", 1},
+		{"/products/shirt", "product", "/products/shirt", 0},
+	}
+	for _, tt := range tests {
+		match, err := resolveRoute(pages, redirects, tt.path, models.RenderOptions{})
+		if err != nil {
+			t.Errorf("resolveRoute(%q): %v", tt.path, err)
+			continue
+		}
+		if match.Page.ID != tt.pageID || match.Path != tt.resolved || len(match.Redirects) != tt.hops {
+			t.Errorf("resolveRoute(%q) = page %s at %s after %d redirects, want page %s at %s after %d",
+				tt.path, match.Page.ID, match.Path, len(match.Redirects), tt.pageID, tt.resolved, tt.hops)
+		}
+	}
+
+	if _, err := resolveRoute(pages, redirects, "/other", models.RenderOptions{}); err == nil || err.Error() != "no page matches the path" {
+		t.Errorf("resolveRoute(/other) error = %v, want no page matches the path", err)
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/targeting"
+)
+
+// ValidateTargetingRule checks a targeting rule without saving it, for editors that
+// want to report problems as the rule is typed.
+func ValidateTargetingRule(rule string) models.TargetingValidation {
+	if _, err := targeting.Parse(rule); err != nil {
+		var ruleErr *targeting.Error
+		if errors.As(err, &ruleErr) {
+			return models.TargetingValidation{Error: &models.TargetingError{Message: ruleErr.Msg, Position: ruleErr.Pos}}
+		}
+		return models.TargetingValidation{Error: &models.TargetingError{Message: err.Error(), Position: 1}}
+	}
+	return models.TargetingValidation{Valid: true}
+}
+
+// normalizeTargeting checks a widget's targeting rule. A blank rule is stored as no rule.
+func normalizeTargeting(widget *models.Widget) error {
+	if widget.Targeting == nil {
+		return nil
+	}
+	if strings.TrimSpace(*widget.Targeting) == "" {
+		widget.Targeting = nil
+		return nil
+	}
+	if _, err := targeting.Parse(*widget.Targeting); err != nil {
+		return fmt.Errorf("invalid targeting rule: %v", err)
+	}
+	return nil
+}
+
+// widgetTargeted reports whether a widget's targeting rule matches the client.
+// Widgets without a rule are shown to everyone; rules are checked when saved, so a
+// rule that no longer parses hides the widget rather than showing it to everyone.
+func widgetTargeted(widget models.Widget, client targeting.Context) bool {
+	if widget.Targeting == nil {
+		return true
+	}
+	rule, err := targeting.Parse(*widget.Targeting)
+	if err != nil {
+		return false
+	}
+	return rule.Match(client)
+}
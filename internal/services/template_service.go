@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// GetTemplates lists templates matching filter, built-in templates first.
+// Returns an empty list (never nil) when nothing matches.
+func GetTemplates(ctx context.Context, filter models.TemplateFilter) ([]models.Template, error) {
+	filter.Category = strings.TrimSpace(filter.Category)
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	templates, err := repository.GetTemplates(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []models.Template{}
+	}
+	return templates, nil
+}
+
+// GetTemplate retrieves a single template by ID.
+// Returns error if template not found.
+func GetTemplate(ctx context.Context, id string) (*models.Template, error) {
+	template, err := repository.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "template not found")
+	}
+	return template, nil
+}
+
+// CreateTemplate validates and saves a page template.
+// Business Rules Enforced:
+//   - Name and category are required; name must be unique
+//   - Exactly one of template.Widgets or sourcePageID must be given; a source page's
+//     current widget tree is copied, component references included
+//   - The widgets must be valid definitions (types, container rules, depth, existing components)
+//     and reference only existing theme tokens, store variables, products and assets
+//   - Product grids follow the product_grid catalog rules (see validateProductGrid)
+//
+// Returns the created template with its UUID or an error.
+func CreateTemplate(ctx context.Context, template models.Template, sourcePageID *string) (*models.Template, error) {
+	if template.Name == "" || template.Category == "" {
+		return nil, errors.New("name and category are required")
+	}
+	if (template.Widgets == nil) == (sourcePageID == nil) {
+		return nil, errors.New("exactly one of widgets or source_page_id is required")
+	}
+
+	exists, err := repository.TemplateNameExists(ctx, template.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("template name already exists")
+	}
+
+	if sourcePageID != nil {
+		if _, err := repository.GetPageByID(ctx, *sourcePageID); err != nil {
+			return nil, notFound(ctx, "source page not found")
+		}
+		ix, err := loadWidgetIndex(ctx, *sourcePageID)
+		if err != nil {
+			return nil, err
+		}
+		template.Widgets = make([]models.WidgetNode, 0, len(ix.children[""]))
+		for _, id := range ix.children[""] {
+			template.Widgets = append(template.Widgets, ix.node(id))
+		}
+	}
+
+	if err := validateDefinition(ctx, template.Widgets, "widgets"); err != nil {
+		return nil, err
+	}
+
+	if err := validateDefinitionReferences(ctx, template.Widgets, "widgets"); err != nil {
+		return nil, err
+	}
+
+	return repository.CreateTemplate(ctx, template)
+}
+
+// DeleteTemplate removes a template from the library.
+// Business Rule: Built-in starter templates cannot be deleted.
+// Pages previously created from the template are unaffected.
+// Returns error if template not found or built in.
+func DeleteTemplate(ctx context.Context, id string) error {
+	template, err := repository.GetTemplateByID(ctx, id)
+	if err != nil {
+		return notFound(ctx, "template not found")
+	}
+	if template.IsBuiltin {
+		return errors.New("cannot delete built-in template")
+	}
+
+	return repository.DeleteTemplate(ctx, id)
+}
+
+// CreatePageFromTemplate creates a new page and fills it with a copy of a template's widgets.
+// Business Rules Enforced:
+//   - Template must exist
+//   - The same page rules as CreatePage (name and route required, unique route, single
+//     home page, publish gate); the publish gate lints the template's widgets
+//   - The template must still be valid; e.g., a component it references may have been
+//     changed so that it no longer fits, or a theme token, store variable, product or
+//     asset it uses may have been removed, or a product grid may list a deleted
+//     collection or product
+//
+// The page and all widgets are created in one transaction.
+// Returns the new page with its widget tree, rendered with opts like GetPageWithWidgets.
+func CreatePageFromTemplate(ctx context.Context, templateID string, page models.Page, opts models.RenderOptions) (map[string]interface{}, error) {
+	template, err := repository.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, notFound(ctx, "template not found")
+	}
+
+	if page.Name == "" || page.Route == "" {
+		return nil, errors.New("name and route are required")
+	}
+
+	if err := validatePageSchedule(&page, ""); err != nil {
+		return nil, err
+	}
+
+	// Check the render options before anything is written
+	if _, _, err := resolveLocale(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	if err := validatePageRoute(ctx, page.Route, ""); err != nil {
+		return nil, err
+	}
+
+	if err := validateDefinition(ctx, template.Widgets, "template widgets"); err != nil {
+		return nil, err
+	}
+
+	if err := validateDefinitionReferences(ctx, template.Widgets, "template widgets"); err != nil {
+		return nil, err
+	}
+
+	if page.Status == utils.PageStatusPublished {
+		page.ID = ""
+		if err := checkPublishGate(ctx, &page, "", template.Widgets); err != nil {
+			return nil, err
+		}
+	}
+
+	if page.IsHome {
+		err := repository.ResetHomePage(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	createdPage, err := repository.CreatePageWithWidgets(ctx, page, template.Widgets)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetPageWithWidgets(ctx, createdPage.ID, opts)
+}
+
+// validateDefinitionReferences checks the theme tokens, bindings and assets referenced by
+// a list of top-level widget definitions, and their product grids' catalog fields (see
+// validateProductGrid). path names the list in error messages.
+func validateDefinitionReferences(ctx context.Context, nodes []models.WidgetNode, path string) error {
+	theme, err := repository.GetTheme(ctx)
+	if err != nil {
+		return err
+	}
+	for i, n := range nodes {
+		if err := validateNodeThemeReferences(n, theme, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+		if err := validateNodeBindings(ctx, n, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+		if err := validateNodeAssetReferences(ctx, n, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+		if err := validateNodeProductGrids(ctx, n, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+var (
+	// tokenNamePattern restricts token names so they can be used in {{...}} references
+	tokenNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	// hexColorPattern accepts #RGB, #RRGGBB and #RRGGBBAA colors
+	hexColorPattern = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})$`)
+)
+
+// GetTheme retrieves the app theme.
+func GetTheme(ctx context.Context) (*models.Theme, error) {
+	return repository.GetTheme(ctx)
+}
+
+// UpdateTheme validates and replaces the app theme.
+// Business Rules Enforced:
+//   - Token names are lowercase letters, digits and underscores, starting with a letter
+//   - Colors are hex values (#RGB, #RRGGBB or #RRGGBBAA)
+//   - Every dark-mode color overrides an existing color token
+//   - Spacing and radius sizes are not negative; font sizes are positive and
+//     font weights are 100 to 900 in steps of 100
+//   - Tokens still referenced by a widget or component cannot be removed
+//
+// Returns the stored theme or an error.
+func UpdateTheme(ctx context.Context, theme models.Theme) (*models.Theme, error) {
+	normalizeTheme(&theme)
+	if err := validateTheme(theme); err != nil {
+		return nil, err
+	}
+
+	// Every existing reference must still resolve against the new theme
+	widgets, err := repository.GetWidgetsWithReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range widgets {
+		if err := validateThemeReferences(w.Config, &theme, "widget "+w.ID); err != nil {
+			return nil, fmt.Errorf("theme change would break existing content: %v", err)
+		}
+	}
+	components, err := repository.GetAllComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range components {
+		if err := validateNodeThemeReferences(c.Root, &theme, "component "+c.Name); err != nil {
+			return nil, fmt.Errorf("theme change would break existing content: %v", err)
+		}
+	}
+
+	return repository.UpdateTheme(ctx, theme)
+}
+
+// normalizeTheme replaces missing token groups with empty ones.
+func normalizeTheme(theme *models.Theme) {
+	if theme.Colors == nil {
+		theme.Colors = map[string]string{}
+	}
+	if theme.Typography == nil {
+		theme.Typography = map[string]models.TypographyToken{}
+	}
+	if theme.Spacing == nil {
+		theme.Spacing = map[string]float64{}
+	}
+	if theme.Radius == nil {
+		theme.Radius = map[string]float64{}
+	}
+	if theme.DarkColors == nil {
+		theme.DarkColors = map[string]string{}
+	}
+}
+
+// validateTheme checks token names and values. Groups are checked in a fixed
+// order, and names sorted, so the same theme always reports the same error.
+func validateTheme(theme models.Theme) error {
+	for _, name := range sortedKeys(theme.Colors) {
+		if !tokenNamePattern.MatchString(name) {
+			return fmt.Errorf("colors.%s: invalid token name", name)
+		}
+		if !hexColorPattern.MatchString(theme.Colors[name]) {
+			return fmt.Errorf("colors.%s: must be a hex color", name)
+		}
+	}
+	for _, name := range sortedKeys(theme.DarkColors) {
+		if _, ok := theme.Colors[name]; !ok {
+			return fmt.Errorf("dark_colors.%s: no color token with this name", name)
+		}
+		if !hexColorPattern.MatchString(theme.DarkColors[name]) {
+			return fmt.Errorf("dark_colors.%s: must be a hex color", name)
+		}
+	}
+	for _, name := range sortedKeys(theme.Typography) {
+		style := theme.Typography[name]
+		if !tokenNamePattern.MatchString(name) {
+			return fmt.Errorf("typography.%s: invalid token name", name)
+		}
+		if style.FontSize <= 0 {
+			return fmt.Errorf("typography.%s.font_size: must be positive", name)
+		}
+		if style.FontWeight != 0 && (style.FontWeight < 100 || style.FontWeight > 900 || style.FontWeight%100 != 0) {
+			return fmt.Errorf("typography.%s.font_weight: must be 100 to 900 in steps of 100", name)
+		}
+		if style.LineHeight < 0 {
+			return fmt.Errorf("typography.%s.line_height: must not be negative", name)
+		}
+	}
+	sizeGroups := []struct {
+		name  string
+		sizes map[string]float64
+	}{{"spacing", theme.Spacing}, {"radius", theme.Radius}}
+	for _, group := range sizeGroups {
+		for _, name := range sortedKeys(group.sizes) {
+			if !tokenNamePattern.MatchString(name) {
+				return fmt.Errorf("%s.%s: invalid token name", group.name, name)
+			}
+			if group.sizes[name] < 0 {
+				return fmt.Errorf("%s.%s: must not be negative", group.name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// themeLookup resolves theme token references. colorScheme selects dark-mode color overrides.
+// Typography styles resolve per field ("typography.body.font_size") or as a whole object.
+func themeLookup(theme *models.Theme, colorScheme string) referenceLookup {
+	return func(path string) (interface{}, bool) {
+		namespace, name, _ := strings.Cut(path, ".")
+		switch namespace {
+		case "color":
+			if colorScheme == utils.ColorSchemeDark {
+				if value, ok := theme.DarkColors[name]; ok {
+					return value, true
+				}
+			}
+			value, ok := theme.Colors[name]
+			return value, ok
+		case "spacing":
+			value, ok := theme.Spacing[name]
+			return value, ok
+		case "radius":
+			value, ok := theme.Radius[name]
+			return value, ok
+		case "typography":
+			styleName, field, hasField := strings.Cut(name, ".")
+			style, ok := theme.Typography[styleName]
+			if !ok {
+				return nil, false
+			}
+			if !hasField {
+				return style, true
+			}
+			switch field {
+			case "font_family":
+				return style.FontFamily, true
+			case "font_size":
+				return style.FontSize, true
+			case "font_weight":
+				return style.FontWeight, true
+			case "line_height":
+				return style.LineHeight, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// validateThemeReferences checks that every theme token referenced in config exists.
+// References in other namespaces are not theme tokens and are ignored.
+// path names the config's owner in error messages ("" for none).
+func validateThemeReferences(config map[string]interface{}, theme *models.Theme, path string) error {
+	lookup := themeLookup(theme, utils.ColorSchemeLight)
+	for _, ref := range findReferences(config) {
+		if !utils.ThemeTokenNamespaces[ref.namespace()] {
+			continue
+		}
+		if _, ok := lookup(ref.Path); !ok {
+			err := fmt.Errorf("config.%s references unknown theme token %s", ref.Field, ref.Path)
+			if path != "" {
+				err = fmt.Errorf("%s: %v", path, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNodeThemeReferences is validateThemeReferences for a WidgetNode tree.
+func validateNodeThemeReferences(n models.WidgetNode, theme *models.Theme, path string) error {
+	if err := validateThemeReferences(n.Config, theme, path); err != nil {
+		return err
+	}
+	for i, child := range n.Children {
+		if err := validateNodeThemeReferences(child, theme, fmt.Sprintf("%s.children[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns a map's keys in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
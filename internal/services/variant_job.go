@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"time"
+
+	"appdrop-api/internal/imaging"
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+)
+
+// VariantWidths are the widths, in pixels, of the resized variants generated for each
+// uploaded image. Set from configuration at startup.
+var VariantWidths = []int{320, 640, 1080}
+
+// variantJPEGQuality is the JPEG quality variants are encoded with.
+const variantJPEGQuality = 82
+
+// variantClaimTimeout is how long an asset can stay claimed by a worker before another
+// worker assumes the first one stopped and claims it again.
+const variantClaimTimeout = 15 * time.Minute
+
+// variantWake asks the variant job to run before its next tick, e.g. after an upload.
+var variantWake = make(chan struct{}, 1)
+
+// wakeVariantJob asks the variant job to look for pending assets now. It never blocks.
+func wakeVariantJob() {
+	select {
+	case variantWake <- struct{}{}:
+	default:
+	}
+}
+
+// StartVariantJob runs the asset variant job in the background: every interval, and
+// whenever an asset is uploaded or queued for regeneration, it generates the resized
+// variants of every asset waiting for them. The job stops when ctx is cancelled; the
+// returned channel is closed once it has stopped.
+func StartVariantJob(ctx context.Context, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			generatePendingVariants(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-variantWake:
+			}
+		}
+	}()
+	return done
+}
+
+// generatePendingVariants claims and processes waiting assets one at a time until none are left.
+// An asset whose variants cannot be generated is marked failed rather than retried.
+func generatePendingVariants(ctx context.Context) {
+	for ctx.Err() == nil {
+		asset, err := repository.ClaimPendingAsset(ctx, variantClaimTimeout)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintln(os.Stderr, "variant job:", err)
+			}
+			return
+		}
+		if asset == nil {
+			return
+		}
+
+		if err := generateVariants(ctx, asset); err != nil {
+			if ctx.Err() != nil {
+				// Shutting down; the claim goes stale and another run picks the asset up
+				return
+			}
+			fmt.Fprintf(os.Stderr, "variant job: asset %s: %v\n", asset.ID, err)
+			if _, err := repository.SetAssetVariantsStatus(ctx, asset.ID, "failed"); err != nil {
+				fmt.Fprintln(os.Stderr, "variant job:", err)
+			}
+		}
+	}
+}
+
+// generateVariants resizes an asset's image to every configured width narrower than the
+// image, stores the results and records them, replacing any earlier variants.
+// JPEGs stay JPEG. Other images are re-encoded as JPEG when they are fully opaque,
+// which is much smaller for photos, and as PNG when they have transparency; animated
+// GIFs only keep their first frame.
+func generateVariants(ctx context.Context, asset *models.Asset) error {
+	file, err := Assets.Open(ctx, asset.StorageKey)
+	if err != nil {
+		return err
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("decode image: %w", err)
+	}
+	src := imaging.ToRGBA(img)
+	asJPEG := asset.MimeType == "image/jpeg" || src.Opaque()
+
+	var variants []models.AssetVariant
+	removeStored := func() {
+		for _, v := range variants {
+			Assets.Delete(context.WithoutCancel(ctx), v.StorageKey)
+		}
+	}
+
+	for _, size := range imaging.VariantSizes(src.Rect, VariantWidths) {
+		if err := ctx.Err(); err != nil {
+			removeStored()
+			return err
+		}
+
+		width, height := size.X, size.Y
+		resized := imaging.Resize(src, width, height)
+
+		var buf bytes.Buffer
+		mimeType, ext := "image/png", ".png"
+		if asJPEG {
+			mimeType, ext = "image/jpeg", ".jpg"
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: variantJPEGQuality})
+		} else {
+			err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, resized)
+		}
+		if err != nil {
+			removeStored()
+			return fmt.Errorf("encode %dpx variant: %w", width, err)
+		}
+
+		key, err := newAssetKey(ext)
+		if err != nil {
+			removeStored()
+			return err
+		}
+		size := int64(buf.Len())
+		if err := Assets.Put(ctx, key, &buf); err != nil {
+			removeStored()
+			return err
+		}
+		variants = append(variants, models.AssetVariant{
+			Width: width, Height: height, MimeType: mimeType, SizeBytes: size, StorageKey: key,
+		})
+	}
+
+	replaced, err := repository.SaveAssetVariants(ctx, asset.ID, variants)
+	if err != nil {
+		// The asset may have been deleted meanwhile
+		removeStored()
+		return err
+	}
+	for _, key := range replaced {
+		Assets.Delete(ctx, key)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// isVisible reports whether at falls inside a visibility window.
+// from is inclusive and until exclusive; a nil bound leaves that side open.
+func isVisible(from, until *time.Time, at time.Time) bool {
+	if from != nil && at.Before(*from) {
+		return false
+	}
+	if until != nil && !at.Before(*until) {
+		return false
+	}
+	return true
+}
+
+// pageVisible reports whether a page is shown on public reads at the given time:
+// it must be published and inside its visibility window.
+func pageVisible(page models.Page, at time.Time) bool {
+	return page.Status == utils.PageStatusPublished && isVisible(page.VisibleFrom, page.VisibleUntil, at)
+}
+
+// validateVisibilityWindow checks that a window's end, if set, comes after its start.
+func validateVisibilityWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return errors.New("visible_until must be after visible_from")
+	}
+	return nil
+}
+
+// validatePageSchedule checks a page's status, publish time and visibility window.
+// An empty status becomes current, the page's existing status ("" on create, which
+// defaults to published). publish_at can only be set on drafts.
+func validatePageSchedule(page *models.Page, current string) error {
+	if page.Status == "" {
+		page.Status = current
+	}
+	if page.Status == "" {
+		page.Status = utils.PageStatusPublished
+	}
+	if page.Status != utils.PageStatusDraft && page.Status != utils.PageStatusPublished {
+		return errors.New("status must be draft or published")
+	}
+	if page.PublishAt != nil && page.Status != utils.PageStatusDraft {
+		return errors.New("publish_at can only be set on draft pages")
+	}
+	return validateVisibilityWindow(page.VisibleFrom, page.VisibleUntil)
+}
+
+// filterPublicWidgets drops the widgets a public read should not show: those outside
+// their visibility window at opts.At and those whose targeting rule does not match
+// opts.Client. A hidden container hides everything nested in it.
+func filterPublicWidgets(widgets []models.Widget, opts models.RenderOptions) []models.Widget {
+	visible := make([]models.Widget, 0, len(widgets))
+	for _, w := range widgets {
+		if !isVisible(w.VisibleFrom, w.VisibleUntil, opts.At) || !widgetTargeted(w, opts.Client) {
+			continue
+		}
+		if w.Children != nil {
+			w.Children = filterPublicWidgets(w.Children, opts)
+		}
+		visible = append(visible, w)
+	}
+	return visible
+}
+
+// visiblePageIDs returns the IDs of the pages shown on public reads at the given time.
+func visiblePageIDs(ctx context.Context, at time.Time) (map[string]bool, error) {
+	pages, err := repository.GetAllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[string]bool, len(pages))
+	for _, p := range pages {
+		if pageVisible(p, at) {
+			visible[p.ID] = true
+		}
+	}
+	return visible, nil
+}
+
+// filterNavigation keeps the navigation items that work for app users: URL items and
+// items targeting a visible page. Broken items and items for hidden pages are dropped.
+func filterNavigation(nav *models.Navigation, visible map[string]bool) {
+	keep := func(items []models.NavigationItem) []models.NavigationItem {
+		kept := make([]models.NavigationItem, 0, len(items))
+		for _, item := range items {
+			if item.Broken || (item.PageID != nil && !visible[*item.PageID]) {
+				continue
+			}
+			kept = append(kept, item)
+		}
+		return kept
+	}
+	nav.TabBar = keep(nav.TabBar)
+	nav.Drawer = keep(nav.Drawer)
+}
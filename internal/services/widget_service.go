@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"appdrop-api/internal/models"
 	"appdrop-api/internal/repository"
@@ -10,83 +12,338 @@ import (
 
 // CreateWidget validates and creates a new widget on a page.
 // Business Rules Enforced:
-//   - Widget type must be one of the valid types: banner, product_grid, text, image, spacer
+//   - Widget type must be one of the valid types (see utils.ValidWidgetTypes)
 //   - Page specified by PageID must exist
+//   - If ParentID is set, the parent must be a container on the same page that accepts
+//     this widget type, and the tree must stay within utils.MaxWidgetDepth levels
+//   - Component widgets must reference an existing component; they are placed as if they
+//     were the component's root widget and their own config is ignored
+//   - Theme tokens referenced in config (e.g. "{{color.primary}}") must exist, as must
+//     bound store variables and products ("{{store.x}}", "{{product.<id>.name}}");
+//     references in any other namespace are rejected
+//   - config.asset_id, if set, must reference an uploaded asset
+//   - product_grid configs may list products by collection_id or product_ids, not both,
+//     and the referenced collection or products must exist (see validateProductGrid)
+//   - visible_until must be after visible_from
+//   - A targeting rule, if set, must parse (see internal/targeting)
 //
 // Returns the created widget with its UUID or an error.
-func CreateWidget(widget models.Widget) (*models.Widget, error) {
+func CreateWidget(ctx context.Context, widget models.Widget) (*models.Widget, error) {
 
 	if !utils.ValidWidgetTypes[widget.Type] {
 		return nil, errors.New("invalid widget type")
 	}
 
+	if err := validateVisibilityWindow(widget.VisibleFrom, widget.VisibleUntil); err != nil {
+		return nil, err
+	}
+
+	if err := normalizeTargeting(&widget); err != nil {
+		return nil, err
+	}
+
 	// Validate page exists
-	_, err := repository.GetPageByID(widget.PageID)
+	_, err := repository.GetPageByID(ctx, widget.PageID)
+	if err != nil {
+		return nil, notFound(ctx, "page not found")
+	}
+
+	ix, err := loadWidgetIndex(ctx, widget.PageID)
 	if err != nil {
-		return nil, errors.New("page not found")
+		return nil, err
+	}
+
+	widgetType, height, err := placementShape(ctx, &widget, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err := ix.validatePlacement(widget.ParentID, widgetType, height); err != nil {
+		return nil, err
+	}
+
+	if err := validateWidgetReferences(ctx, widget.Config); err != nil {
+		return nil, err
 	}
 
-	return repository.CreateWidget(widget)
+	if err := validateAssetReference(ctx, widget.Config); err != nil {
+		return nil, err
+	}
+
+	if widget.Type == utils.ProductGridWidgetType {
+		if err := validateProductGrid(ctx, widget.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	return repository.CreateWidget(ctx, widget)
 }
 
 // UpdateWidget modifies an existing widget's properties.
 // Business Rules Enforced:
-//   - Widget type must be one of the valid types: banner, product_grid, text, image, spacer
+//   - Widget type must be one of the valid types (see utils.ValidWidgetTypes)
 //   - Widget must exist by ID
+//   - A widget with children must remain a container that accepts all of them
+//   - The new type must still be accepted by the widget's parent container
+//   - A component reference can only be pointed at another component; use DetachWidget
+//     to turn it into a local widget
+//   - Theme tokens, store variables and products referenced in config must exist
+//   - config.asset_id, if set, must reference an uploaded asset
+//   - product_grid configs may list products by collection_id or product_ids, not both,
+//     and the referenced collection or products must exist (see validateProductGrid)
+//   - visible_until must be after visible_from
+//   - A targeting rule, if set, must parse (see internal/targeting)
 //
 // Returns the updated widget or an error.
-func UpdateWidget(widget models.Widget) (*models.Widget, error) {
+func UpdateWidget(ctx context.Context, widget models.Widget) (*models.Widget, error) {
 
 	if !utils.ValidWidgetTypes[widget.Type] {
 		return nil, errors.New("invalid widget type")
 	}
 
+	if err := validateVisibilityWindow(widget.VisibleFrom, widget.VisibleUntil); err != nil {
+		return nil, err
+	}
+
+	if err := normalizeTargeting(&widget); err != nil {
+		return nil, err
+	}
+
 	// Validate widget exists
-	_, err := repository.GetWidgetByID(widget.ID)
+	existing, err := repository.GetWidgetByID(ctx, widget.ID)
 	if err != nil {
-		return nil, errors.New("widget not found")
+		return nil, notFound(ctx, "widget not found")
 	}
 
-	return repository.UpdateWidget(widget)
+	if widget.Type != existing.Type || parentKey(widget.ComponentID) != parentKey(existing.ComponentID) {
+		if existing.Type == utils.ComponentWidgetType && widget.Type != utils.ComponentWidgetType {
+			return nil, errors.New("component widgets cannot change type; detach the widget first")
+		}
+
+		ix, err := loadWidgetIndex(ctx, existing.PageID)
+		if err != nil {
+			return nil, err
+		}
+
+		children := ix.children[existing.ID]
+		allowed, isContainer := utils.ContainerWidgetTypes[widget.Type]
+		if len(children) > 0 && !isContainer {
+			return nil, errors.New("widget has children; only container types can hold children")
+		}
+		for _, childID := range children {
+			if allowed != nil && !allowed[ix.byID[childID].Type] {
+				return nil, fmt.Errorf("%s widgets cannot contain %s widgets", widget.Type, ix.byID[childID].Type)
+			}
+		}
+
+		widgetType, height, err := placementShape(ctx, &widget, ix.height(existing.ID))
+		if err != nil {
+			return nil, err
+		}
+		if err := ix.validatePlacement(existing.ParentID, widgetType, height); err != nil {
+			return nil, err
+		}
+	} else if widget.Type == utils.ComponentWidgetType {
+		widget.Config = nil
+	}
+
+	if err := validateWidgetReferences(ctx, widget.Config); err != nil {
+		return nil, err
+	}
+
+	if err := validateAssetReference(ctx, widget.Config); err != nil {
+		return nil, err
+	}
+
+	if widget.Type == utils.ProductGridWidgetType {
+		if err := validateProductGrid(ctx, widget.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	return repository.UpdateWidget(ctx, widget)
 }
 
 // DeleteWidget removes a widget from the database.
 // Validates the widget exists before deletion.
+// Deleting a container also deletes every widget nested inside it.
 // Returns error if widget not found.
-func DeleteWidget(id string) error {
+func DeleteWidget(ctx context.Context, id string) error {
 	// Validate widget exists
-	_, err := repository.GetWidgetByID(id)
+	_, err := repository.GetWidgetByID(ctx, id)
 	if err != nil {
-		return errors.New("widget not found")
+		return notFound(ctx, "widget not found")
 	}
 
-	return repository.DeleteWidget(id)
+	return repository.DeleteWidget(ctx, id)
 }
 
-// ReorderWidgets updates the position of all widgets on a page.
+// ReorderWidgets updates the position of sibling widgets on a page.
 // Business Rules Enforced:
 //   - Page must exist
 //   - All widget IDs must exist
 //   - All widgets must belong to the specified page
+//   - All widgets must be children of parentID (nil for top-level widgets)
 //
 // The position is determined by the order in the ids array (0-based indexing).
-func ReorderWidgets(pageID string, ids []string) error {
+func ReorderWidgets(ctx context.Context, pageID string, parentID *string, ids []string) error {
 	// Validate page exists
-	_, err := repository.GetPageByID(pageID)
+	_, err := repository.GetPageByID(ctx, pageID)
 	if err != nil {
-		return errors.New("page not found")
+		return notFound(ctx, "page not found")
 	}
 
-	// Validate all widgets exist and belong to page
+	// Validate all widgets exist, belong to page and share the parent
 	for _, id := range ids {
-		widget, err := repository.GetWidgetByID(id)
+		widget, err := repository.GetWidgetByID(ctx, id)
 		if err != nil {
-			return errors.New("widget not found")
+			return notFound(ctx, "widget not found")
 		}
 		if widget.PageID != pageID {
 			return errors.New("widget does not belong to this page")
 		}
+		if parentKey(widget.ParentID) != parentKey(parentID) {
+			return errors.New("widget does not belong to this parent")
+		}
+	}
+
+	return repository.ReorderWidgets(ctx, pageID, ids)
+}
+
+// MoveWidget moves a widget (with its children) to a new parent and position on the same page.
+// Business Rules Enforced:
+//   - Widget must exist by ID
+//   - Position must not be negative; positions past the end append the widget
+//   - A widget cannot be moved into itself or one of its descendants
+//   - The new parent must be a container accepting the widget type, within depth limits
+//
+// Siblings in both the old and new parent are renumbered so positions stay contiguous.
+// Returns the moved widget or an error.
+func MoveWidget(ctx context.Context, id string, parentID *string, position int) (*models.Widget, error) {
+	widget, err := repository.GetWidgetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "widget not found")
 	}
 
-	return repository.ReorderWidgets(pageID, ids)
+	if position < 0 {
+		return nil, errors.New("position must not be negative")
+	}
+
+	ix, err := loadWidgetIndex(ctx, widget.PageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rule: no cycles
+	if parentID != nil && (*parentID == id || ix.isDescendant(*parentID, id)) {
+		return nil, errors.New("cannot move a widget into itself or its descendants")
+	}
+
+	if err := ix.validatePlacement(parentID, ix.effectiveType(widget), ix.height(id)); err != nil {
+		return nil, err
+	}
+
+	oldKey, newKey := parentKey(widget.ParentID), parentKey(parentID)
+
+	newSiblings := without(ix.children[newKey], id)
+	if position > len(newSiblings) {
+		position = len(newSiblings)
+	}
+	newSiblings = append(newSiblings[:position], append([]string{id}, newSiblings[position:]...)...)
+
+	var oldSiblings []string
+	if oldKey != newKey {
+		oldSiblings = without(ix.children[oldKey], id)
+	}
+
+	if err := repository.MoveWidget(ctx, id, parentID, oldSiblings, newSiblings); err != nil {
+		return nil, err
+	}
+
+	return repository.GetWidgetByID(ctx, id)
+}
+
+// DetachWidget converts a component reference into a local, editable copy of the
+// component's current definition, including nested children.
+// Business Rules Enforced:
+//   - Widget must exist by ID and be a component widget
+//
+// The component itself and its other usages are unaffected.
+// Returns the detached widget with its new children nested.
+func DetachWidget(ctx context.Context, id string) (*models.Widget, error) {
+	widget, err := repository.GetWidgetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(ctx, "widget not found")
+	}
+	if widget.Type != utils.ComponentWidgetType || widget.ComponentID == nil {
+		return nil, errors.New("widget is not a component reference")
+	}
+
+	component, err := repository.GetComponentByID(ctx, *widget.ComponentID)
+	if err != nil {
+		return nil, notFound(ctx, "component not found")
+	}
+
+	if err := repository.DetachWidget(ctx, id, component.Root); err != nil {
+		return nil, err
+	}
+
+	ix, err := loadWidgetIndex(ctx, widget.PageID)
+	if err != nil {
+		return nil, err
+	}
+	detached := ix.widget(id)
+	return &detached, nil
+}
+
+// placementShape returns the type and subtree height a widget occupies in the tree.
+// For component widgets this is the referenced component's root type and height, and
+// their own config is cleared; other widgets use their own type and subtreeHeight.
+// Validates that component_id is set exactly when the type is "component".
+func placementShape(ctx context.Context, widget *models.Widget, subtreeHeight int) (string, int, error) {
+	if widget.Type != utils.ComponentWidgetType {
+		if widget.ComponentID != nil {
+			return "", 0, errors.New("component_id is only allowed on component widgets")
+		}
+		return widget.Type, subtreeHeight, nil
+	}
+
+	if widget.ComponentID == nil {
+		return "", 0, errors.New("component_id is required for component widgets")
+	}
+	if !utils.IsUUID(*widget.ComponentID) {
+		return "", 0, errors.New("component_id must be a UUID")
+	}
+	component, err := repository.GetComponentByID(ctx, *widget.ComponentID)
+	if err != nil {
+		return "", 0, notFound(ctx, "component not found")
+	}
+	widget.Config = nil
+	return component.Root.Type, nodeHeight(component.Root), nil
+}
+
+// validateWidgetReferences checks the theme tokens and bindings referenced by a widget config.
+// The theme and binding data are only loaded when the config contains references.
+func validateWidgetReferences(ctx context.Context, config map[string]interface{}) error {
+	if len(findReferences(config)) == 0 {
+		return nil
+	}
+	theme, err := repository.GetTheme(ctx)
+	if err != nil {
+		return err
+	}
+	if err := validateThemeReferences(config, theme, ""); err != nil {
+		return err
+	}
+	return validateBindings(ctx, config, "")
+}
+
+// without returns a copy of ids with id removed.
+func without(ids []string, id string) []string {
+	result := make([]string, 0, len(ids))
+	for _, other := range ids {
+		if other != id {
+			result = append(result, other)
+		}
+	}
+	return result
 }
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"appdrop-api/internal/models"
+	"appdrop-api/internal/repository"
+	"appdrop-api/internal/utils"
+)
+
+// widgetIndex is an in-memory view of one page's widget tree.
+// It is used to validate structural changes (nesting, moves, depth) before they are written,
+// and to assemble the nested tree returned by page reads.
+type widgetIndex struct {
+	byID map[string]*models.Widget
+	// children maps a parent ID ("" for the top level) to its child IDs ordered by position
+	children map[string][]string
+	// components holds the definitions referenced by component widgets, keyed by component ID
+	components map[string]*models.Component
+}
+
+// loadWidgetIndex reads every widget of a page, plus the components they reference, and indexes them.
+func loadWidgetIndex(ctx context.Context, pageID string) (*widgetIndex, error) {
+	widgets, err := repository.GetWidgetsByPageID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	ix := newWidgetIndex(widgets)
+
+	var componentIDs []string
+	for _, w := range ix.byID {
+		if w.ComponentID != nil {
+			componentIDs = append(componentIDs, *w.ComponentID)
+		}
+	}
+	ix.components, err = repository.GetComponentsByIDs(ctx, componentIDs)
+	if err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// newWidgetIndex indexes a flat widget list that is already ordered by position.
+func newWidgetIndex(widgets []models.Widget) *widgetIndex {
+	ix := &widgetIndex{
+		byID:       make(map[string]*models.Widget, len(widgets)),
+		children:   make(map[string][]string),
+		components: make(map[string]*models.Component),
+	}
+	for i := range widgets {
+		w := &widgets[i]
+		ix.byID[w.ID] = w
+		key := parentKey(w.ParentID)
+		ix.children[key] = append(ix.children[key], w.ID)
+	}
+	return ix
+}
+
+// parentKey converts a nullable parent ID into a children map key.
+func parentKey(parentID *string) string {
+	if parentID == nil {
+		return ""
+	}
+	return *parentID
+}
+
+// component returns the definition referenced by a component widget, or nil.
+func (ix *widgetIndex) component(w *models.Widget) *models.Component {
+	if w.ComponentID == nil {
+		return nil
+	}
+	return ix.components[*w.ComponentID]
+}
+
+// effectiveType is the type a widget renders as: a component widget renders as its component's root.
+func (ix *widgetIndex) effectiveType(w *models.Widget) string {
+	if c := ix.component(w); c != nil {
+		return c.Root.Type
+	}
+	return w.Type
+}
+
+// depth returns how deeply a widget is nested; top-level widgets are at depth 1.
+func (ix *widgetIndex) depth(id string) int {
+	depth := 0
+	for w := ix.byID[id]; w != nil; w = ix.byID[parentKey(w.ParentID)] {
+		depth++
+		if depth > len(ix.byID) {
+			break // defensive: corrupt data with a cycle
+		}
+	}
+	return depth
+}
+
+// height returns the number of levels in the subtree rooted at id; a leaf has height 1.
+// A component widget is as tall as its component's definition.
+func (ix *widgetIndex) height(id string) int {
+	if c := ix.component(ix.byID[id]); c != nil {
+		return nodeHeight(c.Root)
+	}
+	max := 0
+	for _, childID := range ix.children[id] {
+		if h := ix.height(childID); h > max {
+			max = h
+		}
+	}
+	return max + 1
+}
+
+// isDescendant reports whether id sits anywhere below ancestorID in the tree.
+func (ix *widgetIndex) isDescendant(id, ancestorID string) bool {
+	for w := ix.byID[id]; w != nil && w.ParentID != nil; w = ix.byID[*w.ParentID] {
+		if *w.ParentID == ancestorID {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePlacement checks that a widget of widgetType whose subtree is subtreeHeight levels
+// tall may be placed under parentID (nil for the top level).
+// Rules Enforced:
+//   - Parent must exist on the same page and be a container type
+//   - The container must accept the widget type (e.g., carousels only hold banners and images)
+//   - The resulting tree must not exceed utils.MaxWidgetDepth levels
+func (ix *widgetIndex) validatePlacement(parentID *string, widgetType string, subtreeHeight int) error {
+	parentDepth := 0
+	if parentID != nil {
+		parent, ok := ix.byID[*parentID]
+		if !ok {
+			return errors.New("parent widget not found on this page")
+		}
+		allowed, isContainer := utils.ContainerWidgetTypes[parent.Type]
+		if !isContainer {
+			return errors.New("parent widget is not a container")
+		}
+		if allowed != nil && !allowed[widgetType] {
+			return fmt.Errorf("%s widgets cannot contain %s widgets", parent.Type, widgetType)
+		}
+		parentDepth = ix.depth(parent.ID)
+	}
+
+	if parentDepth+subtreeHeight > utils.MaxWidgetDepth {
+		return fmt.Errorf("widgets cannot be nested more than %d levels deep", utils.MaxWidgetDepth)
+	}
+	return nil
+}
+
+// tree assembles the indexed widgets into nested form, starting from the top level.
+// Component widgets carry their component's current definition.
+func (ix *widgetIndex) tree() []models.Widget {
+	return ix.subtree("")
+}
+
+func (ix *widgetIndex) subtree(parentID string) []models.Widget {
+	ids := ix.children[parentID]
+	nodes := make([]models.Widget, 0, len(ids))
+	for _, id := range ids {
+		nodes = append(nodes, ix.widget(id))
+	}
+	return nodes
+}
+
+// widget returns one widget with its children nested and component expanded.
+func (ix *widgetIndex) widget(id string) models.Widget {
+	w := *ix.byID[id]
+	w.Component = ix.component(&w)
+	w.Children = ix.subtree(id)
+	if len(w.Children) == 0 {
+		w.Children = nil
+	}
+	return w
+}
+
+// node copies the subtree rooted at id into a page-independent WidgetNode definition.
+// Component references are kept as references.
+func (ix *widgetIndex) node(id string) models.WidgetNode {
+	w := ix.byID[id]
+	n := models.WidgetNode{Type: w.Type, ComponentID: w.ComponentID, Config: w.Config}
+	for _, childID := range ix.children[id] {
+		n.Children = append(n.Children, ix.node(childID))
+	}
+	return n
+}
+
+// nodeHeight returns the number of levels in a WidgetNode tree; a leaf has height 1.
+func nodeHeight(n models.WidgetNode) int {
+	max := 0
+	for _, child := range n.Children {
+		if h := nodeHeight(child); h > max {
+			max = h
+		}
+	}
+	return max + 1
+}
+
+// definitionHeight is nodeHeight for definitions that may reference components:
+// a component reference is as tall as the referenced component's definition.
+func definitionHeight(n models.WidgetNode, components map[string]*models.Component) int {
+	if n.ComponentID != nil {
+		if c := components[*n.ComponentID]; c != nil {
+			return nodeHeight(c.Root)
+		}
+		return 1
+	}
+	max := 0
+	for _, child := range n.Children {
+		if h := definitionHeight(child, components); h > max {
+			max = h
+		}
+	}
+	return max + 1
+}
+
+// validateNode checks a WidgetNode definition tree. path names the node in error messages.
+// components holds the definitions component references may point at; pass nil to
+// forbid component references (components cannot nest other components).
+// Rules Enforced:
+//   - Every type must be valid
+//   - Component references are only allowed when components is non-nil, and must name one of them
+//   - Only container types may have children, and only of types they accept;
+//     a component reference counts as its component's root type
+//   - The tree must not exceed utils.MaxWidgetDepth levels
+func validateNode(n models.WidgetNode, path string, components map[string]*models.Component) error {
+	if err := validateNodeTree(n, path, components); err != nil {
+		return err
+	}
+	if definitionHeight(n, components) > utils.MaxWidgetDepth {
+		return fmt.Errorf("%s: widgets cannot be nested more than %d levels deep", path, utils.MaxWidgetDepth)
+	}
+	return nil
+}
+
+func validateNodeTree(n models.WidgetNode, path string, components map[string]*models.Component) error {
+	if !utils.ValidWidgetTypes[n.Type] {
+		return fmt.Errorf("%s: invalid widget type", path)
+	}
+	if n.Type == utils.ComponentWidgetType {
+		if components == nil {
+			return fmt.Errorf("%s: components cannot contain component references", path)
+		}
+		if n.ComponentID == nil {
+			return fmt.Errorf("%s: component_id is required for component widgets", path)
+		}
+		if !utils.IsUUID(*n.ComponentID) {
+			return fmt.Errorf("%s: component_id must be a UUID", path)
+		}
+		if components[*n.ComponentID] == nil {
+			return fmt.Errorf("%s: component not found", path)
+		}
+		if len(n.Children) > 0 {
+			return fmt.Errorf("%s: component widgets cannot have children", path)
+		}
+	} else if n.ComponentID != nil {
+		return fmt.Errorf("%s: component_id is only allowed on component widgets", path)
+	}
+
+	allowed, isContainer := utils.ContainerWidgetTypes[n.Type]
+	if len(n.Children) > 0 && !isContainer {
+		return fmt.Errorf("%s: only container widgets can have children", path)
+	}
+	for i, child := range n.Children {
+		childPath := fmt.Sprintf("%s.children[%d]", path, i)
+		childType := child.Type
+		if child.ComponentID != nil && components[*child.ComponentID] != nil {
+			childType = components[*child.ComponentID].Root.Type
+		}
+		if allowed != nil && !allowed[childType] {
+			return fmt.Errorf("%s: %s widgets cannot contain %s widgets", childPath, n.Type, childType)
+		}
+		if err := validateNodeTree(child, childPath, components); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDefinition checks a list of top-level widget definitions (such as a template)
+// that may reference components, loading the referenced components to do so.
+// path names the list in error messages.
+func validateDefinition(ctx context.Context, nodes []models.WidgetNode, path string) error {
+	var componentIDs []string
+	var collect func(n models.WidgetNode)
+	collect = func(n models.WidgetNode) {
+		// Malformed IDs are reported by validateNode
+		if n.ComponentID != nil && utils.IsUUID(*n.ComponentID) {
+			componentIDs = append(componentIDs, *n.ComponentID)
+		}
+		for _, child := range n.Children {
+			collect(child)
+		}
+	}
+	for _, n := range nodes {
+		collect(n)
+	}
+
+	components, err := repository.GetComponentsByIDs(ctx, componentIDs)
+	if err != nil {
+		return err
+	}
+
+	for i, n := range nodes {
+		if err := validateNode(n, fmt.Sprintf("%s[%d]", path, i), components); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk calls fn for every widget in tree order (parents before children).
+func (ix *widgetIndex) walk(fn func(w *models.Widget)) {
+	var visit func(parentID string)
+	visit = func(parentID string) {
+		for _, id := range ix.children[parentID] {
+			fn(ix.byID[id])
+			visit(id)
+		}
+	}
+	visit("")
+}
+
+// walkWidgetConfigs calls fn with the type and config of every widget in a tree and of
+// every node of the components they reference.
+func walkWidgetConfigs(widgets []models.Widget, fn func(widgetType string, config map[string]interface{})) {
+	var walkNode func(n models.WidgetNode)
+	walkNode = func(n models.WidgetNode) {
+		fn(n.Type, n.Config)
+		for _, child := range n.Children {
+			walkNode(child)
+		}
+	}
+	for _, w := range widgets {
+		fn(w.Type, w.Config)
+		if w.Component != nil {
+			walkNode(w.Component.Root)
+		}
+		walkWidgetConfigs(w.Children, fn)
+	}
+}
+
+// rewriteWidgetConfigs replaces the config of every widget in a tree, and of every node of
+// the components they reference, with fn's result. fn must return a new map rather than
+// modify config; component definitions are copied so shared definitions are unaffected.
+func rewriteWidgetConfigs(widgets []models.Widget, fn func(widgetType string, config map[string]interface{}) map[string]interface{}) {
+	var rewriteNode func(n models.WidgetNode) models.WidgetNode
+	rewriteNode = func(n models.WidgetNode) models.WidgetNode {
+		n.Config = fn(n.Type, n.Config)
+		if n.Children != nil {
+			children := make([]models.WidgetNode, len(n.Children))
+			for i, child := range n.Children {
+				children[i] = rewriteNode(child)
+			}
+			n.Children = children
+		}
+		return n
+	}
+	for i := range widgets {
+		w := &widgets[i]
+		w.Config = fn(w.Type, w.Config)
+		if w.Component != nil {
+			component := *w.Component
+			component.Root = rewriteNode(component.Root)
+			w.Component = &component
+		}
+		rewriteWidgetConfigs(w.Children, fn)
+	}
+}
@@ -0,0 +1,26 @@
+// Package storage keeps binary blobs, such as uploaded media, behind the BlobStore
+// interface so the backing store can change without touching the services that use it.
+// LocalStore keeps blobs as files in a directory on the server.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned by BlobStore.Open when no blob is stored under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores blobs under string keys. Keys are relative slash-separated
+// paths such as "ab/ab12cd.png"; "." and ".." segments are rejected.
+type BlobStore interface {
+	// Put stores the contents of r under key, replacing any existing blob.
+	// A failed Put leaves no partial blob behind.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns a reader over the blob stored under key, or ErrNotFound.
+	// The caller must close the reader.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
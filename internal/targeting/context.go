@@ -0,0 +1,110 @@
+package targeting
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Context describes the client making a public read. Zero values mean the client
+// did not say; see the package documentation for how rules treat them.
+type Context struct {
+	// Platform is "ios" or "android"
+	Platform string
+	// AppVersion is the version of the app making the request
+	AppVersion *Version
+	// Country is an upper-case ISO 3166-1 alpha-2 code
+	Country string
+	// Segments are the user's segment tags
+	Segments []string
+	// LoggedIn reports whether the user is signed in
+	LoggedIn *bool
+}
+
+// ContextParams are the names of the client context values, as read by ParseContext.
+// Segments are comma-separated.
+var ContextParams = []string{"platform", "app_version", "country", "segments", "logged_in"}
+
+// ParseContext builds a Context from raw values, looked up by the names in ContextParams.
+// Platform and country are case-insensitive. Returns an error naming the first invalid value.
+func ParseContext(get func(name string) string) (Context, error) {
+	var ctx Context
+
+	if platform := strings.ToLower(get("platform")); platform != "" {
+		if !slices.Contains(platforms, platform) {
+			return ctx, fmt.Errorf("platform must be one of %s", strings.Join(platforms, ", "))
+		}
+		ctx.Platform = platform
+	}
+
+	if raw := get("app_version"); raw != "" {
+		v, err := ParseVersion(raw)
+		if err != nil {
+			return ctx, err
+		}
+		ctx.AppVersion = &v
+	}
+
+	if country := strings.ToUpper(get("country")); country != "" {
+		if !validCountry(country) {
+			return ctx, errors.New("country must be a two-letter code such as US")
+		}
+		ctx.Country = country
+	}
+
+	for _, s := range strings.Split(get("segments"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			ctx.Segments = append(ctx.Segments, s)
+		}
+	}
+
+	if raw := get("logged_in"); raw != "" {
+		if raw != "true" && raw != "false" {
+			return ctx, errors.New("logged_in must be true or false")
+		}
+		loggedIn := raw == "true"
+		ctx.LoggedIn = &loggedIn
+	}
+
+	return ctx, nil
+}
+
+// Version is an app version of up to three numeric parts; missing parts are zero,
+// so "2.3" equals "2.3.0".
+type Version [3]int
+
+// ParseVersion parses a version such as "2", "2.3" or "2.3.1".
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > len(v) {
+		return v, fmt.Errorf("version %q must have 1 to 3 numeric parts, e.g. \"2.3.1\"", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || strings.HasPrefix(part, "+") {
+			return v, fmt.Errorf("version %q must have 1 to 3 numeric parts, e.g. \"2.3.1\"", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or 1 as v is older than, the same as, or newer than other.
+func (v Version) compare(other Version) int {
+	for i := range v {
+		if v[i] != other[i] {
+			if v[i] < other[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func validCountry(code string) bool {
+	return len(code) == 2 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z'
+}
@@ -0,0 +1,109 @@
+package targeting
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+)
+
+// keywords are identifiers with a meaning of their own; they cannot be field names.
+var keywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "true": true, "false": true,
+}
+
+var punctuation = map[byte]tokenKind{
+	'(': tokenLParen, ')': tokenRParen, '[': tokenLBracket, ']': tokenRBracket, ',': tokenComma,
+}
+
+// token is one lexical element of a rule. pos is its 1-based byte offset.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenIdent && t.text == keyword
+}
+
+// String describes the token in error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of rule"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits a rule into tokens, ending with a tokenEOF.
+func lex(source string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(source) {
+		c := source[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case strings.IndexByte("()[],", c) >= 0:
+			tokens = append(tokens, token{punctuation[c], string(c), start + 1})
+			i++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(source) && source[i+1] == '=' {
+				op += "="
+			}
+			if op == "=" || op == "!" {
+				return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("unknown operator %q, did you mean %q?", op, op+"=")}
+			}
+			tokens = append(tokens, token{tokenOperator, op, start + 1})
+			i += len(op)
+		case c == '"':
+			var text strings.Builder
+			i++
+			for {
+				if i >= len(source) {
+					return nil, &Error{Pos: start + 1, Msg: "unterminated string"}
+				}
+				if source[i] == '"' {
+					i++
+					break
+				}
+				if source[i] == '\\' && i+1 < len(source) && (source[i+1] == '"' || source[i+1] == '\\') {
+					i++
+				}
+				text.WriteByte(source[i])
+				i++
+			}
+			tokens = append(tokens, token{tokenString, text.String(), start + 1})
+		case isIdentStart(c):
+			for i < len(source) && (isIdentStart(source[i]) || (source[i] >= '0' && source[i] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, source[start:i], start + 1})
+		default:
+			return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("unexpected character %q", rune(c))}
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(source) + 1}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
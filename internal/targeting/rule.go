@@ -0,0 +1,371 @@
+// Package targeting parses and evaluates audience targeting rules on widgets.
+//
+// A rule is a boolean expression over the client context of a public read:
+//
+//	platform == "ios" and app_version >= "2.3" and (country in ["US", "CA"] or segment == "vip")
+//
+// Fields:
+//   - platform: "ios" or "android"; operators ==, !=, in
+//   - app_version: a version of 1 to 3 numeric parts ("2", "2.3", "2.3.1"); operators ==, !=, <, <=, >, >=
+//   - country: an ISO 3166-1 alpha-2 code in upper case ("US"); operators ==, !=, in
+//   - segment: a user segment tag; == and in match if the user has the tag (or any listed tag),
+//     != matches if the user does not have it
+//   - logged_in: true or false; used on its own ("logged_in") or with == and !=
+//
+// Conditions combine with and, or, not and parentheses; and binds tighter than or.
+// A comparison against a field the client did not send (other than segment, which is
+// an empty set) never matches, so "not platform == \"ios\"" matches unknown platforms.
+package targeting
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MaxRuleLength is the longest rule, in bytes, that Parse accepts.
+const MaxRuleLength = 1000
+
+// Error is a rule syntax or validation error. Pos is the 1-based byte offset in
+// the rule where the problem was found.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// Rule is a parsed targeting rule.
+type Rule struct {
+	root node
+}
+
+// Match reports whether a client with the given context is targeted by the rule.
+func (r *Rule) Match(ctx Context) bool {
+	return r.root.match(ctx)
+}
+
+// Parse parses and validates a rule.
+// Returns an *Error describing the first problem found.
+func Parse(source string) (*Rule, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, &Error{Pos: 1, Msg: "rule is empty"}
+	}
+	if len(source) > MaxRuleLength {
+		return nil, &Error{Pos: MaxRuleLength + 1, Msg: fmt.Sprintf("rule is longer than %d characters", MaxRuleLength)}
+	}
+
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected and, or or end of rule, found %s", t)}
+	}
+	return &Rule{root: root}, nil
+}
+
+// Fields that rules can test, with the operators each accepts.
+const (
+	fieldPlatform   = "platform"
+	fieldAppVersion = "app_version"
+	fieldCountry    = "country"
+	fieldSegment    = "segment"
+	fieldLoggedIn   = "logged_in"
+)
+
+var fieldOperators = map[string][]string{
+	fieldPlatform:   {"==", "!=", "in"},
+	fieldAppVersion: {"==", "!=", "<", "<=", ">", ">="},
+	fieldCountry:    {"==", "!=", "in"},
+	fieldSegment:    {"==", "!=", "in"},
+	fieldLoggedIn:   {"==", "!="},
+}
+
+// Platforms a client can run on.
+var platforms = []string{"android", "ios"}
+
+// node is an expression in a parsed rule.
+type node interface {
+	match(ctx Context) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(ctx Context) bool { return n.left.match(ctx) && n.right.match(ctx) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(ctx Context) bool { return n.left.match(ctx) || n.right.match(ctx) }
+
+type notNode struct{ operand node }
+
+func (n notNode) match(ctx Context) bool { return !n.operand.match(ctx) }
+
+// comparisonNode tests one field. values holds one value, or several for "in".
+// Versions are parsed once, at parse time.
+type comparisonNode struct {
+	field   string
+	op      string
+	values  []string
+	version Version
+}
+
+func (n comparisonNode) match(ctx Context) bool {
+	switch n.field {
+	case fieldPlatform, fieldCountry:
+		value := ctx.Platform
+		if n.field == fieldCountry {
+			value = ctx.Country
+		}
+		if value == "" {
+			return false
+		}
+		return compareStrings(n.op, value, n.values)
+	case fieldSegment:
+		has := slices.ContainsFunc(n.values, func(v string) bool { return slices.Contains(ctx.Segments, v) })
+		if n.op == "!=" {
+			return !has
+		}
+		return has
+	case fieldLoggedIn:
+		if ctx.LoggedIn == nil {
+			return false
+		}
+		want := n.values[0] == "true"
+		if n.op == "!=" {
+			return *ctx.LoggedIn != want
+		}
+		return *ctx.LoggedIn == want
+	case fieldAppVersion:
+		if ctx.AppVersion == nil {
+			return false
+		}
+		c := ctx.AppVersion.compare(n.version)
+		switch n.op {
+		case "==":
+			return c == 0
+		case "!=":
+			return c != 0
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		case ">=":
+			return c >= 0
+		}
+	}
+	return false
+}
+
+func compareStrings(op, value string, values []string) bool {
+	switch op {
+	case "==", "in":
+		return slices.Contains(values, value)
+	case "!=":
+		return value != values[0]
+	}
+	return false
+}
+
+// parser is a recursive descent parser over the token list:
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" or ")" | condition
+//	condition  = "logged_in" | field op value | field "in" "[" value { "," value } "]"
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("or") {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("and") {
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	switch {
+	case t.isKeyword("not"):
+		p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	case t.kind == tokenLParen:
+		p.advance()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, &Error{Pos: closing.pos, Msg: fmt.Sprintf("expected ) to close the ( at position %d, found %s", t.pos, closing)}
+		}
+		return inner, nil
+	case t.kind == tokenIdent && !keywords[t.text]:
+		return p.parseCondition()
+	}
+	return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a condition, found %s", t)}
+}
+
+func (p *parser) parseCondition() (node, error) {
+	fieldToken := p.advance()
+	field := fieldToken.text
+	ops, ok := fieldOperators[field]
+	if !ok {
+		return nil, &Error{Pos: fieldToken.pos, Msg: fmt.Sprintf("unknown field %q, expected one of %s", field, strings.Join(knownFields(), ", "))}
+	}
+
+	opToken := p.peek()
+	if field == fieldLoggedIn && opToken.kind != tokenOperator && !opToken.isKeyword("in") {
+		// A bare logged_in is shorthand for logged_in == true
+		return comparisonNode{field: field, op: "==", values: []string{"true"}}, nil
+	}
+	if opToken.kind != tokenOperator && !opToken.isKeyword("in") {
+		return nil, &Error{Pos: opToken.pos, Msg: fmt.Sprintf("expected an operator after %s, found %s", field, opToken)}
+	}
+	p.advance()
+	if !slices.Contains(ops, opToken.text) {
+		return nil, &Error{Pos: opToken.pos, Msg: fmt.Sprintf("%s does not support %s, use one of %s", field, opToken.text, strings.Join(ops, " "))}
+	}
+
+	n := comparisonNode{field: field, op: opToken.text}
+	if opToken.text == "in" {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if err := checkValue(field, v); err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, v.text)
+		}
+		return n, nil
+	}
+
+	value := p.advance()
+	if err := checkValue(field, value); err != nil {
+		return nil, err
+	}
+	n.values = []string{value.text}
+	if field == fieldAppVersion {
+		n.version, _ = ParseVersion(value.text)
+	}
+	return n, nil
+}
+
+func (p *parser) parseList() ([]token, error) {
+	open := p.advance()
+	if open.kind != tokenLBracket {
+		return nil, &Error{Pos: open.pos, Msg: fmt.Sprintf("expected [ to start a list after in, found %s", open)}
+	}
+	if t := p.peek(); t.kind == tokenRBracket {
+		return nil, &Error{Pos: t.pos, Msg: "list after in cannot be empty"}
+	}
+	var values []token
+	for {
+		values = append(values, p.advance())
+		sep := p.advance()
+		if sep.kind == tokenRBracket {
+			return values, nil
+		}
+		if sep.kind != tokenComma {
+			return nil, &Error{Pos: sep.pos, Msg: fmt.Sprintf("expected , or ] in list, found %s", sep)}
+		}
+	}
+}
+
+// checkValue checks that a literal is a valid value for field.
+func checkValue(field string, t token) error {
+	if field == fieldLoggedIn {
+		if !t.isKeyword("true") && !t.isKeyword("false") {
+			return &Error{Pos: t.pos, Msg: fmt.Sprintf("logged_in must be compared with true or false, found %s", t)}
+		}
+		return nil
+	}
+	if t.kind != tokenString {
+		return &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a quoted value for %s, found %s", field, t)}
+	}
+
+	switch field {
+	case fieldPlatform:
+		if !slices.Contains(platforms, t.text) {
+			return &Error{Pos: t.pos, Msg: fmt.Sprintf("unknown platform %q, expected one of %s", t.text, strings.Join(platforms, ", "))}
+		}
+	case fieldAppVersion:
+		if _, err := ParseVersion(t.text); err != nil {
+			return &Error{Pos: t.pos, Msg: err.Error()}
+		}
+	case fieldCountry:
+		if !validCountry(t.text) {
+			return &Error{Pos: t.pos, Msg: fmt.Sprintf("country %q must be a two-letter upper-case code such as \"US\"", t.text)}
+		}
+	case fieldSegment:
+		if t.text == "" {
+			return &Error{Pos: t.pos, Msg: "segment cannot be empty"}
+		}
+	}
+	return nil
+}
+
+func knownFields() []string {
+	fields := make([]string, 0, len(fieldOperators))
+	for f := range fieldOperators {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,176 @@
+package targeting
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		rule string
+		pos  int
+		msg  string
+	}{
+		// Structure
+		{"", 1, "rule is empty"},
+		{"   ", 1, "rule is empty"},
+		{strings.Repeat("x", MaxRuleLength+1), MaxRuleLength + 1, "longer than"},
+		{`(platform == "ios"`, 19, "expected ) to close the ( at position 1"},
+		{`((platform == "ios")`, 21, "expected ) to close the ( at position 1"},
+		{`platform == "ios")`, 18, "expected and, or or end of rule"},
+		{`platform == "ios" and`, 22, "expected a condition, found end of rule"},
+		{`or platform == "ios"`, 1, `expected a condition, found "or"`},
+		{`platform == "ios" logged_in`, 19, "expected and, or or end of rule"},
+		// Operators
+		{`platform = "ios"`, 10, `unknown operator "=", did you mean "=="?`},
+		{`not platform ! "ios"`, 14, `unknown operator "!", did you mean "!="?`},
+		{`platform >= "ios"`, 10, "platform does not support >=, use one of == != in"},
+		{`app_version in ["2"]`, 13, "app_version does not support in"},
+		{`country "US"`, 9, "expected an operator after country"},
+		{`platform == "ios" && logged_in`, 19, `unexpected character '&'`},
+		// Fields and literals
+		{`device == "ios"`, 1, `unknown field "device", expected one of app_version, country, logged_in, platform, segment`},
+		{`platform == "windows"`, 13, `unknown platform "windows"`},
+		{`platform == ios`, 13, `expected a quoted value for platform, found "ios"`},
+		{`platform == "ios`, 13, "unterminated string"},
+		{`app_version >= "2.x"`, 16, `version "2.x" must have 1 to 3 numeric parts`},
+		{`app_version >= "1.2.3.4"`, 16, "must have 1 to 3 numeric parts"},
+		{`app_version >= ""`, 16, "must have 1 to 3 numeric parts"},
+		{`country == "us"`, 12, "two-letter upper-case code"},
+		{`country in ["US", "USA"]`, 19, "two-letter upper-case code"},
+		{`segment == ""`, 12, "segment cannot be empty"},
+		{`logged_in == "true"`, 14, "logged_in must be compared with true or false"},
+		{`logged_in == yes`, 14, "logged_in must be compared with true or false"},
+		// Lists
+		{`country in "US"`, 12, "expected [ to start a list after in"},
+		{`country in []`, 13, "list after in cannot be empty"},
+		{`country in ["US" "CA"]`, 18, "expected , or ] in list"},
+		{`country in ["US",`, 18, "expected , or ] in list, found end of rule"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.rule)
+		var ruleErr *Error
+		if !errors.As(err, &ruleErr) {
+			t.Errorf("Parse(%q) error = %v, want an *Error", tt.rule, err)
+			continue
+		}
+		if ruleErr.Pos != tt.pos || !strings.Contains(ruleErr.Msg, tt.msg) {
+			t.Errorf("Parse(%q) error = %v, want position %d: ...%s...", tt.rule, err, tt.pos, tt.msg)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	yes, no := true, false
+	v230 := Version{2, 3, 0}
+	iosUser := Context{Platform: "ios", AppVersion: &v230, Country: "US", Segments: []string{"vip", "beta"}, LoggedIn: &yes}
+	guest := Context{Platform: "android", Country: "DE", LoggedIn: &no}
+	unknown := Context{}
+
+	tests := []struct {
+		rule string
+		ctx  Context
+		want bool
+	}{
+		// Platform and country
+		{`platform == "ios"`, iosUser, true},
+		{`platform == "ios"`, guest, false},
+		{`platform != "ios"`, guest, true},
+		{`platform in ["android", "ios"]`, guest, true},
+		{`country in ["US", "CA"]`, guest, false},
+		{`country != "US"`, guest, true},
+
+		// Versions compare numerically, with missing parts as zero
+		{`app_version == "2.3"`, iosUser, true},
+		{`app_version == "2.3.0"`, iosUser, true},
+		{`app_version != "2.3.1"`, iosUser, true},
+		{`app_version >= "2.3"`, iosUser, true},
+		{`app_version > "2.3"`, iosUser, false},
+		{`app_version > "2.2.9"`, iosUser, true},
+		{`app_version < "2.10"`, iosUser, true},
+		{`app_version <= "2"`, iosUser, false},
+		{`app_version < "3"`, iosUser, true},
+
+		// Segments: the user's tags are a set
+		{`segment == "vip"`, iosUser, true},
+		{`segment in ["staff", "beta"]`, iosUser, true},
+		{`segment in ["staff"]`, iosUser, false},
+		{`segment != "vip"`, iosUser, false},
+		{`segment != "vip"`, guest, true},
+
+		// logged_in, bare and compared
+		{`logged_in`, iosUser, true},
+		{`logged_in`, guest, false},
+		{`logged_in == false`, guest, true},
+		{`logged_in != true`, guest, true},
+		{`not logged_in`, guest, true},
+
+		// Missing context fields never match a comparison, so not inverts to true
+		{`platform == "ios"`, unknown, false},
+		{`platform != "ios"`, unknown, false},
+		{`not platform == "ios"`, unknown, true},
+		{`country != "US"`, unknown, false},
+		{`app_version < "99"`, unknown, false},
+		{`app_version != "1"`, unknown, false},
+		{`logged_in == false`, unknown, false},
+		{`logged_in != true`, unknown, false},
+		{`segment != "vip"`, unknown, true},
+
+		// and binds tighter than or; not binds tightest
+		{`platform == "android" or platform == "ios" and country == "CA"`, iosUser, false},
+		{`platform == "ios" or platform == "android" and country == "CA"`, iosUser, true},
+		{`(platform == "android" or platform == "ios") and country == "US"`, iosUser, true},
+		{`not platform == "android" and country == "US"`, iosUser, true},
+		{`not (platform == "ios" and country == "US")`, iosUser, false},
+		{`not not logged_in`, iosUser, true},
+
+		// The package documentation example
+		{`platform == "ios" and app_version >= "2.3" and (country in ["US", "CA"] or segment == "vip")`, iosUser, true},
+		{`platform == "ios" and app_version >= "2.3" and (country in ["US", "CA"] or segment == "vip")`, guest, false},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.rule, err)
+			continue
+		}
+		if got := rule.Match(tt.ctx); got != tt.want {
+			t.Errorf("Parse(%q).Match(%+v) = %v, want %v", tt.rule, tt.ctx, got, tt.want)
+		}
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	values := map[string]string{
+		"platform":    "iOS",
+		"app_version": "2.3",
+		"country":     "us",
+		"segments":    " vip, ,beta ",
+		"logged_in":   "true",
+	}
+	ctx, err := ParseContext(func(name string) string { return values[name] })
+	if err != nil {
+		t.Fatalf("ParseContext: %v", err)
+	}
+	if ctx.Platform != "ios" || ctx.Country != "US" || *ctx.AppVersion != (Version{2, 3, 0}) ||
+		strings.Join(ctx.Segments, ",") != "vip,beta" || ctx.LoggedIn == nil || !*ctx.LoggedIn {
+		t.Errorf("ParseContext = %+v", ctx)
+	}
+
+	for name, value := range map[string]string{
+		"platform":    "web",
+		"app_version": "2.3-beta",
+		"country":     "USA",
+		"logged_in":   "yes",
+	} {
+		if _, err := ParseContext(func(n string) string {
+			if n == name {
+				return value
+			}
+			return ""
+		}); err == nil {
+			t.Errorf("ParseContext with %s=%q succeeded, want an error", name, value)
+		}
+	}
+}
@@ -9,10 +9,206 @@ package utils
 //   - text: Plain or formatted text content
 //   - image: Individual image display
 //   - spacer: Empty space for layout purposes
+//   - row, column, tabs, carousel: Containers holding child widgets (see ContainerWidgetTypes)
+//   - component: Reference to a reusable component (see ComponentWidgetType)
 var ValidWidgetTypes = map[string]bool{
 	"banner":       true,
 	"product_grid": true,
 	"text":         true,
 	"image":        true,
 	"spacer":       true,
+	"row":          true,
+	"column":       true,
+	"tabs":         true,
+	"carousel":     true,
+	"component":    true,
 }
+
+// ComponentWidgetType is the widget type for references to reusable components.
+// A component widget has a component_id and renders the component's definition;
+// it cannot have children of its own and cannot appear inside a component definition.
+const ComponentWidgetType = "component"
+
+// ContainerWidgetTypes defines the widget types that can hold child widgets.
+// The value restricts which child types are allowed; nil allows any valid type.
+//   - row: Children laid out side by side
+//   - column: Children stacked vertically
+//   - tabs: Each child is one tab
+//   - carousel: Swipeable slides of banners or images
+var ContainerWidgetTypes = map[string]map[string]bool{
+	"row":      nil,
+	"column":   nil,
+	"tabs":     nil,
+	"carousel": {"banner": true, "image": true},
+}
+
+// MaxWidgetDepth is the maximum nesting depth of the widget tree.
+// Top-level widgets are at depth 1, children of a top-level container at depth 2, and so on.
+const MaxWidgetDepth = 4
+
+// Navigation menus an item can belong to.
+//   - NavigationTabBar: bottom tab bar, limited to MaxTabBarItems items, each with an icon
+//   - NavigationDrawer: side drawer menu
+const (
+	NavigationTabBar = "tab_bar"
+	NavigationDrawer = "drawer"
+)
+
+// MaxTabBarItems is the most items a bottom tab bar can show.
+const MaxTabBarItems = 5
+
+// ThemeTokenNamespaces are the reference namespaces served by the app theme.
+// A widget config value "{{color.primary}}" references the "primary" color token.
+//   - color: Colors (or DarkColors in dark mode)
+//   - typography: text styles, referenced per field ("typography.body.font_size")
+//   - spacing: spacing sizes
+//   - radius: corner radius sizes
+var ThemeTokenNamespaces = map[string]bool{
+	"color":      true,
+	"typography": true,
+	"spacing":    true,
+	"radius":     true,
+}
+
+// BindingNamespaces are the reference namespaces served by app data rather than the theme.
+// They are resolved on page reads like theme tokens.
+//   - store: store variables, e.g. "{{store.free_shipping_threshold}}"
+//   - product: a product's fields by ID, e.g. "{{product.<id>.name}}" (see ProductBindingFields)
+var BindingNamespaces = map[string]bool{
+	"store":   true,
+	"product": true,
+}
+
+// ProductBindingFields are the product fields a "{{product.<id>.<field>}}" reference can name.
+// image_url is the URL of the product's first image.
+var ProductBindingFields = map[string]bool{
+	"sku":         true,
+	"name":        true,
+	"description": true,
+	"price_cents": true,
+	"currency":    true,
+	"in_stock":    true,
+	"image_url":   true,
+}
+
+// Store variable limits: how many variables the app can have, and the longest string value.
+const (
+	MaxStoreVariables        = 200
+	MaxStoreVariableValueLen = 1000
+)
+
+// Color schemes a page can be rendered in; dark selects Theme.DarkColors overrides.
+const (
+	ColorSchemeLight = "light"
+	ColorSchemeDark  = "dark"
+)
+
+// TranslatableWidgetFields lists, per widget type, the config fields that hold
+// user-facing text and can be translated per locale. Other types have no
+// translatable fields; component widgets are translated through their component.
+var TranslatableWidgetFields = map[string][]string{
+	"banner": {"title", "description", "alt_text"},
+	"text":   {"content"},
+	"image":  {"alt_text"},
+}
+
+// Page statuses. Drafts are never shown on public reads; a draft with publish_at
+// is published by the background publish job once that time has passed.
+const (
+	PageStatusDraft     = "draft"
+	PageStatusPublished = "published"
+)
+
+// Experiment statuses. Only running experiments assign variants; a started
+// experiment cannot return to draft.
+const (
+	ExperimentStatusDraft   = "draft"
+	ExperimentStatusRunning = "running"
+	ExperimentStatusStopped = "stopped"
+)
+
+// MaxExperimentVariants is the most variants an experiment can test.
+const MaxExperimentVariants = 10
+
+// MaxVariantWeight is the largest weight an experiment variant can have, which keeps
+// the sum of the weights from overflowing.
+const MaxVariantWeight = 10000
+
+// ProductGridWidgetType is the widget type listing catalog products. Its config names
+// the products with collection_id or product_ids, and may set sort and limit.
+const ProductGridWidgetType = "product_grid"
+
+// ProductGridSorts are the orders a product_grid can list its products in.
+//   - manual: collection order, or the order of product_ids (default)
+//   - price_asc, price_desc: by price
+//   - name: alphabetically by name
+//   - newest: most recently created first
+var ProductGridSorts = map[string]bool{
+	"manual":     true,
+	"price_asc":  true,
+	"price_desc": true,
+	"name":       true,
+	"newest":     true,
+}
+
+// Product grid limits: how many products a grid lists by default and at most,
+// and how many products it can name in product_ids.
+const (
+	DefaultProductGridLimit = 20
+	MaxProductGridLimit     = 100
+	MaxProductGridIDs       = 100
+)
+
+// MaxCollectionProducts is the most products a collection can hold.
+const MaxCollectionProducts = 500
+
+// MaxProductImages is the most images a product can have.
+const MaxProductImages = 10
+
+// MaxRedirectHops is the most redirects followed when resolving an app path.
+const MaxRedirectHops = 5
+
+// LinkConfigKeys are the widget config keys holding a link target. Values starting
+// with "/" are app paths checked by the link checker; other values are external URLs.
+var LinkConfigKeys = []string{"link", "target_route"}
+
+// ClientContextHeaders maps each client context query parameter of the public reads to
+// the header that can carry it instead. Query parameters win when both are sent.
+var ClientContextHeaders = map[string]string{
+	"platform":    "X-Platform",
+	"app_version": "X-App-Version",
+	"country":     "X-Country",
+	"segments":    "X-Segments",
+	"logged_in":   "X-Logged-In",
+}
+
+// UserIDHeader carries the user ID bucketed into experiment variants on public reads,
+// when the user_id query parameter is not sent.
+const UserIDHeader = "X-User-ID"
+
+// LintCategories group the lint rules; lint requests can run a single category.
+var LintCategories = map[string]bool{
+	"layout":        true,
+	"content":       true,
+	"links":         true,
+	"accessibility": true,
+}
+
+// Accessibility thresholds: the WCAG 2.1 AA contrast ratios for normal and large text,
+// the font sizes (in points) from which text counts as large, regular or bold, and the
+// smallest font size considered readable.
+const (
+	MinContrastRatio          = 4.5
+	MinLargeTextContrastRatio = 3.0
+	LargeTextSize             = 24
+	LargeBoldTextSize         = 18.66
+	MinFontSize               = 12
+)
+
+// Lint severities, from most to least serious. A rule whose severity is set to
+// LintSeverityOff in the lint settings is not run.
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+	LintSeverityOff     = "off"
+)
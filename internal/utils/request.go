@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// uuidPattern matches a UUID in its canonical hyphenated form, in either case.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IsUUID reports whether s is a UUID. IDs from request bodies are checked with it before
+// they reach a ::uuid cast, so that a malformed ID is a validation error, not a database error.
+func IsUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// MaxBodyBytes is the largest request body DecodeJSON will read.
+// Set from configuration at startup (default: 1 MiB).
+var MaxBodyBytes int64 = 1 << 20
+
+// DecodeJSON strictly decodes a JSON request body into dst.
+// The request is rejected, with the error response already written, when:
+//   - Content-Type is not application/json (415 UNSUPPORTED_MEDIA_TYPE)
+//   - the body is larger than MaxBodyBytes (413 PAYLOAD_TOO_LARGE)
+//   - the body is empty, malformed, contains fields dst doesn't declare,
+//     has a value of the wrong type, or has data after the JSON value (400 INVALID_JSON)
+//
+// Returns true if dst was decoded successfully.
+// Example: if !utils.DecodeJSON(w, r, &req) { return }
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		SendError(w, 415, "UNSUPPORTED_MEDIA_TYPE", "Content-Type must be application/json")
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			SendError(w, 413, "PAYLOAD_TOO_LARGE", fmt.Sprintf("Request body must not exceed %d bytes", maxBytesErr.Limit))
+			return false
+		}
+		SendError(w, 400, "INVALID_JSON", "Invalid request body: "+describeDecodeError(err))
+		return false
+	}
+
+	// Anything after the first JSON value (even another valid value) is rejected
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			SendError(w, 413, "PAYLOAD_TOO_LARGE", fmt.Sprintf("Request body must not exceed %d bytes", maxBytesErr.Limit))
+			return false
+		}
+		SendError(w, 400, "INVALID_JSON", "Invalid request body: unexpected data after JSON object")
+		return false
+	}
+
+	return true
+}
+
+// describeDecodeError turns an encoding/json error into a message suitable for API clients.
+func describeDecodeError(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return "body must not be empty"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "body is truncated"
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("malformed JSON at byte %d", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return fmt.Sprintf("field %q must be of type %s (got %s)", typeErr.Field, typeErr.Type, typeErr.Value)
+		}
+		return fmt.Sprintf("body must be a JSON object (got %s)", typeErr.Value)
+	}
+
+	// encoding/json has no typed error for unknown fields; its message is
+	// already client-friendly, e.g. `json: unknown field "id"`
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
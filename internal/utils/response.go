@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -9,8 +11,9 @@ import (
 // All error responses follow this structure with error code and human-readable message.
 type ErrorResponse struct {
 	Error struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code    string      `json:"code"`
+		Message string      `json:"message"`
+		Details interface{} `json:"details,omitempty"`
 	} `json:"error"`
 }
 
@@ -18,12 +21,20 @@ type ErrorResponse struct {
 // Sets appropriate HTTP status code and returns error details in JSON format.
 // Example: SendError(w, 404, "NOT_FOUND", "Page not found")
 func SendError(w http.ResponseWriter, status int, code, message string) {
+	SendErrorDetails(w, status, code, message, nil)
+}
+
+// SendErrorDetails writes a formatted error response like SendError, with details
+// describing the error further (omitted when nil).
+// Example: SendErrorDetails(w, 409, "CONFLICT", "Page is linked from other content", links)
+func SendErrorDetails(w http.ResponseWriter, status int, code, message string, details interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
 	var errResp ErrorResponse
 	errResp.Error.Code = code
 	errResp.Error.Message = message
+	errResp.Error.Details = details
 
 	json.NewEncoder(w).Encode(errResp)
 }
@@ -37,3 +48,19 @@ func SendJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
+
+// SendContextError writes an error response if err was caused by the request context ending.
+// A cancelled request (client disconnected) gets 499 and a request that exceeded its
+// deadline gets 504. Returns true if a response was written, false otherwise.
+// Example: if utils.SendContextError(w, err) { return }
+func SendContextError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled):
+		SendError(w, 499, "CLIENT_CLOSED_REQUEST", "Request was cancelled by the client")
+		return true
+	case errors.Is(err, context.DeadlineExceeded):
+		SendError(w, 504, "TIMEOUT", "Request timed out")
+		return true
+	}
+	return false
+}
@@ -1,127 +1,140 @@
 // Package main provides the entry point for the AppDrop API server.
-// It sets up HTTP routing, middleware, and database connections.
+// It loads configuration, sets up HTTP routing, middleware, and database connections.
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"appdrop-api/internal/config"
 	"appdrop-api/internal/db"
 	"appdrop-api/internal/handlers"
 	"appdrop-api/internal/middleware"
-
-	"github.com/joho/godotenv"
+	"appdrop-api/internal/router"
+	"appdrop-api/internal/services"
+	"appdrop-api/internal/storage"
+	"appdrop-api/internal/utils"
 )
 
 // main initializes and starts the AppDrop API server.
 // It performs the following:
-// 1. Loads environment variables from .env file
-// 2. Establishes PostgreSQL database connection
+// 1. Loads and validates configuration from defaults, config file, .env and environment
+// 2. Establishes PostgreSQL database connection and opens asset storage
 // 3. Registers HTTP route handlers for all endpoints
-// 4. Applies request logging middleware
-// 5. Starts the HTTP server on the configured port
+// 4. Applies CORS and request logging middleware
+// 5. Starts the scheduled-publish and image variant jobs and the HTTP server on the configured port
+// 6. On SIGINT/SIGTERM, fails readiness, drains in-flight requests, stops the jobs and closes the pool
 func main() {
-	// Load environment variables from .env file for configuration
-	godotenv.Load()
-
-	// Initialize database connection pool using configured DATABASE_URL
-	db.ConnectDB()
-
-	// Health check endpoint - validates API and database connectivity
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "API + DB working")
-	})
-
-	// Pages list and creation endpoints
-	// GET /pages - List all pages
-	// POST /pages - Create a new page
-	http.HandleFunc("/pages", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			handlers.GetPagesHandler(w, r)
-			return
-		}
-		if r.Method == http.MethodPost {
-			handlers.CreatePageHandler(w, r)
-			return
-		}
-		http.NotFound(w, r)
-	})
-
-	// Pages detail, update, delete, and widget operations
-	// GET /pages/:id - Get page with all its widgets
-	// PUT /pages/:id - Update page details
-	// DELETE /pages/:id - Delete page and all its widgets
-	// POST /pages/:id/widgets - Create new widget on page
-	// POST /pages/:id/widgets/reorder - Reorder widgets on page
-
-	http.HandleFunc("/pages/", func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
-
-		// Handle POST /pages/:id/widgets/reorder
-		// Reorders all widgets on a specific page by updating their positions
-		if r.Method == http.MethodPost && len(path) > len("/pages/") {
-			if len(path) >= len("/widgets/reorder") && path[len(path)-len("/widgets/reorder"):] == "/widgets/reorder" {
-				handlers.ReorderWidgetsHandler(w, r)
-				return
-			}
-			// Handle POST /pages/:id/widgets
-			// Creates a new widget on the specified page
-			if len(path) >= len("/widgets") && path[len(path)-len("/widgets"):] == "/widgets" {
-				handlers.CreateWidgetHandler(w, r)
-				return
-			}
-		}
-
-		// Handle GET /pages/:id
-		// Retrieves page details including all associated widgets
-		if r.Method == http.MethodGet {
-			handlers.GetPageByIDHandler(w, r)
-			return
-		}
-
-		// Handle PUT /pages/:id
-		// Updates page name, route, or home page status
-		if r.Method == http.MethodPut {
-			handlers.UpdatePageHandler(w, r)
-			return
-		}
-
-		// Handle DELETE /pages/:id
-		// Deletes page and cascades delete to all its widgets
-		if r.Method == http.MethodDelete {
-			handlers.DeletePageHandler(w, r)
-			return
-		}
-
-		http.NotFound(w, r)
-	})
-
-	// Widget update and delete endpoints
-	// PUT /widgets/:id - Update widget configuration or position
-	// DELETE /widgets/:id - Delete a widget from its page
-
-	http.HandleFunc("/widgets/", func(w http.ResponseWriter, r *http.Request) {
-		// Handle PUT /widgets/:id
-		// Updates widget configuration, position, or type
-		if r.Method == http.MethodPut {
-			handlers.UpdateWidgetHandler(w, r)
-			return
+	// Load configuration; exit with every validation problem listed if it is invalid
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Initialize database connection pool using configured DATABASE_URL and pool sizing
+	if err := db.ConnectDB(cfg.Database); err != nil {
+		fmt.Fprintln(os.Stderr, "database:", err)
+		os.Exit(1)
+	}
+
+	// Apply configured settings to the handler layer
+	handlers.ReadinessTimeout = cfg.Health.CheckTimeout
+	utils.MaxBodyBytes = cfg.Server.MaxBodyBytes
+	services.DefaultPreviewTTL = cfg.Preview.DefaultTTL
+	services.MaxPreviewTTL = cfg.Preview.MaxTTL
+	services.PreviewSecret = []byte(cfg.Preview.Secret)
+	if cfg.Preview.Secret == "" {
+		// Without a configured secret, links only work until the process restarts
+		services.PreviewSecret = make([]byte, 32)
+		if _, err := rand.Read(services.PreviewSecret); err != nil {
+			// An all-zero key would let anyone forge preview links
+			fmt.Fprintln(os.Stderr, "preview secret:", err)
+			os.Exit(1)
 		}
-		// Handle DELETE /widgets/:id
-		// Removes a widget from its page
-		if r.Method == http.MethodDelete {
-			handlers.DeleteWidgetHandler(w, r)
-			return
+		fmt.Fprintln(os.Stderr, "warning: PREVIEW_SECRET is not set; preview links will stop working on restart")
+	}
+
+	// Store uploaded media assets on the local filesystem
+	assets, err := storage.NewLocalStore(cfg.Storage.Dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "storage:", err)
+		os.Exit(1)
+	}
+	services.Assets = assets
+	services.MaxUploadBytes = cfg.Storage.MaxUploadBytes
+	services.VariantWidths = cfg.Storage.VariantWidths
+	services.BlockLinkedPageDeletes = cfg.Features.BlockLinkedPageDeletes
+	services.AppleAppIDs = cfg.DeepLinks.AppleAppIDs()
+	services.AndroidPackage = cfg.DeepLinks.AndroidPackage
+	services.AndroidFingerprints = cfg.DeepLinks.AndroidFingerprints
+
+	// Register every API route (see internal/router for the full route table)
+	mux := router.New()
+
+	// Start HTTP server with middleware and configured timeouts
+	// Request logging can be switched off via FEATURE_REQUEST_LOGGING
+	// Every request gets a deadline that is propagated down to database queries
+	var handler http.Handler = middleware.Timeout(cfg.Server.RequestTimeout)(mux)
+	if cfg.Features.RequestLogging {
+		handler = middleware.Logger(handler)
+	}
+	handler = middleware.CORS(cfg.CORS.AllowedOrigins)(handler)
+
+	server := &http.Server{
+		Addr:         cfg.Addr(),
+		Handler:      handler,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	// Publish scheduled drafts in the background until shutdown
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	publishDone := services.StartPublishJob(jobCtx, cfg.Jobs.PublishInterval)
+
+	// Generate resized variants of uploaded images in the background
+	variantDone := services.StartVariantJob(jobCtx, cfg.Jobs.VariantInterval)
+
+	// Serve until the process is asked to stop
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		fmt.Println("Server running on " + server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintln(os.Stderr, "server:", err)
+			os.Exit(1)
 		}
-		http.NotFound(w, r)
-	})
-
-	// Start HTTP server with logging middleware
-	// Read port from environment variable (default: 8080)
-	// Apply request logging middleware to track all API calls
-	port := os.Getenv("PORT")
-	fmt.Println("Server running on :" + port)
-	handler := middleware.Logger(http.DefaultServeMux)
-	http.ListenAndServe(":"+port, handler)
+	case <-stop:
+	}
+
+	// Graceful shutdown: fail readiness first so load balancers stop sending
+	// traffic, then let in-flight requests finish before closing the pool
+	fmt.Println("Shutting down")
+	services.BeginShutdown()
+	time.Sleep(cfg.Server.DrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "shutdown:", err)
+	}
+	stopJobs()
+	<-publishDone
+	<-variantDone
+	db.Pool.Close()
 }
@@ -0,0 +1,10 @@
+-- Tracks which migrations have been applied so the API can verify at
+-- readiness time that the database schema matches the code.
+-- Run migrations in order: schema.sql (version 1), then 002_*, 003_*, ...
+
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version) VALUES (1), (2) ON CONFLICT DO NOTHING;
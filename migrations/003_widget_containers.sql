@@ -0,0 +1,9 @@
+-- Container widgets: a widget may be nested inside another widget (row,
+-- column, tabs, carousel). Deleting a container deletes its children.
+
+ALTER TABLE widgets
+    ADD COLUMN parent_id UUID REFERENCES widgets(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_widgets_parent_id ON widgets(parent_id);
+
+INSERT INTO schema_migrations (version) VALUES (3);
@@ -0,0 +1,19 @@
+-- Reusable components: a named widget (or widget subtree) that pages reference
+-- through widgets of type 'component'. Editing a component changes every page
+-- that references it. A component cannot be deleted while it is referenced.
+
+CREATE TABLE components (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    root JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+ALTER TABLE widgets
+    ADD COLUMN component_id UUID REFERENCES components(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_widgets_component_id ON widgets(component_id);
+
+INSERT INTO schema_migrations (version) VALUES (4);
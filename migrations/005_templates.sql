@@ -0,0 +1,72 @@
+-- Page templates: a named, categorised set of top-level widget definitions
+-- (with nested children) that new pages can be created from. Built-in starter
+-- templates are seeded below and cannot be deleted through the API.
+
+CREATE TABLE templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT UNIQUE NOT NULL,
+    category TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    preview_image_url TEXT NOT NULL DEFAULT '',
+    widgets JSONB NOT NULL DEFAULT '[]',
+    is_builtin BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_templates_category ON templates(category);
+
+INSERT INTO templates (name, category, description, preview_image_url, widgets, is_builtin) VALUES
+(
+    'Storefront Home', 'home',
+    'Hero banner followed by a featured product grid.',
+    'https://cdn.appdrop.dev/templates/storefront-home.png',
+    '[
+        {"type": "banner", "config": {"image_url": "https://cdn.appdrop.dev/placeholders/hero.jpg", "title": "Welcome to our store", "description": "Discover this season''s collection"}},
+        {"type": "text", "config": {"content": "Featured products", "font_size": "20", "color": "#111111"}},
+        {"type": "product_grid", "config": {"columns": 2, "items_per_page": 6}}
+    ]',
+    true
+),
+(
+    'Promotions', 'marketing',
+    'Carousel of promotional banners above a sale product grid.',
+    'https://cdn.appdrop.dev/templates/promotions.png',
+    '[
+        {"type": "carousel", "config": {"autoplay": true, "interval_ms": 4000}, "children": [
+            {"type": "banner", "config": {"image_url": "https://cdn.appdrop.dev/placeholders/promo-1.jpg", "title": "Summer sale", "description": "Up to 50% off"}},
+            {"type": "banner", "config": {"image_url": "https://cdn.appdrop.dev/placeholders/promo-2.jpg", "title": "New arrivals", "description": "Fresh styles every week"}}
+        ]},
+        {"type": "spacer", "config": {"height": "16"}},
+        {"type": "product_grid", "config": {"columns": 2, "items_per_page": 10}}
+    ]',
+    true
+),
+(
+    'About Us', 'content',
+    'Brand image with a short story and contact details.',
+    'https://cdn.appdrop.dev/templates/about-us.png',
+    '[
+        {"type": "image", "config": {"url": "https://cdn.appdrop.dev/placeholders/team.jpg", "alt_text": "Our team", "width": "100%"}},
+        {"type": "text", "config": {"content": "Our story", "font_size": "22", "color": "#111111"}},
+        {"type": "text", "config": {"content": "Tell customers who you are and what you stand for.", "font_size": "14", "color": "#444444"}},
+        {"type": "spacer", "config": {"height": "24"}},
+        {"type": "text", "config": {"content": "Contact us at hello@example.com", "font_size": "14", "color": "#444444"}}
+    ]',
+    true
+),
+(
+    'Category Showcase', 'catalog',
+    'Two side-by-side category images above a product grid.',
+    'https://cdn.appdrop.dev/templates/category-showcase.png',
+    '[
+        {"type": "row", "config": {"spacing": "8", "alignment": "stretch"}, "children": [
+            {"type": "image", "config": {"url": "https://cdn.appdrop.dev/placeholders/category-1.jpg", "alt_text": "Women", "width": "50%"}},
+            {"type": "image", "config": {"url": "https://cdn.appdrop.dev/placeholders/category-2.jpg", "alt_text": "Men", "width": "50%"}}
+        ]},
+        {"type": "product_grid", "config": {"columns": 3, "items_per_page": 12}}
+    ]',
+    true
+);
+
+INSERT INTO schema_migrations (version) VALUES (5);
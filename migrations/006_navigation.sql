@@ -0,0 +1,21 @@
+-- App navigation: ordered items of the bottom tab bar and the side drawer.
+-- Each item targets either a page or an external URL. When a target page is
+-- deleted, page_id is cleared: tab bar items are then removed by the API, and
+-- drawer items are kept and reported as broken until they are retargeted.
+
+CREATE TABLE navigation_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    menu TEXT NOT NULL CHECK (menu IN ('tab_bar', 'drawer')),
+    position INT NOT NULL,
+    label TEXT NOT NULL,
+    icon TEXT NOT NULL DEFAULT '',
+    page_id UUID REFERENCES pages(id) ON DELETE SET NULL,
+    url TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CHECK (page_id IS NULL OR url IS NULL)
+);
+
+CREATE INDEX idx_navigation_items_menu ON navigation_items(menu, position);
+CREATE INDEX idx_navigation_items_page_id ON navigation_items(page_id);
+
+INSERT INTO schema_migrations (version) VALUES (6);
@@ -0,0 +1,35 @@
+-- App theme: design tokens (colors, typography, spacing, corner radius and
+-- dark-mode color overrides) that widget configs reference as {{color.primary}}.
+-- There is exactly one theme per app, so the table holds a single row.
+
+CREATE TABLE theme (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    tokens JSONB NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO theme (tokens) VALUES ('{
+    "colors": {
+        "primary": "#1A73E8",
+        "secondary": "#FF6D00",
+        "background": "#FFFFFF",
+        "surface": "#F5F5F5",
+        "text": "#111111",
+        "text_muted": "#666666"
+    },
+    "typography": {
+        "heading": {"font_family": "Inter", "font_size": 22, "font_weight": 700, "line_height": 1.3},
+        "body": {"font_family": "Inter", "font_size": 14, "font_weight": 400, "line_height": 1.5},
+        "caption": {"font_family": "Inter", "font_size": 12, "font_weight": 400, "line_height": 1.4}
+    },
+    "spacing": {"xs": 4, "sm": 8, "md": 16, "lg": 24, "xl": 32},
+    "radius": {"none": 0, "sm": 4, "md": 8, "lg": 16, "full": 9999},
+    "dark_colors": {
+        "background": "#121212",
+        "surface": "#1E1E1E",
+        "text": "#F5F5F5",
+        "text_muted": "#AAAAAA"
+    }
+}');
+
+INSERT INTO schema_migrations (version) VALUES (7);
@@ -0,0 +1,32 @@
+-- Localization: the app's locales (exactly one is the default) and per-locale
+-- translations of page names and translatable widget config fields. Content
+-- stored on pages and widgets themselves is in the default locale; translations
+-- are only stored for the other locales and fall back to it when missing.
+
+CREATE TABLE locales (
+    code TEXT PRIMARY KEY,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_locales_single_default ON locales(is_default) WHERE is_default;
+
+INSERT INTO locales (code, is_default) VALUES ('en', true);
+
+CREATE TABLE page_translations (
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL REFERENCES locales(code) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (page_id, locale)
+);
+
+CREATE TABLE widget_translations (
+    widget_id UUID NOT NULL REFERENCES widgets(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL REFERENCES locales(code) ON DELETE CASCADE,
+    fields JSONB NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (widget_id, locale)
+);
+
+INSERT INTO schema_migrations (version) VALUES (8);
@@ -0,0 +1,21 @@
+-- Scheduled visibility: pages and widgets can be limited to a time window
+-- (visible_from inclusive, visible_until exclusive), evaluated on public reads.
+-- Pages can be drafts, which are never public; a draft with publish_at is
+-- published by the background publish job once that time has passed.
+-- Existing pages stay published.
+
+ALTER TABLE pages
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'published')),
+    ADD COLUMN publish_at TIMESTAMPTZ,
+    ADD COLUMN visible_from TIMESTAMPTZ,
+    ADD COLUMN visible_until TIMESTAMPTZ,
+    ADD CHECK (visible_until IS NULL OR visible_from IS NULL OR visible_until > visible_from);
+
+ALTER TABLE widgets
+    ADD COLUMN visible_from TIMESTAMPTZ,
+    ADD COLUMN visible_until TIMESTAMPTZ,
+    ADD CHECK (visible_until IS NULL OR visible_from IS NULL OR visible_until > visible_from);
+
+CREATE INDEX idx_pages_publish_at ON pages(publish_at) WHERE status = 'draft';
+
+INSERT INTO schema_migrations (version) VALUES (9);
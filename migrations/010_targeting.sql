@@ -0,0 +1,7 @@
+-- Audience targeting: a widget can carry a rule (see internal/targeting) that is
+-- evaluated against the client context on public reads. Widgets without a rule
+-- are shown to everyone.
+
+ALTER TABLE widgets ADD COLUMN targeting TEXT;
+
+INSERT INTO schema_migrations (version) VALUES (10);
@@ -0,0 +1,28 @@
+-- A/B experiments on page layouts. Each experiment targets one page and splits
+-- public readers between variants by traffic weight; a variant either overrides
+-- widgets on the page or swaps in the widgets of an alternate page version.
+-- Variants are stored as JSONB: [{ "key", "weight", "page_id", "overrides" }].
+-- Only one experiment per page can be running at a time.
+
+CREATE TABLE experiments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE,
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'running', 'stopped')),
+    variants JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_experiments_running_page ON experiments(page_id) WHERE status = 'running';
+
+-- The first time each user was shown a variant of an experiment
+CREATE TABLE experiment_exposures (
+    experiment_id UUID NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    variant TEXT NOT NULL,
+    exposed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (experiment_id, user_id)
+);
+
+INSERT INTO schema_migrations (version) VALUES (11);
@@ -0,0 +1,16 @@
+-- Preview links share a non-public view of a page (usually a draft) for a limited
+-- time. The token handed out is signed with the server's preview secret and
+-- carries the link ID and expiry; this table lets links be listed and revoked.
+
+CREATE TABLE preview_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    label TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_preview_links_page ON preview_links(page_id);
+
+INSERT INTO schema_migrations (version) VALUES (12);
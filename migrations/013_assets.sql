@@ -0,0 +1,19 @@
+-- Media assets: uploaded images kept in blob storage (see internal/storage) and
+-- referenced from widget configs by asset_id. The row records what was stored so
+-- clients can lay images out before downloading them.
+
+CREATE TABLE assets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    filename TEXT NOT NULL,
+    mime_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    checksum TEXT NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_assets_checksum ON assets(checksum);
+
+INSERT INTO schema_migrations (version) VALUES (13);
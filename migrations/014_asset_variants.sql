@@ -0,0 +1,25 @@
+-- Resized variants of uploaded images, generated in the background so small
+-- screens can download an image close to the size they display. Assets wait in
+-- variants_status 'pending' until the variant job claims them ('processing'),
+-- then end up 'ready' or 'failed'.
+
+ALTER TABLE assets
+    ADD COLUMN variants_status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (variants_status IN ('pending', 'processing', 'ready', 'failed')),
+    ADD COLUMN variants_updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX idx_assets_variants_pending ON assets(created_at)
+    WHERE variants_status IN ('pending', 'processing');
+
+CREATE TABLE asset_variants (
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    mime_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, width)
+);
+
+INSERT INTO schema_migrations (version) VALUES (14);
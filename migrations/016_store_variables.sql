@@ -0,0 +1,13 @@
+-- Store variables: app-level values such as a free shipping threshold or a support
+-- phone number that widget configs reference as {{store.free_shipping_threshold}}.
+-- There is one set of variables per app, so the table holds a single row.
+
+CREATE TABLE store_variables (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    variables JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO store_variables (variables) VALUES ('{}');
+
+INSERT INTO schema_migrations (version) VALUES (16);
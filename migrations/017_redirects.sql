@@ -0,0 +1,19 @@
+-- Redirects: old or alternative app routes that lead to a page (page_id) or to another
+-- route (to_route). Changing a page's route records the old route as an automatic
+-- redirect to the page, so existing deep links keep working. Page routes take
+-- precedence over redirects; redirects to a deleted page are removed with it.
+
+CREATE TABLE redirects (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    from_route TEXT UNIQUE NOT NULL,
+    page_id UUID REFERENCES pages(id) ON DELETE CASCADE,
+    to_route TEXT,
+    automatic BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK ((page_id IS NULL) <> (to_route IS NULL))
+);
+
+CREATE INDEX idx_redirects_page_id ON redirects(page_id);
+
+INSERT INTO schema_migrations (version) VALUES (17);
@@ -0,0 +1,14 @@
+-- Lint settings: per-rule severity overrides for the page linter, and whether lint
+-- errors keep drafts from being published. There is one set of settings per app,
+-- so the table holds a single row.
+
+CREATE TABLE lint_settings (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    severities JSONB NOT NULL DEFAULT '{}',
+    publish_gate BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO lint_settings DEFAULT VALUES;
+
+INSERT INTO schema_migrations (version) VALUES (18);